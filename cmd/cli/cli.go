@@ -3,16 +3,42 @@ package main
 import (
 	"bufio"
 	"context"
+	"encoding/csv"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 
+	"github.com/mateuszkrasucki/calculator/pkg/bc"
 	calculator "github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
 	rpn "github.com/mateuszkrasucki/calculator/pkg/reversepolish"
 )
 
+var (
+	operationFlag = flag.String("c", "", "Operation to calculate")
+	explainFlag   = flag.Bool("explain", false, "Explain how operation is lexed, parsed and calculated")
+	dotFlag       = flag.Bool("dot", false, "Print operation tree in Graphviz DOT format")
+	tsvFlag       = flag.Bool("tsv", false, "Read columns separated with tabs instead of commas")
+	bcFlag        = flag.Bool("bc", false, "Run bc program read from stdin or given with -c flag")
+	mathLibFlag   = flag.Bool("l", false, "Load bc math library")
+	modeFlag      = flag.String("mode", "", "Calculate with numbers of the mode instead of float64: bigfloat, rational, decimal, complex, interval, sigfig, units or time")
+	precisionFlag = flag.Uint("precision", 0, "Precision of numbers of the mode in bits")
+	digitsFlag    = flag.Uint("digits", 0, "Precision of numbers of the mode in significant decimal digits")
+	roundingFlag  = flag.String("rounding", "", "Rounding of numbers of the mode: half-even, half-up, down, up, floor or ceiling")
+	strictFlag    = flag.Bool("strict", false, "Fail instead of approximating results of exact modes")
+	scaleFlag     = flag.Int("scale", -1, "Number of fractional digits of numbers of fixed-point modes, negative selects default scale")
+	specialFlag   = flag.String("special-values", string(calculator.IEEESpecialValues), "Policy for results which are not finite numbers: ieee prints Infinity and NaN, strict fails")
+	ratesFlag     = flag.String("rates", "", "File of exchange rates, JSON or CSV, making currency codes units of units mode")
+	zoneFlag      = flag.String("zone", "", "Time zone of now, today and timestamps without offset of time mode, e.g. Europe/Warsaw, local time zone by default")
+	timeFmtFlag   = flag.String("time-format", "", "Format of dates and timestamps of time mode: iso, rfc1123, unix or a layout of Go package time")
+)
+
 func getInput() string {
 	input, err := readStdin()
 	if err != nil {
@@ -45,21 +71,158 @@ func readStdin() (string, error) {
 	return string(b), nil
 }
 
-func readFlag() (string, error) {
-	input := flag.String("c", "", "Operation to calculate")
-	flag.Parse()
+// getProgram returns whole stdin if it is a pipe, otherwise program given with -c flag
+func getProgram() string {
+	fi, err := os.Stdin.Stat()
+	if err != nil || fi.Mode()&os.ModeNamedPipe == 0 {
+		return *operationFlag
+	}
+
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return ""
+	}
 
-	return *input, nil
+	return string(b)
+}
+
+func readFlag() (string, error) {
+	return *operationFlag, nil
 }
 
 func main() {
+	args := os.Args[1:]
+
+	// fmt subcommand formats operation instead of calculating it, equiv compares two operations given as arguments,
+	// columns calculates operation given with -c flag for every row of CSV read from stdin
+	subcommand := ""
+	if len(args) > 0 && (args[0] == "fmt" || args[0] == "equiv" || args[0] == "columns") {
+		subcommand, args = args[0], args[1:]
+	}
+
+	flag.CommandLine.Parse(args)
+
+	switch {
+	case subcommand == "fmt":
+		format(getInput())
+	case subcommand == "equiv":
+		equivalent(flag.Args())
+	case subcommand == "columns":
+		columns(*operationFlag, os.Stdin)
+	case *bcFlag:
+		runBC(getProgram(), *mathLibFlag)
+	case *explainFlag || *dotFlag:
+		explain(getInput())
+	default:
+		calculate(getInput())
+	}
+}
+
+func calculate(input string) {
+	specialValues, err := calculator.ParseSpecialValues(*specialFlag)
+	if err != nil {
+		panic(err)
+	}
+
+	options := []calculator.Option{calculator.WithSpecialValues(specialValues), calculator.WithModeParser(calculator.TimeMode, rpn.ParseTime)}
+	if *ratesFlag != "" {
+		rates, err := calculator.LoadRates(*ratesFlag)
+		if err != nil {
+			panic(err)
+		}
+		options = append(options, calculator.WithRates(rates))
+	}
+
 	var c calculator.Calculator
 	{
-		c = calculator.New(rpn.ParseInfix)
+		c = calculator.New(rpn.ParseInfix, options...)
 		c = calculator.ValidateMiddleware()(c)
 	}
 
-	result, err := c.Calculate(context.Background(), getInput())
+	if *modeFlag != "" {
+		calculateMode(c, input)
+		return
+	}
+
+	result, err := c.Calculate(context.Background(), input)
+
+	if err != nil {
+		panic(err)
+	}
+
+	lint(input, lexer.Lex)
+
+	if special := calculator.FormatSpecial(result); special != "" {
+		fmt.Println(special)
+		return
+	}
+
+	fmt.Println(result)
+}
+
+// calculateMode prints result of operation calculated in mode given with flags
+func calculateMode(c calculator.Calculator, input string) {
+	mode := calculator.Mode{Name: *modeFlag, Precision: *precisionFlag, Digits: *digitsFlag, Rounding: *roundingFlag, Strict: *strictFlag, Zone: *zoneFlag, TimeFormat: *timeFmtFlag}
+	if *scaleFlag >= 0 {
+		scale := uint(*scaleFlag)
+		mode.Scale = &scale
+	}
+
+	value, err := c.(calculator.ModeCalculator).CalculateMode(context.Background(), input, mode)
+
+	if err != nil {
+		panic(err)
+	}
+
+	// operations of time mode may contain dates and durations, e.g. 3h 20m * 4
+	lex := lexer.Lex
+	if mode.Name == calculator.TimeMode {
+		lex = lexer.LexTime
+	}
+	lint(input, lex)
+
+	if fraction, ok := value.(calculator.FractionValue); ok && fraction.Fraction() != "" {
+		fmt.Println(fraction.Fraction())
+		return
+	}
+
+	fmt.Println(value)
+}
+
+// lint prints warnings about the operation lexed with lex to stderr, the operation is already calculated
+// so linting errors are printed too instead of failing
+func lint(input string, lex func(string) lexer.Lexer) {
+	if strings.TrimSpace(input) == "" {
+		return
+	}
+
+	warnings, err := rpn.Lint(context.Background(), lex(input))
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "linting failed: %v\n", err)
+		return
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w.Message)
+	}
+}
+
+// runBC prints output of bc program, output written before an error is printed too
+func runBC(program string, mathLib bool) {
+	output, err := bc.NewInterpreter().Run(context.Background(), program, mathLib)
+
+	fmt.Print(output)
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func format(input string) {
+	f := calculator.NewFormatter(calculator.Parsers{calculator.InfixNotation: rpn.ParseInfix}, rpn.Format)
+
+	result, err := f.Format(context.Background(), calculator.InfixNotation, input)
 
 	if err != nil {
 		panic(err)
@@ -67,3 +230,188 @@ func main() {
 
 	fmt.Println(result)
 }
+
+func explain(input string) {
+	// empty operations are calculated as 0, so they are explained as 0 too
+	if strings.TrimSpace(input) == "" {
+		input = "0"
+	}
+
+	e := rpn.NewExplainer(rpn.Lexers{calculator.InfixNotation: lexer.Lex})
+
+	explanation, err := e.Explain(context.Background(), calculator.InfixNotation, input)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if !*explainFlag {
+		fmt.Print(explanation.Dot)
+		return
+	}
+
+	tokens := []string{}
+	for _, t := range explanation.Tokens {
+		tokens = append(tokens, fmt.Sprintf("%s(%s)", t.Type, t.Value))
+	}
+
+	fmt.Printf("Tokens:  %s\n", strings.Join(tokens, " "))
+	fmt.Printf("Postfix: %s\n", strings.Join(explanation.Postfix, " "))
+	fmt.Printf("Tree:\n%s", explanation.Tree)
+	fmt.Println("Steps:")
+	for k, s := range explanation.Steps {
+		if s.Action == "push" {
+			fmt.Printf("%3d. push %v, stack: %v\n", k+1, s.Result, s.Stack)
+			continue
+		}
+
+		fmt.Printf("%3d. %s %s %v = %v, stack: %v\n", k+1, s.Action, s.Item, s.Operands, s.Result, s.Stack)
+	}
+	fmt.Printf("Result:  %v\n", explanation.Result)
+
+	if *dotFlag {
+		fmt.Printf("DOT:\n%s", explanation.Dot)
+	}
+}
+
+func equivalent(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: equiv <operation> <operation>")
+		os.Exit(2)
+	}
+
+	c := rpn.NewEquivalenceChecker(rpn.Lexers{calculator.InfixNotation: lexer.Lex})
+
+	equivalence, err := c.Equivalent(context.Background(), calculator.InfixNotation, args[0], args[1])
+
+	if err != nil {
+		panic(err)
+	}
+
+	if equivalence.Equivalent {
+		fmt.Printf("equivalent (%s)\n", equivalence.Method)
+		return
+	}
+
+	fmt.Printf("not equivalent (%s)\n", equivalence.Method)
+	if ce := equivalence.Counterexample; ce != nil {
+		bindings := []string{}
+		for name, value := range ce.Bindings {
+			bindings = append(bindings, fmt.Sprintf("%s=%v", name, value))
+		}
+		sort.Strings(bindings)
+		fmt.Printf("counterexample: %s: %v != %v\n", strings.Join(bindings, " "), ce.First, ce.Second)
+	}
+	os.Exit(1)
+}
+
+// columns reads CSV with header naming variables and prints column of results, cells of rows which
+// could not be calculated are left empty and their errors are printed to stderr. Rows are read and
+// calculated in batches, so input of any size is streamed
+func columns(input string, r io.Reader) {
+	program, err := rpn.CompileColumns(context.Background(), input)
+
+	if err != nil {
+		panic(err)
+	}
+
+	// rows of any length are read, missing cells are reported as errors of their rows
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	if *tsvFlag {
+		reader.Comma = '\t'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		fmt.Fprintln(os.Stderr, "columns: missing header")
+		os.Exit(2)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	indices := map[string]int{}
+	for _, name := range program.Variables() {
+		indices[name] = -1
+		for k, h := range header {
+			if strings.TrimSpace(h) == name {
+				indices[name] = k
+			}
+		}
+
+		if indices[name] < 0 {
+			fmt.Fprintf(os.Stderr, "columns: missing column: %s\n", name)
+			os.Exit(2)
+		}
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	fmt.Fprintln(w, "result")
+	for row, done := 0, false; !done; {
+		rows := [][]string{}
+		for len(rows) < rpn.ColumnBatchSize {
+			record, err := reader.Read()
+			if err == io.EOF {
+				done = true
+				break
+			}
+			if err != nil {
+				panic(err)
+			}
+			rows = append(rows, record)
+		}
+
+		columnsBatch(w, program, indices, rows, row)
+		row += len(rows)
+	}
+}
+
+// columnsBatch calculates and prints results of rows, first is the number of rows calculated before
+func columnsBatch(w io.Writer, program calculator.ColumnProgram, indices map[string]int, rows [][]string, first int) {
+	values := map[string][]float64{}
+	failed := make([]error, len(rows))
+
+	for name, index := range indices {
+		column := make([]float64, len(rows))
+		for k, row := range rows {
+			if index >= len(row) {
+				if failed[k] == nil {
+					failed[k] = fmt.Errorf("missing value of %s", name)
+				}
+				continue
+			}
+
+			var err error
+			column[k], err = strconv.ParseFloat(strings.TrimSpace(row[index]), 64)
+			if err != nil && failed[k] == nil {
+				failed[k] = fmt.Errorf("could not parse %s as a number", name)
+			}
+		}
+		values[name] = column
+	}
+
+	results, rowErrors, err := program.EvalColumns(context.Background(), values)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, e := range rowErrors {
+		if failed[e.Row] == nil {
+			failed[e.Row] = e.Err
+		}
+	}
+
+	for k, result := range results {
+		if failed[k] != nil {
+			fmt.Fprintf(os.Stderr, "row %d: %v\n", first+k+1, failed[k])
+			fmt.Fprintln(w)
+			continue
+		}
+
+		fmt.Fprintln(w, strconv.FormatFloat(result, 'g', -1, 64))
+	}
+}
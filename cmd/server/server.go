@@ -4,15 +4,29 @@ import (
 	"flag"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 
+	"github.com/mateuszkrasucki/calculator/pkg/bc"
 	calculator "github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/goconst"
+	"github.com/mateuszkrasucki/calculator/pkg/latex"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+	"github.com/mateuszkrasucki/calculator/pkg/natural"
 	rpn "github.com/mateuszkrasucki/calculator/pkg/reversepolish"
 )
 
 func main() {
 	addr := flag.String("addr", ":8080", "Interface and port to listen on")
+	cacheSize := flag.Int("cache-size", 1000, "Number of parsed operations to cache, 0 disables the cache")
+	cacheStatsInterval := flag.Duration("cache-stats-interval", time.Minute, "Interval of logging cache statistics, 0 disables logging")
+	specialValuesName := flag.String("special-values", string(calculator.IEEESpecialValues), "Policy for results which are not finite numbers: ieee returns Infinity and NaN, strict fails")
+	ratesPath := flag.String("rates", "", "File of exchange rates, JSON or CSV, making currency codes units of units mode")
+	ratesReloadInterval := flag.Duration("rates-reload-interval", time.Minute, "Interval of checking if the rates file was modified, 0 disables checking, SIGHUP always reloads the file")
 	flag.Parse()
 
 	// Create a single logger, which we'll use and give to other components.
@@ -23,20 +37,135 @@ func main() {
 		logger = log.With(logger, "caller", log.DefaultCaller)
 	}
 
-	// Create calculator service
+	specialValues, err := calculator.ParseSpecialValues(*specialValuesName)
+	if err != nil {
+		logger.Log("during", "configuration", "err", err)
+		os.Exit(1)
+	}
+
+	// Options shared by calculators of all notations, rates are reloaded while server is running
+	options := []calculator.Option{calculator.WithSpecialValues(specialValues)}
+	if *ratesPath != "" {
+		rates, err := calculator.NewRatesFile(*ratesPath)
+		if err != nil {
+			logger.Log("during", "configuration", "err", err)
+			os.Exit(1)
+		}
+		options = append(options, calculator.WithRates(rates))
+		go reloadRates(logger, rates, *ratesReloadInterval)
+	}
+
+	// Create calculator service caching parsed operations
 	var c calculator.Calculator
 	{
-		c = calculator.New(rpn.ParseInfix)
+		options := append([]calculator.Option{}, options...)
+		if *cacheSize > 0 {
+			cache := calculator.NewCache(*cacheSize)
+			options = append(options, calculator.WithCache(cache))
+			if *cacheStatsInterval > 0 {
+				go logCacheStats(logger, cache, *cacheStatsInterval)
+			}
+		}
+
+		options = append(options, calculator.WithModeParser(calculator.TimeMode, rpn.ParseTime))
+		c = calculator.New(rpn.ParseInfix, options...)
 		c = calculator.ServiceLoggingMiddleware(logger)(c)
 		c = calculator.ValidateMiddleware()(c)
 	}
 
-	endpoint := calculator.MakeEndpoint(c)
-	handler := calculator.NewHTTPHandler(endpoint, logger)
+	// Create calculators for other notations
+	notations := calculator.Notations{}
+	{
+		notations["latex"] = calculator.ServiceLoggingMiddleware(logger)(calculator.New(latex.Parse, options...))
+		notations["natural"] = calculator.ServiceLoggingMiddleware(logger)(calculator.New(natural.Parse, options...))
+		notations[goconst.Notation] = calculator.ServiceLoggingMiddleware(logger)(calculator.New(goconst.Parse, options...))
+	}
+
+	parsers := calculator.Parsers{
+		calculator.InfixNotation: rpn.ParseInfix,
+		"latex":                  latex.Parse,
+		"natural":                natural.Parse,
+		goconst.Notation:         goconst.Parse,
+	}
+
+	lexers := rpn.Lexers{
+		calculator.InfixNotation: lexer.Lex,
+		"latex":                  latex.Lex,
+		"natural":                natural.Lex,
+	}
+
+	renderers := calculator.Renderers{
+		"latex":  calculator.NewFormatter(parsers, rpn.RenderLaTeX),
+		"mathml": calculator.NewFormatter(parsers, rpn.RenderMathML),
+	}
+
+	// Create calculate endpoint adding warnings and renderings to responses, only notations with lexers are linted
+	var calculate endpoint.Endpoint
+	{
+		linted := []string{}
+		for notation := range lexers {
+			linted = append(linted, notation)
+		}
+
+		calculate = calculator.MakeEndpoint(c, notations)
+		calculate = calculator.LintingMiddleware(rpn.NewLinter(lexers), linted...)(calculate)
+		calculate = calculator.RenderingMiddleware(renderers)(calculate)
+	}
+
+	endpoints := calculator.Endpoints{
+		Calculate:  calculate,
+		Format:     calculator.MakeFormatEndpoint(calculator.NewFormatter(parsers, rpn.Format)),
+		Explain:    calculator.MakeExplainEndpoint(rpn.NewExplainer(lexers)),
+		Simplify:   calculator.MakeSimplifyEndpoint(rpn.NewSimplifier(lexers)),
+		Derive:     calculator.MakeDeriveEndpoint(rpn.NewDeriver(lexers)),
+		Equivalent: calculator.MakeEquivalentEndpoint(rpn.NewEquivalenceChecker(lexers)),
+		Evaluate:   calculator.MakeEvaluateEndpoint(rpn.NewCompiler(lexers)),
+		BC:         calculator.MakeBCEndpoint(bc.NewInterpreter()),
+	}
+	handler := calculator.NewHTTPHandler(endpoints, logger)
 
 	logger.Log("transport", "http", "listen", *addr)
-	err := http.ListenAndServe(*addr, handler)
+	err = http.ListenAndServe(*addr, handler)
 	if err != nil {
 		logger.Log("transport", "http", "during", "listen", "err", err)
 	}
 }
+
+// logCacheStats logs statistics of the cache every interval
+func logCacheStats(logger log.Logger, cache *calculator.Cache, interval time.Duration) {
+	for range time.Tick(interval) {
+		stats := cache.Stats()
+		logger.Log("cache_hits", stats.Hits, "cache_misses", stats.Misses, "cache_size", stats.Size, "cache_capacity", stats.Capacity)
+	}
+}
+
+// reloadRates reloads rates file when it was modified, it is checked every interval, and on SIGHUP,
+// rates loaded earlier are used if the file cannot be reloaded
+func reloadRates(logger log.Logger, rates *calculator.RatesFile, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		tick = time.Tick(interval)
+	}
+
+	for {
+		var err error
+		reloaded := true
+
+		select {
+		case <-hangup:
+			err = rates.Reload()
+		case <-tick:
+			reloaded, err = rates.ReloadIfModified()
+		}
+
+		switch {
+		case err != nil:
+			logger.Log("during", "rates reload", "err", err)
+		case reloaded:
+			logger.Log("rates", "reloaded", "rates_date", rates.Rates().Date)
+		}
+	}
+}
@@ -0,0 +1,656 @@
+package bc
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// limits protecting the service from programs which would never finish or exhaust memory
+const (
+	maxSteps    = 10000000
+	maxDepth    = 1000
+	maxOutput   = 1 << 20
+	maxScale    = 10000
+	maxObase    = 1000
+	maxArrayDim = 65536
+)
+
+// mathlibScale is the scale set when math library is loaded
+const mathlibScale = 20
+
+// control tells enclosing statements how to continue after executing a statement
+type control int
+
+const (
+	proceed control = iota
+	breakLoop
+	continueLoop
+	returnFromFunction
+	halt
+)
+
+// errHalt stops the program when halt is executed inside of a function
+var errHalt = stderrors.New("halt")
+
+type interpreter struct{}
+
+// machine holds state of running program
+type machine struct {
+	ctx       context.Context
+	mathlib   bool
+	scalars   map[string]number
+	arrays    map[string]map[int64]number
+	functions map[string]*function
+	scale     int
+	ibase     int
+	obase     int
+	last      number
+	returned  number
+	output    strings.Builder
+	steps     int
+	depth     int
+}
+
+// NewInterpreter returns interpreter of programs written in bc language
+func NewInterpreter() calculator.Interpreter {
+	return interpreter{}
+}
+
+// Run parses and runs bc program, math library sets scale to 20 and defines s, c, a, l, e and j functions,
+// output written before an error is returned together with it
+func (interpreter) Run(ctx context.Context, program string, mathlib bool) (string, error) {
+	statements, err := parse(Lex(program))
+	if err != nil {
+		return "", err
+	}
+
+	m := &machine{
+		ctx:       ctx,
+		mathlib:   mathlib,
+		scalars:   map[string]number{},
+		arrays:    map[string]map[int64]number{},
+		functions: map[string]*function{},
+		ibase:     10,
+		obase:     10,
+		last:      newNumber(0),
+	}
+	if mathlib {
+		m.scale = mathlibScale
+	}
+
+	err = m.run(statements)
+
+	return m.output.String(), err
+}
+
+func (m *machine) run(statements []statement) error {
+	for _, s := range statements {
+		c, err := m.exec(s)
+		if err == errHalt {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch c {
+		case halt:
+			return nil
+		case breakLoop, continueLoop:
+			return errors.NewParsingError("break or continue outside of a loop")
+		case returnFromFunction:
+			return errors.NewParsingError("return outside of a function")
+		}
+	}
+
+	return nil
+}
+
+// step counts executed statements and checks whether the program should be stopped
+func (m *machine) step() error {
+	m.steps++
+	if m.steps > maxSteps {
+		return errors.NewCalculationError(fmt.Sprintf("program exceeded %d steps", maxSteps))
+	}
+
+	if m.steps%1024 == 0 {
+		if err := m.ctx.Err(); err != nil {
+			return errors.NewCalculationErrorWrap(err, "program interrupted")
+		}
+	}
+
+	return nil
+}
+
+func (m *machine) write(s string) error {
+	if m.output.Len()+len(s) > maxOutput {
+		return errors.NewCalculationError(fmt.Sprintf("program output exceeded %d bytes", maxOutput))
+	}
+
+	m.output.WriteString(s)
+	return nil
+}
+
+func (m *machine) exec(s statement) (control, error) {
+	if err := m.step(); err != nil {
+		return proceed, err
+	}
+
+	switch s := s.(type) {
+	case expressionStatement:
+		value, err := m.eval(s.expression)
+		if err != nil {
+			return proceed, err
+		}
+
+		// assignments are not printed
+		switch s.expression.(type) {
+		case assignmentNode, incrementNode:
+			return proceed, nil
+		}
+
+		m.last = value
+		return proceed, m.write(wrap(value.format(m.obase)) + "\n")
+	case stringStatement:
+		return proceed, m.write(s.text)
+	case printStatement:
+		return proceed, m.print(s)
+	case blockStatement:
+		for _, inner := range s.statements {
+			c, err := m.exec(inner)
+			if err != nil || c != proceed {
+				return c, err
+			}
+		}
+		return proceed, nil
+	case ifStatement:
+		condition, err := m.eval(s.condition)
+		if err != nil {
+			return proceed, err
+		}
+
+		switch {
+		case !condition.isZero():
+			return m.exec(s.then)
+		case s.otherwise != nil:
+			return m.exec(s.otherwise)
+		default:
+			return proceed, nil
+		}
+	case whileStatement:
+		return m.loop(nil, s.condition, nil, s.body)
+	case forStatement:
+		return m.loop(s.init, s.condition, s.step, s.body)
+	case breakStatement:
+		return breakLoop, nil
+	case continueStatement:
+		return continueLoop, nil
+	case haltStatement:
+		return halt, nil
+	case returnStatement:
+		m.returned = newNumber(0)
+		if s.value != nil {
+			value, err := m.eval(s.value)
+			if err != nil {
+				return proceed, err
+			}
+			m.returned = value
+		}
+		return returnFromFunction, nil
+	case defineStatement:
+		m.functions[s.function.name] = s.function
+		return proceed, nil
+	default:
+		return proceed, errors.NewCalcError(fmt.Sprintf("unknown statement: %T", s))
+	}
+}
+
+func (m *machine) loop(init expression, condition expression, step expression, body statement) (control, error) {
+	if init != nil {
+		if _, err := m.eval(init); err != nil {
+			return proceed, err
+		}
+	}
+
+	for {
+		if err := m.step(); err != nil {
+			return proceed, err
+		}
+
+		value, err := m.eval(condition)
+		if err != nil {
+			return proceed, err
+		}
+
+		if value.isZero() {
+			return proceed, nil
+		}
+
+		c, err := m.exec(body)
+		if err != nil {
+			return proceed, err
+		}
+
+		switch c {
+		case breakLoop:
+			return proceed, nil
+		case returnFromFunction, halt:
+			return c, nil
+		}
+
+		if step != nil {
+			if _, err := m.eval(step); err != nil {
+				return proceed, err
+			}
+		}
+	}
+}
+
+// print writes values without newlines, escape sequences in strings are interpreted
+func (m *machine) print(s printStatement) error {
+	escapes := strings.NewReplacer("\\a", "\a", "\\b", "\b", "\\f", "\f", "\\n", "\n", "\\r", "\r",
+		"\\q", "\"", "\\t", "\t", "\\\\", "\\")
+
+	for _, v := range s.values {
+		if text, ok := v.(string); ok {
+			if err := m.write(escapes.Replace(text)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := m.eval(v)
+		if err != nil {
+			return err
+		}
+
+		m.last = value
+		if err := m.write(wrap(value.format(m.obase))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *machine) eval(e expression) (number, error) {
+	switch e := e.(type) {
+	case numberNode:
+		return parseNumber(e.literal, m.ibase), nil
+	case nameNode:
+		return m.get(e.name), nil
+	case elementNode:
+		index, err := m.index(e.index)
+		if err != nil {
+			return number{}, err
+		}
+		value, ok := m.arrays[e.name][index]
+		if !ok {
+			return newNumber(0), nil
+		}
+		return value, nil
+	case callNode:
+		return m.call(e)
+	case builtinNode:
+		return m.builtin(e)
+	case unaryNode:
+		operand, err := m.eval(e.operand)
+		if err != nil {
+			return number{}, err
+		}
+		if e.operator == "!" {
+			return boolNumber(operand.isZero()), nil
+		}
+		return operand.neg(), nil
+	case binaryNode:
+		return m.binary(e)
+	case assignmentNode:
+		value, err := m.eval(e.value)
+		if err != nil {
+			return number{}, err
+		}
+
+		if e.operator != "=" {
+			current, err := m.eval(e.target)
+			if err != nil {
+				return number{}, err
+			}
+			if value, err = m.arithmetic(strings.TrimSuffix(e.operator, "="), current, value); err != nil {
+				return number{}, err
+			}
+		}
+
+		return m.assign(e.target, value)
+	case incrementNode:
+		current, err := m.eval(e.target)
+		if err != nil {
+			return number{}, err
+		}
+
+		value := current.add(newNumber(1))
+		if e.operator == "--" {
+			value = current.sub(newNumber(1))
+		}
+
+		if value, err = m.assign(e.target, value); err != nil || e.prefix {
+			return value, err
+		}
+		return current, nil
+	default:
+		return number{}, errors.NewCalcError(fmt.Sprintf("unknown expression: %T", e))
+	}
+}
+
+func (m *machine) binary(e binaryNode) (number, error) {
+	left, err := m.eval(e.left)
+	if err != nil {
+		return number{}, err
+	}
+
+	// logical operators are short-circuit
+	switch {
+	case e.operator == "&&" && left.isZero():
+		return newNumber(0), nil
+	case e.operator == "||" && !left.isZero():
+		return newNumber(1), nil
+	}
+
+	right, err := m.eval(e.right)
+	if err != nil {
+		return number{}, err
+	}
+
+	switch e.operator {
+	case "&&", "||":
+		return boolNumber(!right.isZero()), nil
+	case "==":
+		return boolNumber(left.cmp(right) == 0), nil
+	case "!=":
+		return boolNumber(left.cmp(right) != 0), nil
+	case "<":
+		return boolNumber(left.cmp(right) < 0), nil
+	case "<=":
+		return boolNumber(left.cmp(right) <= 0), nil
+	case ">":
+		return boolNumber(left.cmp(right) > 0), nil
+	case ">=":
+		return boolNumber(left.cmp(right) >= 0), nil
+	default:
+		return m.arithmetic(e.operator, left, right)
+	}
+}
+
+func (m *machine) arithmetic(operator string, left number, right number) (number, error) {
+	switch operator {
+	case "+":
+		return checkSize(left.add(right))
+	case "-":
+		return checkSize(left.sub(right))
+	case "*":
+		return left.mul(right, m.scale)
+	case "/":
+		return left.div(right, m.scale)
+	case "%":
+		return left.mod(right, m.scale)
+	case "^":
+		return left.pow(right, m.scale)
+	default:
+		return number{}, errors.NewCalcError(fmt.Sprintf("unknown operator: %s", operator))
+	}
+}
+
+func (m *machine) builtin(e builtinNode) (number, error) {
+	argument, err := m.eval(e.argument)
+	if err != nil {
+		return number{}, err
+	}
+
+	switch e.name {
+	case "length":
+		return newNumber(int64(argument.length())), nil
+	case "scale":
+		return newNumber(int64(argument.scale)), nil
+	default:
+		return argument.sqrt(m.scale)
+	}
+}
+
+// get returns value of scalar variable, unset variables are zero
+func (m *machine) get(name string) number {
+	switch name {
+	case "scale":
+		return newNumber(int64(m.scale))
+	case "ibase":
+		return newNumber(int64(m.ibase))
+	case "obase":
+		return newNumber(int64(m.obase))
+	case "last":
+		return m.last
+	}
+
+	value, ok := m.scalars[name]
+	if !ok {
+		return newNumber(0)
+	}
+
+	return value
+}
+
+// assign sets variable or array element and returns the assigned value
+func (m *machine) assign(target expression, value number) (number, error) {
+	switch t := target.(type) {
+	case elementNode:
+		index, err := m.index(t.index)
+		if err != nil {
+			return number{}, err
+		}
+		if m.arrays[t.name] == nil {
+			m.arrays[t.name] = map[int64]number{}
+		}
+		m.arrays[t.name][index] = value
+		return value, nil
+	case nameNode:
+		return m.set(t.name, value)
+	default:
+		return number{}, errors.NewCalcError(fmt.Sprintf("cannot assign to %T", target))
+	}
+}
+
+func (m *machine) set(name string, value number) (number, error) {
+	limits := map[string][2]int64{"scale": {0, maxScale}, "ibase": {2, 16}, "obase": {2, maxObase}}
+
+	switch name {
+	case "scale", "ibase", "obase":
+		i, ok := value.integer()
+		if !ok || i < limits[name][0] || i > limits[name][1] {
+			return number{}, errors.NewCalculationError(fmt.Sprintf("%s has to be between %d and %d", name, limits[name][0], limits[name][1]))
+		}
+
+		switch name {
+		case "scale":
+			m.scale = int(i)
+		case "ibase":
+			m.ibase = int(i)
+		default:
+			m.obase = int(i)
+		}
+		return newNumber(i), nil
+	case "last":
+		m.last = value
+	default:
+		m.scalars[name] = value
+	}
+
+	return value, nil
+}
+
+func (m *machine) index(e expression) (int64, error) {
+	value, err := m.eval(e)
+	if err != nil {
+		return 0, err
+	}
+
+	i, ok := value.integer()
+	if !ok || i < 0 || i >= maxArrayDim {
+		return 0, errors.NewCalculationError(fmt.Sprintf("array index out of bounds: %s", value.format(10)))
+	}
+
+	return i, nil
+}
+
+// call runs user defined function or function of math library, parameters and auto variables
+// are dynamically scoped like in bc, their previous values are restored after the call
+func (m *machine) call(c callNode) (number, error) {
+	f, ok := m.functions[c.name]
+	if !ok {
+		return m.callMathFunction(c)
+	}
+
+	if len(c.arguments) != len(f.parameters) {
+		return number{}, errors.NewCalculationError(fmt.Sprintf("function %s takes %d arguments, called with %d", c.name, len(f.parameters), len(c.arguments)))
+	}
+
+	if m.depth >= maxDepth {
+		return number{}, errors.NewCalculationError(fmt.Sprintf("function calls nested deeper than %d", maxDepth))
+	}
+
+	scalars := map[string]number{}
+	arrays := map[string]map[int64]number{}
+	for k, p := range f.parameters {
+		a := c.arguments[k]
+		if p.array != (a.array != "") {
+			return number{}, errors.NewCalculationError(fmt.Sprintf("argument %d of function %s has to be %s", k+1, c.name, kind(p)))
+		}
+
+		if p.array {
+			arrays[p.name] = copyArray(m.arrays[a.array])
+			continue
+		}
+
+		value, err := m.eval(a.value)
+		if err != nil {
+			return number{}, err
+		}
+		scalars[p.name] = value
+	}
+
+	for _, a := range f.autos {
+		if a.array {
+			arrays[a.name] = map[int64]number{}
+		} else {
+			scalars[a.name] = newNumber(0)
+		}
+	}
+
+	restore := m.bind(scalars, arrays)
+	defer restore()
+
+	m.depth++
+	defer func() { m.depth-- }()
+
+	for _, s := range f.body {
+		c, err := m.exec(s)
+		if err != nil {
+			return number{}, err
+		}
+
+		switch c {
+		case returnFromFunction:
+			return m.returned, nil
+		case halt:
+			return number{}, errHalt
+		case breakLoop, continueLoop:
+			return number{}, errors.NewParsingError("break or continue outside of a loop")
+		}
+	}
+
+	return newNumber(0), nil
+}
+
+func (m *machine) callMathFunction(c callNode) (number, error) {
+	f, ok := mathFunctions[c.name]
+	if !m.mathlib || !ok {
+		return number{}, errors.NewCalculationError(fmt.Sprintf("function %s is not defined", c.name))
+	}
+
+	if len(c.arguments) != f.arity {
+		return number{}, errors.NewCalculationError(fmt.Sprintf("function %s takes %d arguments, called with %d", c.name, f.arity, len(c.arguments)))
+	}
+
+	arguments := []number{}
+	for _, a := range c.arguments {
+		if a.array != "" {
+			return number{}, errors.NewCalculationError(fmt.Sprintf("arguments of function %s have to be numbers", c.name))
+		}
+
+		value, err := m.eval(a.value)
+		if err != nil {
+			return number{}, err
+		}
+		arguments = append(arguments, value)
+	}
+
+	return f.calculate(arguments, m.scale)
+}
+
+// bind sets variables for the time of a function call and returns function restoring their previous values
+func (m *machine) bind(scalars map[string]number, arrays map[string]map[int64]number) func() {
+	savedScalars := map[string]*number{}
+	for name, value := range scalars {
+		savedScalars[name] = nil
+		if previous, ok := m.scalars[name]; ok {
+			savedScalars[name] = &previous
+		}
+		m.scalars[name] = value
+	}
+
+	savedArrays := map[string]map[int64]number{}
+	for name, array := range arrays {
+		savedArrays[name] = m.arrays[name]
+		m.arrays[name] = array
+	}
+
+	return func() {
+		for name, previous := range savedScalars {
+			if previous == nil {
+				delete(m.scalars, name)
+			} else {
+				m.scalars[name] = *previous
+			}
+		}
+
+		for name, previous := range savedArrays {
+			m.arrays[name] = previous
+		}
+	}
+}
+
+func copyArray(array map[int64]number) map[int64]number {
+	copied := map[int64]number{}
+	for k, v := range array {
+		copied[k] = v
+	}
+
+	return copied
+}
+
+func kind(v variable) string {
+	if v.array {
+		return "an array"
+	}
+
+	return "a number"
+}
+
+func boolNumber(b bool) number {
+	if b {
+		return newNumber(1)
+	}
+
+	return newNumber(0)
+}
@@ -0,0 +1,95 @@
+package bc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		program        string
+		mathLib        bool
+		expectedOutput string
+		expectedError  error
+	}{
+		{"Integer arithmetic", "1 + 2 * 3\n(1 + 2) * 3\n7 % 3\n2 ^ 10", false, "7\n9\n1\n1024\n", nil},
+		{"Division truncated to scale", "1 / 3\nscale = 5\n1 / 3\n-1 / 3", false, "0\n.33333\n-.33333\n", nil},
+		{"Scale of multiplication", "scale = 1\n1.25 * 1.25\n1.5 * 1.5", false, "1.56\n2.2\n", nil},
+		{"Negative exponent", "scale = 3\n2 ^ -2", false, ".250\n", nil},
+		{"Square root", "scale = 10\nsqrt(2)\nsqrt(16)", false, "1.4142135623\n4.0000000000\n", nil},
+		{"Builtins", "length(123.45)\nscale(123.45)", false, "5\n2\n", nil},
+		{"Assignments are not printed", "a = 2; b = a++; a; b\nc += 5; c", false, "3\n2\n5\n", nil},
+		{"Last printed value", "5\n. + 1", false, "5\n6\n", nil},
+		{"Comparisons and logical operators", "1 < 2\n2 <= 1\n1 == 1 && 0 || !0", false, "1\n0\n1\n", nil},
+		{"Input and output bases", "ibase = 16\nFF\nibase = A\nobase = 2\n10\nobase = 16\n255.5", false, "255\n1010\nFF.8\n", nil},
+		{"Output base above 16", "obase = 100\n12345", false, " 01 23 45\n", nil},
+		{"Arrays", "a[2] = 3; a[1] = a[2] * 2; a[1] + a[2] + a[0]", false, "9\n", nil},
+		{"If and else", "if (1 > 2) 1 else 2\nif (0) { 3 }", false, "2\n", nil},
+		{"While loop", "i = 0; while (i < 3) { i; i += 1 }", false, "0\n1\n2\n", nil},
+		{"For loop with break and continue", "for (i = 0; ; i++) { if (i == 1) continue; if (i == 3) break; i }", false, "0\n2\n", nil},
+		{"Strings and print", "\"a\\tb\n\"; print 1, \" and \", 2, \"\\n\"", false, "a\\tb\n1 and 2\n", nil},
+		{
+			"Recursive function",
+			"define f(n) {\n if (n < 2) return (1)\n return (n * f(n - 1))\n}\nf(25)",
+			false, "15511210043330985984000000\n", nil,
+		},
+		{
+			"Auto variables and array parameters",
+			"define s(a[], n) { auto i, t; for (i = 0; i < n; i++) t += a[i]; return t }\nx[0] = 1; x[1] = 2; x[2] = 3; s(x[], 3); i",
+			false, "6\n0\n", nil,
+		},
+		{"Function without return is zero", "define f() { 1 }\nf()", false, "1\n0\n", nil},
+		{"Long numbers are wrapped", "2 ^ 300", false, "203703597633448608626844568840937816105146839366593625063614044935438\\\n1299763336706183397376\n", nil},
+		{"Halt stops the program", "1; halt; 2", false, "1\n", nil},
+		{"Comments", "/* comment\n */ 1 # comment\n2", false, "1\n2\n", nil},
+		{"Sine", "s(1)", true, ".84147098480789650665\n", nil},
+		{"Cosine", "c(1)", true, ".54030230586813971740\n", nil},
+		{"Arctangent", "a(1)\n4 * a(1)", true, ".78539816339744830961\n3.14159265358979323844\n", nil},
+		{"Natural logarithm", "l(2)\nl(100)", true, ".69314718055994530941\n4.60517018598809136803\n", nil},
+		{"Exponential", "e(1)\ne(-1)", true, "2.71828182845904523536\n.36787944117144232159\n", nil},
+		{"Bessel function", "j(0, 1)\nj(1, 2)", true, ".76519768655796655144\n.57672480775687338720\n", nil},
+		{"Math library scale", "scale = 5; s(1)", true, ".84147\n", nil},
+		{"Math library not loaded", "s(1)", false, "", errors.NewCalculationError("function s is not defined")},
+		{"Logarithm of zero", "l(0)", true, "", errors.NewCalculationError("logarithm of non-positive number")},
+		{"Division by zero", "1\n1 / 0\n2", false, "1\n", errors.NewCalculationError("divide by zero")},
+		{"Square root of negative number", "sqrt(-1)", false, "", errors.NewCalculationError("square root of negative number")},
+		{"Invalid base", "obase = 1", false, "", errors.NewCalculationError("obase has to be between 2 and 1000")},
+		{"Wrong number of arguments", "define f(a) { return a }\nf(1, 2)", false, "", errors.NewCalculationError("function f takes 1 arguments, called with 2")},
+		{"Infinite loop", "while (1) { }", false, "", errors.NewCalculationError("program exceeded 10000000 steps")},
+		{"Infinite recursion", "define f() { return f() }\nf()", false, "", errors.NewCalculationError("function calls nested deeper than 1000")},
+		{"Syntax error", "1 +", false, "", errors.NewParsingError(`unexpected "+"`)},
+		{"Unterminated block", "if (1) {", false, "", errors.NewParsingError("unexpected end of program")},
+		{"Break outside of a loop", "break", false, "", errors.NewParsingError("break or continue outside of a loop")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewInterpreter().Run(context.Background(), tt.program, tt.mathLib)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if output != tt.expectedOutput {
+				t.Errorf("expected output to be %q, got %q", tt.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewInterpreter().Run(ctx, "while (1) { }", false); err == nil {
+		t.Errorf("expected error of cancelled program, got nil")
+	}
+}
@@ -0,0 +1,151 @@
+package bc
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// item types of bc tokens which do not occur in infix operations, numbers, parentheses, arithmetic
+// operators, names and commas are lexed as infix items
+const (
+	itemModulo lexer.ItemType = lexer.Error + 1 + iota
+	itemEqual
+	itemNotEqual
+	itemLess
+	itemLessEqual
+	itemGreater
+	itemGreaterEqual
+	itemNot
+	itemAnd
+	itemOr
+	itemAssignment // =, +=, -=, *=, /=, %= or ^=
+	itemIncrement
+	itemDecrement
+	itemLeftBrace
+	itemRightBrace
+	itemLeftBracket
+	itemRightBracket
+	itemSemicolon
+	itemNewline
+	itemString
+	itemKeyword
+)
+
+var keywords = map[string]bool{
+	"define": true, "auto": true, "if": true, "else": true, "while": true, "for": true, "break": true,
+	"continue": true, "return": true, "quit": true, "halt": true, "print": true,
+}
+
+// operators of bc ordered so that longer ones are matched first
+var operators = []struct {
+	text string
+	typ  lexer.ItemType
+}{
+	{"++", itemIncrement}, {"--", itemDecrement},
+	{"+=", itemAssignment}, {"-=", itemAssignment}, {"*=", itemAssignment}, {"/=", itemAssignment},
+	{"%=", itemAssignment}, {"^=", itemAssignment},
+	{"==", itemEqual}, {"!=", itemNotEqual}, {"<=", itemLessEqual}, {">=", itemGreaterEqual},
+	{"&&", itemAnd}, {"||", itemOr},
+	{"+", lexer.Addition}, {"-", lexer.Subtraction}, {"*", lexer.Multiplication}, {"/", lexer.Division},
+	{"%", itemModulo}, {"^", lexer.Exponent}, {"=", itemAssignment}, {"<", itemLess}, {">", itemGreater},
+	{"!", itemNot}, {"(", lexer.LeftParenthesis}, {")", lexer.RightParenthesis}, {"[", itemLeftBracket},
+	{"]", itemRightBracket}, {"{", itemLeftBrace}, {"}", itemRightBrace}, {",", lexer.Separator},
+	{";", itemSemicolon}, {"\n", itemNewline},
+}
+
+// Lex returns lexer of bc program, errors are returned as lexer.Error items
+func Lex(input string) lexer.Lexer {
+	items, err := scan(input)
+	if err != nil {
+		return lexer.NewItemsLexer([]lexer.Item{lexer.NewItem(lexer.Error, err.Error())})
+	}
+
+	return lexer.NewItemsLexer(items)
+}
+
+// scan splits bc program into items, comments and escaped newlines are skipped
+func scan(input string) ([]lexer.Item, error) {
+	items := []lexer.Item{}
+
+	for pos := 0; pos < len(input); {
+		rest := input[pos:]
+		r, w := utf8.DecodeRuneInString(rest)
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\r':
+			pos += w
+		case strings.HasPrefix(rest, "\\\n"):
+			pos += 2
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at: %d", pos)
+			}
+			pos += end + 4
+		case r == '#':
+			end := strings.Index(rest, "\n")
+			if end < 0 {
+				end = len(rest)
+			}
+			pos += end
+		case r == '"':
+			end := strings.Index(rest[1:], "\"")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at: %d", pos)
+			}
+			items = append(items, lexer.NewItem(itemString, rest[1:end+1]))
+			pos += end + 2
+		case isDigit(r) || r == '.':
+			end := 1
+			for end < len(rest) && (isDigit(rune(rest[end])) || rest[end] == '.' && !strings.Contains(rest[:end], ".")) {
+				end++
+			}
+			// single dot is the last printed value
+			if rest[:end] == "." {
+				items = append(items, lexer.NewItem(lexer.Identifier, "last"))
+			} else {
+				items = append(items, lexer.NewItem(lexer.Number, rest[:end]))
+			}
+			pos += end
+		case r >= 'a' && r <= 'z':
+			end := 1
+			for end < len(rest) && isPartOfName(rune(rest[end])) {
+				end++
+			}
+			typ := lexer.Identifier
+			if keywords[rest[:end]] {
+				typ = itemKeyword
+			}
+			items = append(items, lexer.NewItem(typ, rest[:end]))
+			pos += end
+		default:
+			found := false
+			for _, o := range operators {
+				if strings.HasPrefix(rest, o.text) {
+					items = append(items, lexer.NewItem(o.typ, o.text))
+					pos += len(o.text)
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return nil, fmt.Errorf("invalid rune at: %d; could not lex: %s", pos, string(r))
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// isDigit returns true for digits of numbers in bases up to 16
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'A' && r <= 'F'
+}
+
+func isPartOfName(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_'
+}
@@ -0,0 +1,140 @@
+package bc
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mateuszkrasucki/calculator/pkg/bigmath"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// mathFunction of bc math library is calculated with big.Float precision high enough for scale digits
+// and truncated to scale digits like other bc operations
+type mathFunction struct {
+	arity int
+	// extraBits returns precision lost by the function for arguments
+	extraBits func(arguments []number) uint
+	function  func(arguments []*big.Float) (*big.Float, error)
+}
+
+var mathFunctions = map[string]mathFunction{
+	"s": {1, noExtraBits, func(a []*big.Float) (*big.Float, error) { return bigmath.Sin(a[0]), nil }},
+	"c": {1, noExtraBits, func(a []*big.Float) (*big.Float, error) { return bigmath.Cos(a[0]), nil }},
+	"a": {1, noExtraBits, func(a []*big.Float) (*big.Float, error) { return bigmath.Atan(a[0]), nil }},
+	"l": {1, noExtraBits, func(a []*big.Float) (*big.Float, error) { return bigmath.Log(a[0]) }},
+	"e": {1, exponentialExtraBits, func(a []*big.Float) (*big.Float, error) { return bigmath.Exp(a[0]), nil }},
+	"j": {2, besselExtraBits, bessel},
+}
+
+func noExtraBits([]number) uint {
+	return 0
+}
+
+// exponentialExtraBits covers digits of the integer part of e(x)
+func exponentialExtraBits(a []number) uint {
+	x, _ := toFloat(a[0], 64).Float64()
+	if x < 0 {
+		return 0
+	}
+
+	return uint(x * 1.45)
+}
+
+// besselExtraBits covers cancellation of terms of Bessel function series
+func besselExtraBits(a []number) uint {
+	x, _ := toFloat(a[1], 64).Float64()
+	if x < 0 {
+		x = -x
+	}
+
+	return uint(x * 3)
+}
+
+func (f mathFunction) calculate(arguments []number, scale int) (number, error) {
+	if f.extraBits(arguments) > maxDigits*4 {
+		return number{}, errors.NewCalculationError(fmt.Sprintf("number has more than %d digits", maxDigits))
+	}
+
+	digits := scale + 10
+	for _, a := range arguments {
+		digits += len(a.rescale(0).value.String())
+	}
+	prec := uint(float64(digits)*3.33) + 64 + f.extraBits(arguments)
+
+	floats := []*big.Float{}
+	for _, a := range arguments {
+		floats = append(floats, toFloat(a, prec))
+	}
+
+	result, err := f.function(floats)
+	if err != nil {
+		return number{}, err
+	}
+
+	return checkSize(fromFloat(result, scale))
+}
+
+func toFloat(n number, prec uint) *big.Float {
+	f := new(big.Float).SetPrec(prec).SetInt(n.value)
+
+	return f.Quo(f, new(big.Float).SetPrec(prec).SetInt(pow10(n.scale)))
+}
+
+// fromFloat returns number truncated to scale digits
+func fromFloat(f *big.Float, scale int) number {
+	scaled := new(big.Float).SetPrec(f.Prec()).Mul(f, new(big.Float).SetInt(pow10(scale)))
+	i, _ := scaled.Int(nil)
+
+	return number{i, scale}
+}
+
+func newFloat(prec uint, x int64) *big.Float {
+	return new(big.Float).SetPrec(prec).SetInt64(x)
+}
+
+// negligible returns true if term does not change result of series calculated with precision
+func negligible(term *big.Float, prec uint) bool {
+	return term.Sign() == 0 || term.MantExp(nil) < -int(prec)
+}
+
+// bessel returns Bessel function of the first kind of integer order n, J(n, x) = sum of
+// (-1)^k (x/2)^(2k+n) / (k! (k+n)!), J(-n, x) = (-1)^n J(n, x)
+func bessel(a []*big.Float) (*big.Float, error) {
+	order, _ := a[0].Int(nil)
+	if !order.IsInt64() || order.Int64() > maxDigits || order.Int64() < -maxDigits {
+		return nil, errors.NewCalculationError("order of Bessel function too large")
+	}
+
+	n := order.Int64()
+	negative := n < 0 && n%2 != 0
+	if n < 0 {
+		n = -n
+	}
+
+	x := a[1]
+	prec := x.Prec()
+	half := new(big.Float).SetPrec(prec).Quo(x, newFloat(prec, 2))
+
+	term := newFloat(prec, 1)
+	for k := int64(1); k <= n; k++ {
+		term.Mul(term, half)
+		term.Quo(term, newFloat(prec, k))
+	}
+
+	z2 := new(big.Float).SetPrec(prec).Mul(half, half)
+	z2.Neg(z2)
+	sum := new(big.Float).SetPrec(prec).Set(term)
+	limit, _ := half.Float64()
+
+	for k := int64(1); !negligible(term, prec) || float64(k) < limit*limit; k++ {
+		term.Mul(term, z2)
+		term.Quo(term, newFloat(prec, k*(k+n)))
+		sum.Add(sum, term)
+	}
+
+	if negative {
+		sum.Neg(sum)
+	}
+
+	return sum, nil
+}
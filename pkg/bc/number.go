@@ -0,0 +1,265 @@
+package bc
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// maxDigits is the highest number of decimal digits of numbers, bigger numbers are calculation errors
+const maxDigits = 100000
+
+// lineLength is the length of output lines, longer numbers are split with backslash
+const lineLength = 70
+
+const digits = "0123456789ABCDEF"
+
+// number is a decimal number with fixed number of fractional digits, its value is value / 10^scale
+type number struct {
+	value *big.Int
+	scale int
+}
+
+func newNumber(n int64) number {
+	return number{value: big.NewInt(n), scale: 0}
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// checkSize returns error if number has too many digits
+func checkSize(n number) (number, error) {
+	if n.value.BitLen() > maxDigits*10/3 {
+		return number{}, errors.NewCalculationError(fmt.Sprintf("number has more than %d digits", maxDigits))
+	}
+
+	return n, nil
+}
+
+// rescale returns number with scale digits, digits beyond it are truncated
+func (n number) rescale(scale int) number {
+	switch {
+	case scale > n.scale:
+		return number{new(big.Int).Mul(n.value, pow10(scale-n.scale)), scale}
+	case scale < n.scale:
+		return number{new(big.Int).Quo(n.value, pow10(n.scale-scale)), scale}
+	default:
+		return n
+	}
+}
+
+func (n number) isZero() bool {
+	return n.value.Sign() == 0
+}
+
+func (n number) cmp(m number) int {
+	scale := max(n.scale, m.scale)
+
+	return n.rescale(scale).value.Cmp(m.rescale(scale).value)
+}
+
+func (n number) neg() number {
+	return number{new(big.Int).Neg(n.value), n.scale}
+}
+
+func (n number) add(m number) number {
+	scale := max(n.scale, m.scale)
+
+	return number{new(big.Int).Add(n.rescale(scale).value, m.rescale(scale).value), scale}
+}
+
+func (n number) sub(m number) number {
+	return n.add(m.neg())
+}
+
+// mul keeps all digits of the product up to the highest of scale and scales of factors
+func (n number) mul(m number, scale int) (number, error) {
+	product := number{new(big.Int).Mul(n.value, m.value), n.scale + m.scale}
+
+	return checkSize(product.rescale(min(product.scale, max(scale, n.scale, m.scale))))
+}
+
+// div returns quotient truncated to scale digits
+func (n number) div(m number, scale int) (number, error) {
+	if m.isZero() {
+		return number{}, errors.NewCalculationError("divide by zero")
+	}
+
+	numerator := new(big.Int).Mul(n.value, pow10(scale+m.scale))
+	denominator := new(big.Int).Mul(m.value, pow10(n.scale))
+
+	return checkSize(number{numerator.Quo(numerator, denominator), scale})
+}
+
+// mod returns n - (n / m) * m, where quotient is truncated to scale digits
+func (n number) mod(m number, scale int) (number, error) {
+	quotient, err := n.div(m, scale)
+	if err != nil {
+		return number{}, err
+	}
+
+	product := number{new(big.Int).Mul(quotient.value, m.value), quotient.scale + m.scale}
+
+	return n.sub(product).rescale(max(scale+m.scale, n.scale)), nil
+}
+
+// pow raises number to integer part of exponent, negative exponents divide with scale digits
+func (n number) pow(exponent number, scale int) (number, error) {
+	e := exponent.rescale(0).value
+	if !e.IsInt64() || e.Int64() > maxDigits || e.Int64() < -maxDigits {
+		return number{}, errors.NewCalculationError("exponent too large")
+	}
+
+	power := e.Int64()
+	if power < 0 {
+		power = -power
+	}
+
+	if power > 0 && n.value.BitLen() > 1 && int64(n.value.BitLen())*power > maxDigits*10/3 {
+		return number{}, errors.NewCalculationError(fmt.Sprintf("number has more than %d digits", maxDigits))
+	}
+
+	result := number{new(big.Int).Exp(n.value, big.NewInt(power), nil), n.scale * int(power)}
+
+	if e.Sign() < 0 {
+		return newNumber(1).div(result, scale)
+	}
+
+	return checkSize(result.rescale(min(result.scale, max(scale, n.scale))))
+}
+
+// sqrt returns square root truncated to the highest of scale and scale of the number
+func (n number) sqrt(scale int) (number, error) {
+	if n.value.Sign() < 0 {
+		return number{}, errors.NewCalculationError("square root of negative number")
+	}
+
+	scale = max(scale, n.scale)
+	square := new(big.Int).Mul(n.value, pow10(2*scale-n.scale))
+
+	return checkSize(number{square.Sqrt(square), scale})
+}
+
+// length returns number of significant decimal digits
+func (n number) length() int {
+	integer := new(big.Int).Quo(new(big.Int).Abs(n.value), pow10(n.scale))
+	if integer.Sign() == 0 {
+		return max(n.scale, 1)
+	}
+
+	return len(integer.String()) + n.scale
+}
+
+// integer returns integer part of the number as int64
+func (n number) integer() (int64, bool) {
+	i := n.rescale(0).value
+
+	return i.Int64(), i.IsInt64()
+}
+
+// parseNumber converts literal written in base to number, scale of the number is the number of fractional digits
+func parseNumber(literal string, base int) number {
+	integerPart, fractionPart, _ := strings.Cut(literal, ".")
+	b := big.NewInt(int64(base))
+
+	integer := new(big.Int)
+	for _, r := range integerPart {
+		integer.Mul(integer, b)
+		integer.Add(integer, big.NewInt(int64(strings.IndexRune(digits, r))))
+	}
+
+	if fractionPart == "" {
+		return number{integer, 0}
+	}
+
+	fraction := new(big.Int)
+	for _, r := range fractionPart {
+		fraction.Mul(fraction, b)
+		fraction.Add(fraction, big.NewInt(int64(strings.IndexRune(digits, r))))
+	}
+
+	scale := len(fractionPart)
+	fraction.Mul(fraction, pow10(scale))
+	fraction.Quo(fraction, new(big.Int).Exp(b, big.NewInt(int64(scale)), nil))
+
+	return number{integer.Add(integer.Mul(integer, pow10(scale)), fraction), scale}
+}
+
+// format writes number in base, leading zero of numbers between -1 and 1 is omitted like in bc
+func (n number) format(base int) string {
+	if n.isZero() {
+		return "0"
+	}
+
+	abs := new(big.Int).Abs(n.value)
+	one := pow10(n.scale)
+	integer, fraction := new(big.Int).QuoRem(abs, one, new(big.Int))
+
+	var b strings.Builder
+	if n.value.Sign() < 0 {
+		b.WriteString("-")
+	}
+
+	if integer.Sign() > 0 {
+		b.WriteString(formatInteger(integer, base))
+	}
+
+	if n.scale == 0 {
+		return b.String()
+	}
+
+	b.WriteString(".")
+
+	// fractional digits are written until their precision reaches precision of the number
+	bigBase := big.NewInt(int64(base))
+	for precision := big.NewInt(1); precision.Cmp(one) < 0; precision.Mul(precision, bigBase) {
+		fraction.Mul(fraction, bigBase)
+		digit, rest := new(big.Int).QuoRem(fraction, one, new(big.Int))
+		b.WriteString(formatDigit(int(digit.Int64()), base))
+		fraction = rest
+	}
+
+	return b.String()
+}
+
+func formatInteger(integer *big.Int, base int) string {
+	if base == 10 {
+		return integer.String()
+	}
+
+	bigBase := big.NewInt(int64(base))
+	written := []string{}
+	rest := new(big.Int).Set(integer)
+	for rest.Sign() > 0 {
+		digit := new(big.Int)
+		rest.QuoRem(rest, bigBase, digit)
+		written = append([]string{formatDigit(int(digit.Int64()), base)}, written...)
+	}
+
+	return strings.Join(written, "")
+}
+
+// formatDigit writes digit as single character for bases up to 16, otherwise as space and zero-padded decimal number
+func formatDigit(digit int, base int) string {
+	if base <= 16 {
+		return string(digits[digit])
+	}
+
+	return fmt.Sprintf(" %0*d", len(fmt.Sprint(base-1)), digit)
+}
+
+// wrap splits output lines longer than line length with backslash and newline
+func wrap(s string) string {
+	var b strings.Builder
+	for len(s) > lineLength-1 {
+		b.WriteString(s[:lineLength-1])
+		b.WriteString("\\\n")
+		s = s[lineLength-1:]
+	}
+	b.WriteString(s)
+
+	return b.String()
+}
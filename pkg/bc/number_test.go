@@ -0,0 +1,56 @@
+package bc
+
+import (
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		literal  string
+		base     int
+		expected string
+	}{
+		{"Integer", "123", 10, "123"},
+		{"Fraction", "1.50", 10, "1.50"},
+		{"Fraction without integer part", ".5", 10, ".5"},
+		{"Hexadecimal", "FF", 16, "255"},
+		{"Binary fraction", "0.1", 2, ".5"},
+		{"Digit above base", "A", 10, "10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseNumber(tt.literal, tt.base).format(10)
+
+			if result != tt.expected {
+				t.Errorf("expected number to be %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		number   number
+		base     int
+		expected string
+	}{
+		{"Zero", parseNumber("0.000", 10), 10, "0"},
+		{"Negative fraction", parseNumber("0.25", 10).neg(), 10, "-.25"},
+		{"Hexadecimal", parseNumber("255.5", 10), 16, "FF.8"},
+		{"Binary", parseNumber("10", 10), 2, "1010"},
+		{"Base above 16", parseNumber("1000", 10), 20, " 02 10 00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.number.format(tt.base)
+
+			if result != tt.expected {
+				t.Errorf("expected formatted number to be %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,702 @@
+package bc
+
+import (
+	"fmt"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// expression and statement are nodes of parsed program, they are distinguished by type switches while executing
+type (
+	expression interface{}
+	statement  interface{}
+)
+
+type numberNode struct {
+	literal string
+}
+
+// nameNode is a scalar variable, including scale, ibase, obase and last
+type nameNode struct {
+	name string
+}
+
+type elementNode struct {
+	name  string
+	index expression
+}
+
+type callNode struct {
+	name      string
+	arguments []argument
+}
+
+// argument is an expression, or whole array passed by value if array is not empty
+type argument struct {
+	value expression
+	array string
+}
+
+// builtinNode is length, sqrt or scale function
+type builtinNode struct {
+	name     string
+	argument expression
+}
+
+type unaryNode struct {
+	operator string
+	operand  expression
+}
+
+type binaryNode struct {
+	operator string
+	left     expression
+	right    expression
+}
+
+type assignmentNode struct {
+	operator string
+	target   expression
+	value    expression
+}
+
+type incrementNode struct {
+	operator string
+	target   expression
+	prefix   bool
+}
+
+type expressionStatement struct {
+	expression expression
+}
+
+type stringStatement struct {
+	text string
+}
+
+type printStatement struct {
+	values []interface{} // expressions and strings
+}
+
+type blockStatement struct {
+	statements []statement
+}
+
+type ifStatement struct {
+	condition expression
+	then      statement
+	otherwise statement
+}
+
+type whileStatement struct {
+	condition expression
+	body      statement
+}
+
+// forStatement expressions are nil if omitted
+type forStatement struct {
+	init      expression
+	condition expression
+	step      expression
+	body      statement
+}
+
+type breakStatement struct{}
+
+type continueStatement struct{}
+
+type haltStatement struct{}
+
+type returnStatement struct {
+	value expression
+}
+
+type defineStatement struct {
+	function *function
+}
+
+type function struct {
+	name       string
+	parameters []variable
+	autos      []variable
+	body       []statement
+}
+
+type variable struct {
+	name  string
+	array bool
+}
+
+type parser struct {
+	items []lexer.Item
+	pos   int
+}
+
+var relationalOperators = map[lexer.ItemType]bool{
+	itemEqual: true, itemNotEqual: true, itemLess: true, itemLessEqual: true, itemGreater: true, itemGreaterEqual: true,
+}
+
+// parse reads items of bc program and returns its statements
+func parse(l lexer.Lexer) ([]statement, error) {
+	p := &parser{}
+	for i := l.NextItem(); i.GetType() != lexer.Empty; i = l.NextItem() {
+		if i.GetType() == lexer.Error {
+			return nil, errors.NewParsingError(i.GetString())
+		}
+		p.items = append(p.items, i)
+	}
+
+	statements, err := p.parseStatements(lexer.Empty)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().GetType() != lexer.Empty {
+		return nil, p.unexpected()
+	}
+
+	return statements, nil
+}
+
+func (p *parser) peek() lexer.Item {
+	if p.pos >= len(p.items) {
+		return lexer.NewEmptyItem()
+	}
+
+	return p.items[p.pos]
+}
+
+func (p *parser) next() lexer.Item {
+	i := p.peek()
+	if p.pos < len(p.items) {
+		p.pos++
+	}
+
+	return i
+}
+
+// accept consumes next item if it is of the type and, unless value is empty, of the value
+func (p *parser) accept(typ lexer.ItemType, value string) bool {
+	i := p.peek()
+	if i.GetType() != typ || value != "" && i.GetString() != value {
+		return false
+	}
+
+	p.pos++
+	return true
+}
+
+func (p *parser) expect(typ lexer.ItemType, value string) error {
+	if !p.accept(typ, value) {
+		return p.unexpected()
+	}
+
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	i := p.peek()
+	if i.GetType() == lexer.Empty {
+		return errors.NewParsingError("unexpected end of program")
+	}
+
+	return errors.NewParsingError(fmt.Sprintf("unexpected %q at item %d", i.GetString(), p.pos))
+}
+
+func (p *parser) skipNewlines() {
+	for p.accept(itemNewline, "") {
+	}
+}
+
+func (p *parser) isSeparator() bool {
+	typ := p.peek().GetType()
+
+	return typ == itemNewline || typ == itemSemicolon
+}
+
+// parseStatements parses statements separated with newlines or semicolons until the end item
+func (p *parser) parseStatements(end lexer.ItemType) ([]statement, error) {
+	statements := []statement{}
+
+	for {
+		for p.isSeparator() {
+			p.next()
+		}
+
+		if p.peek().GetType() == end || p.peek().GetType() == lexer.Empty {
+			return statements, nil
+		}
+
+		s, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, s)
+
+		// statements ending with a block do not need separators
+		previous := p.items[p.pos-1].GetType()
+		if !p.isSeparator() && p.peek().GetType() != end && previous != itemRightBrace {
+			return nil, p.unexpected()
+		}
+	}
+}
+
+func (p *parser) parseStatement() (statement, error) {
+	i := p.peek()
+
+	switch {
+	case i.GetType() == itemString:
+		p.next()
+		return stringStatement{i.GetString()}, nil
+	case i.GetType() == itemLeftBrace:
+		p.next()
+		statements, err := p.parseStatements(itemRightBrace)
+		if err != nil {
+			return nil, err
+		}
+		return blockStatement{statements}, p.expect(itemRightBrace, "")
+	case i.GetType() == itemKeyword:
+		return p.parseKeyword()
+	default:
+		e, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return expressionStatement{e}, nil
+	}
+}
+
+func (p *parser) parseKeyword() (statement, error) {
+	switch keyword := p.next().GetString(); keyword {
+	case "if":
+		condition, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+
+		then, err := p.parseBody()
+		if err != nil {
+			return nil, err
+		}
+
+		start := p.pos
+		p.skipNewlines()
+		if !p.accept(itemKeyword, "else") {
+			p.pos = start
+			return ifStatement{condition, then, nil}, nil
+		}
+
+		otherwise, err := p.parseBody()
+		if err != nil {
+			return nil, err
+		}
+
+		return ifStatement{condition, then, otherwise}, nil
+	case "while":
+		condition, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := p.parseBody()
+		if err != nil {
+			return nil, err
+		}
+
+		return whileStatement{condition, body}, nil
+	case "for":
+		return p.parseFor()
+	case "break":
+		return breakStatement{}, nil
+	case "continue":
+		return continueStatement{}, nil
+	case "quit", "halt":
+		return haltStatement{}, nil
+	case "return":
+		if p.isSeparator() || p.peek().GetType() == itemRightBrace || p.peek().GetType() == lexer.Empty {
+			return returnStatement{}, nil
+		}
+
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		return returnStatement{value}, nil
+	case "print":
+		return p.parsePrint()
+	case "define":
+		return p.parseDefine()
+	default:
+		p.pos--
+		return nil, p.unexpected()
+	}
+}
+
+// parseCondition parses expression in parentheses
+func (p *parser) parseCondition() (expression, error) {
+	if err := p.expect(lexer.LeftParenthesis, ""); err != nil {
+		return nil, err
+	}
+
+	condition, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	return condition, p.expect(lexer.RightParenthesis, "")
+}
+
+// parseBody parses statement of if, else, while or for, it may start in the next line
+func (p *parser) parseBody() (statement, error) {
+	p.skipNewlines()
+
+	return p.parseStatement()
+}
+
+func (p *parser) parseFor() (statement, error) {
+	if err := p.expect(lexer.LeftParenthesis, ""); err != nil {
+		return nil, err
+	}
+
+	expressions := []expression{}
+	for k, end := range []lexer.ItemType{itemSemicolon, itemSemicolon, lexer.RightParenthesis} {
+		var e expression
+		if p.peek().GetType() != end {
+			var err error
+			if e, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+		}
+
+		// omitted condition is always true
+		if e == nil && k == 1 {
+			e = numberNode{"1"}
+		}
+
+		if err := p.expect(end, ""); err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, e)
+	}
+
+	body, err := p.parseBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return forStatement{expressions[0], expressions[1], expressions[2], body}, nil
+}
+
+func (p *parser) parsePrint() (statement, error) {
+	values := []interface{}{}
+
+	for {
+		if i := p.peek(); i.GetType() == itemString {
+			p.next()
+			values = append(values, i.GetString())
+		} else {
+			e, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, e)
+		}
+
+		if !p.accept(lexer.Separator, "") {
+			return printStatement{values}, nil
+		}
+	}
+}
+
+func (p *parser) parseDefine() (statement, error) {
+	name := p.next()
+	if name.GetType() != lexer.Identifier {
+		p.pos--
+		return nil, p.unexpected()
+	}
+
+	if err := p.expect(lexer.LeftParenthesis, ""); err != nil {
+		return nil, err
+	}
+
+	f := &function{name: name.GetString()}
+
+	if !p.accept(lexer.RightParenthesis, "") {
+		parameters, err := p.parseVariables()
+		if err != nil {
+			return nil, err
+		}
+		f.parameters = parameters
+
+		if err := p.expect(lexer.RightParenthesis, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	p.skipNewlines()
+	if err := p.expect(itemLeftBrace, ""); err != nil {
+		return nil, err
+	}
+
+	for p.isSeparator() {
+		p.next()
+	}
+
+	if p.accept(itemKeyword, "auto") {
+		autos, err := p.parseVariables()
+		if err != nil {
+			return nil, err
+		}
+		f.autos = autos
+	}
+
+	body, err := p.parseStatements(itemRightBrace)
+	if err != nil {
+		return nil, err
+	}
+	f.body = body
+
+	return defineStatement{f}, p.expect(itemRightBrace, "")
+}
+
+// parseVariables parses comma separated names, names followed by [] are arrays
+func (p *parser) parseVariables() ([]variable, error) {
+	variables := []variable{}
+
+	for {
+		name := p.next()
+		if name.GetType() != lexer.Identifier {
+			p.pos--
+			return nil, p.unexpected()
+		}
+
+		v := variable{name: name.GetString()}
+		if p.accept(itemLeftBracket, "") {
+			if err := p.expect(itemRightBracket, ""); err != nil {
+				return nil, err
+			}
+			v.array = true
+		}
+		variables = append(variables, v)
+
+		if !p.accept(lexer.Separator, "") {
+			return variables, nil
+		}
+	}
+}
+
+// expressions are parsed with precedence of GNU bc, from the lowest: ||, &&, !, relational operators,
+// assignments, + and -, *, / and %, ^, unary minus, ++ and --
+
+func (p *parser) parseExpression() (expression, error) {
+	return p.parseBinary(p.parseAnd, map[lexer.ItemType]bool{itemOr: true})
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	return p.parseBinary(p.parseNot, map[lexer.ItemType]bool{itemAnd: true})
+}
+
+func (p *parser) parseNot() (expression, error) {
+	if p.accept(itemNot, "") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return unaryNode{"!", operand}, nil
+	}
+
+	return p.parseBinary(p.parseAssignment, relationalOperators)
+}
+
+func (p *parser) parseAssignment() (expression, error) {
+	target, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	operator := p.peek()
+	if operator.GetType() != itemAssignment {
+		return target, nil
+	}
+
+	if !isLvalue(target) {
+		return nil, p.unexpected()
+	}
+	p.next()
+
+	value, err := p.parseAssignment()
+	if err != nil {
+		return nil, err
+	}
+
+	return assignmentNode{operator.GetString(), target, value}, nil
+}
+
+func (p *parser) parseAdditive() (expression, error) {
+	return p.parseBinary(p.parseMultiplicative, map[lexer.ItemType]bool{lexer.Addition: true, lexer.Subtraction: true})
+}
+
+func (p *parser) parseMultiplicative() (expression, error) {
+	return p.parseBinary(p.parsePower, map[lexer.ItemType]bool{lexer.Multiplication: true, lexer.Division: true, itemModulo: true})
+}
+
+// parsePower parses right associative exponentiation
+func (p *parser) parsePower() (expression, error) {
+	base, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.accept(lexer.Exponent, "") {
+		return base, nil
+	}
+
+	exponent, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+
+	return binaryNode{"^", base, exponent}, nil
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	switch {
+	case p.accept(lexer.Subtraction, ""):
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{"-", operand}, nil
+	case p.accept(itemNot, ""):
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{"!", operand}, nil
+	case p.peek().GetType() == itemIncrement || p.peek().GetType() == itemDecrement:
+		operator := p.next().GetString()
+		target, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		if !isLvalue(target) {
+			return nil, p.unexpected()
+		}
+		return incrementNode{operator, target, true}, nil
+	}
+
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if i := p.peek(); isLvalue(e) && (i.GetType() == itemIncrement || i.GetType() == itemDecrement) {
+		p.next()
+		return incrementNode{i.GetString(), e, false}, nil
+	}
+
+	return e, nil
+}
+
+func (p *parser) parsePrimary() (expression, error) {
+	i := p.next()
+
+	switch i.GetType() {
+	case lexer.Number:
+		return numberNode{i.GetString()}, nil
+	case lexer.LeftParenthesis:
+		e, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(lexer.RightParenthesis, "")
+	case lexer.Identifier:
+		return p.parseName(i.GetString())
+	default:
+		p.pos--
+		return nil, p.unexpected()
+	}
+}
+
+// parseName parses variable, array element, function call or builtin function
+func (p *parser) parseName(name string) (expression, error) {
+	switch {
+	case (name == "length" || name == "sqrt" || name == "scale") && p.accept(lexer.LeftParenthesis, ""):
+		argument, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return builtinNode{name, argument}, p.expect(lexer.RightParenthesis, "")
+	case name == "length" || name == "sqrt":
+		return nil, p.unexpected()
+	case p.accept(lexer.LeftParenthesis, ""):
+		return p.parseCall(name)
+	case p.accept(itemLeftBracket, ""):
+		index, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return elementNode{name, index}, p.expect(itemRightBracket, "")
+	default:
+		return nameNode{name}, nil
+	}
+}
+
+func (p *parser) parseCall(name string) (expression, error) {
+	call := callNode{name: name, arguments: []argument{}}
+	if p.accept(lexer.RightParenthesis, "") {
+		return call, nil
+	}
+
+	for {
+		// array arguments are written as name[]
+		if p.peek().GetType() == lexer.Identifier && p.pos+2 < len(p.items) &&
+			p.items[p.pos+1].GetType() == itemLeftBracket && p.items[p.pos+2].GetType() == itemRightBracket {
+			call.arguments = append(call.arguments, argument{array: p.next().GetString()})
+			p.pos += 2
+		} else {
+			value, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			call.arguments = append(call.arguments, argument{value: value})
+		}
+
+		if !p.accept(lexer.Separator, "") {
+			return call, p.expect(lexer.RightParenthesis, "")
+		}
+	}
+}
+
+// parseBinary parses left associative operators of one precedence level
+func (p *parser) parseBinary(operand func() (expression, error), operators map[lexer.ItemType]bool) (expression, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for operators[p.peek().GetType()] {
+		operator := p.next().GetString()
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryNode{operator, left, right}
+	}
+
+	return left, nil
+}
+
+func isLvalue(e expression) bool {
+	switch e.(type) {
+	case nameNode, elementNode:
+		return true
+	default:
+		return false
+	}
+}
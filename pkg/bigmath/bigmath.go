@@ -0,0 +1,325 @@
+// Package bigmath provides elementary functions of big.Float numbers, results are calculated
+// with precision of the argument, callers needing correctly rounded results should add guard bits
+package bigmath
+
+import (
+	"math/big"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func newFloat(prec uint, x int64) *big.Float {
+	return new(big.Float).SetPrec(prec).SetInt64(x)
+}
+
+// negligible returns true if term does not change result of series calculated with precision
+func negligible(term *big.Float, prec uint) bool {
+	return term.Sign() == 0 || term.MantExp(nil) < -int(prec)
+}
+
+// Pi returns pi with precision
+func Pi(prec uint) *big.Float {
+	pi := Atan(newFloat(prec, 1))
+
+	return pi.Mul(pi, newFloat(prec, 4))
+}
+
+// Exp sums Taylor series of x / 2^k smaller than 1/2 and squares the sum k times
+func Exp(x *big.Float) *big.Float {
+	prec := x.Prec()
+	y := new(big.Float).SetPrec(prec).Set(x)
+
+	k := 0
+	if exp := y.MantExp(nil); exp >= 0 {
+		k = exp + 1
+		y.SetMantExp(y, -k)
+	}
+
+	sum, term := newFloat(prec, 1), newFloat(prec, 1)
+	for n := int64(1); !negligible(term, prec); n++ {
+		term.Mul(term, y)
+		term.Quo(term, newFloat(prec, n))
+		sum.Add(sum, term)
+	}
+
+	for ; k > 0; k-- {
+		sum.Mul(sum, sum)
+	}
+
+	return sum
+}
+
+// Log of x = m * 2^e is ln(m) + e * ln(2), both calculated with series of artanh
+func Log(x *big.Float) (*big.Float, error) {
+	if x.Sign() <= 0 {
+		return nil, errors.NewCalculationError("logarithm of non-positive number")
+	}
+
+	prec := x.Prec()
+	m := new(big.Float).SetPrec(prec)
+	e := x.MantExp(m)
+
+	// ln(m) = 2 * artanh((m - 1) / (m + 1))
+	z := new(big.Float).SetPrec(prec).Sub(m, newFloat(prec, 1))
+	z.Quo(z, new(big.Float).SetPrec(prec).Add(m, newFloat(prec, 1)))
+	result := artanh(z)
+
+	ln2 := artanh(new(big.Float).SetPrec(prec).Quo(newFloat(prec, 1), newFloat(prec, 3)))
+	result.Add(result, ln2.Mul(ln2, newFloat(prec, int64(e))))
+
+	return result.Mul(result, newFloat(prec, 2)), nil
+}
+
+// Log10 returns decimal logarithm of x
+func Log10(x *big.Float) (*big.Float, error) {
+	ln, err := Log(x)
+	if err != nil {
+		return nil, err
+	}
+
+	ln10, _ := Log(newFloat(x.Prec(), 10))
+
+	return ln.Quo(ln, ln10), nil
+}
+
+func artanh(z *big.Float) *big.Float {
+	prec := z.Prec()
+	z2 := new(big.Float).SetPrec(prec).Mul(z, z)
+	sum := new(big.Float).SetPrec(prec).Set(z)
+	power := new(big.Float).SetPrec(prec).Set(z)
+
+	for n := int64(3); ; n += 2 {
+		power.Mul(power, z2)
+		term := new(big.Float).SetPrec(prec).Quo(power, newFloat(prec, n))
+		if negligible(term, prec) {
+			return sum
+		}
+		sum.Add(sum, term)
+	}
+}
+
+// Pow returns x^y, integer exponents are calculated by repeated squaring, other ones as exp(y * ln(x))
+func Pow(x *big.Float, y *big.Float) (*big.Float, error) {
+	prec := x.Prec()
+
+	if y.IsInt() {
+		n, _ := y.Int(nil)
+		if n.Sign() < 0 && x.Sign() == 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+
+		result := newFloat(prec, 1)
+		power := new(big.Float).SetPrec(prec).Set(x)
+		for e := new(big.Int).Abs(n); e.Sign() > 0; e.Rsh(e, 1) {
+			if e.Bit(0) == 1 {
+				result.Mul(result, power)
+			}
+			power.Mul(power, power)
+			if result.IsInf() || power.IsInf() && e.BitLen() > 1 {
+				return nil, errors.NewCalculationError("result is too large")
+			}
+		}
+
+		if n.Sign() < 0 {
+			result.Quo(newFloat(prec, 1), result)
+		}
+
+		return result, nil
+	}
+
+	if x.Sign() < 0 {
+		return nil, errors.NewCalculationError("non-integer power of negative number")
+	}
+
+	if x.Sign() == 0 {
+		if y.Sign() < 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+		return newFloat(prec, 0), nil
+	}
+
+	ln, _ := Log(x)
+
+	return Exp(ln.Mul(ln, y)), nil
+}
+
+// Atan reduces x to at most 1/8 with atan(x) = 2 * atan(x / (1 + sqrt(1 + x^2))) and sums Taylor series
+func Atan(x *big.Float) *big.Float {
+	prec := x.Prec()
+	one := newFloat(prec, 1)
+	y := new(big.Float).SetPrec(prec).Set(x)
+
+	// atan(x) = sign(x) * pi / 2 - atan(1 / x) for |x| > 1
+	if new(big.Float).Abs(y).Cmp(one) > 0 {
+		halfPi := Atan(one)
+		halfPi.Mul(halfPi, newFloat(prec, 2))
+		if y.Sign() < 0 {
+			halfPi.Neg(halfPi)
+		}
+		return halfPi.Sub(halfPi, Atan(new(big.Float).SetPrec(prec).Quo(one, y)))
+	}
+
+	halvings := 0
+	for eighth := new(big.Float).SetPrec(prec).Quo(one, newFloat(prec, 8)); new(big.Float).Abs(y).Cmp(eighth) > 0; halvings++ {
+		root := new(big.Float).SetPrec(prec).Mul(y, y)
+		root.Add(root, one)
+		root.Sqrt(root)
+		y.Quo(y, root.Add(root, one))
+	}
+
+	z2 := new(big.Float).SetPrec(prec).Mul(y, y)
+	z2.Neg(z2)
+	sum := new(big.Float).SetPrec(prec).Set(y)
+	power := new(big.Float).SetPrec(prec).Set(y)
+
+	for n := int64(3); ; n += 2 {
+		power.Mul(power, z2)
+		term := new(big.Float).SetPrec(prec).Quo(power, newFloat(prec, n))
+		if negligible(term, prec) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+
+	return sum.SetMantExp(sum, halvings)
+}
+
+// Asin returns arcsine of x between -1 and 1 as atan(x / sqrt(1 - x^2))
+func Asin(x *big.Float) (*big.Float, error) {
+	prec := x.Prec()
+	one := newFloat(prec, 1)
+
+	switch new(big.Float).Abs(x).Cmp(one) {
+	case 1:
+		return nil, errors.NewCalculationError("argument of arcsine has to be between -1 and 1")
+	case 0:
+		halfPi := Pi(prec)
+		halfPi.Quo(halfPi, newFloat(prec, 2))
+		if x.Sign() < 0 {
+			halfPi.Neg(halfPi)
+		}
+		return halfPi, nil
+	}
+
+	root := new(big.Float).SetPrec(prec).Mul(x, x)
+	root.Sub(one, root)
+	root.Sqrt(root)
+
+	return Atan(root.Quo(x, root)), nil
+}
+
+// Acos returns arccosine of x between -1 and 1 as pi / 2 - asin(x)
+func Acos(x *big.Float) (*big.Float, error) {
+	asin, err := Asin(x)
+	if err != nil {
+		return nil, errors.NewCalculationError("argument of arccosine has to be between -1 and 1")
+	}
+
+	halfPi := Pi(x.Prec())
+	halfPi.Quo(halfPi, newFloat(x.Prec(), 2))
+
+	return halfPi.Sub(halfPi, asin), nil
+}
+
+// Sin reduces x modulo 2 pi and sums Taylor series of sine
+func Sin(x *big.Float) *big.Float {
+	return sinCos(x, false)
+}
+
+// Cos reduces x modulo 2 pi and sums Taylor series of cosine
+func Cos(x *big.Float) *big.Float {
+	return sinCos(x, true)
+}
+
+// Tan returns sin(x) / cos(x), tangent is undefined if cosine is zero at precision
+func Tan(x *big.Float) (*big.Float, error) {
+	cos := Cos(x)
+	if cos.Sign() == 0 {
+		return nil, errors.NewCalculationError("tangent is undefined")
+	}
+
+	sin := Sin(x)
+
+	return sin.Quo(sin, cos), nil
+}
+
+func sinCos(x *big.Float, cosine bool) *big.Float {
+	prec := x.Prec()
+
+	twoPi := Pi(prec)
+	twoPi.Mul(twoPi, newFloat(prec, 2))
+
+	turns, _ := new(big.Float).SetPrec(prec).Quo(x, twoPi).Int(nil)
+	y := new(big.Float).SetPrec(prec).SetInt(turns)
+	y.Sub(x, y.Mul(y, twoPi))
+
+	z2 := new(big.Float).SetPrec(prec).Mul(y, y)
+	z2.Neg(z2)
+
+	term, n := new(big.Float).SetPrec(prec).Set(y), int64(1)
+	if cosine {
+		term, n = newFloat(prec, 1), 0
+	}
+	sum := new(big.Float).SetPrec(prec).Set(term)
+
+	for ; !negligible(term, prec); n += 2 {
+		term.Mul(term, z2)
+		term.Quo(term, newFloat(prec, (n+1)*(n+2)))
+		sum.Add(sum, term)
+	}
+
+	return sum
+}
+
+// Sinh returns (exp(x) - exp(-x)) / 2
+func Sinh(x *big.Float) *big.Float {
+	e, inverse := exps(x)
+
+	return e.Sub(e, inverse).Quo(e, newFloat(x.Prec(), 2))
+}
+
+// Cosh returns (exp(x) + exp(-x)) / 2
+func Cosh(x *big.Float) *big.Float {
+	e, inverse := exps(x)
+
+	return e.Add(e, inverse).Quo(e, newFloat(x.Prec(), 2))
+}
+
+// Tanh returns (exp(x) - exp(-x)) / (exp(x) + exp(-x))
+func Tanh(x *big.Float) *big.Float {
+	e, inverse := exps(x)
+	sum := new(big.Float).SetPrec(x.Prec()).Add(e, inverse)
+
+	return e.Sub(e, inverse).Quo(e, sum)
+}
+
+func exps(x *big.Float) (*big.Float, *big.Float) {
+	e := Exp(x)
+
+	return e, new(big.Float).SetPrec(x.Prec()).Quo(newFloat(x.Prec(), 1), e)
+}
+
+// DecimalDigits returns number of fractional digits of decimal representation of fractions with denominator,
+// or false if their decimal representation does not terminate
+func DecimalDigits(denominator *big.Int) (int, bool) {
+	d := new(big.Int).Set(denominator)
+	counts := []int{0, 0}
+
+	for k, p := range []int64{2, 5} {
+		prime := big.NewInt(p)
+		for new(big.Int).Mod(d, prime).Sign() == 0 {
+			d.Div(d, prime)
+			counts[k]++
+		}
+	}
+
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+
+	if counts[0] > counts[1] {
+		return counts[0], true
+	}
+
+	return counts[1], true
+}
@@ -0,0 +1,96 @@
+package bigmath
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestFunctions(t *testing.T) {
+	const prec = 200
+
+	x := func(s string) *big.Float {
+		f, _ := new(big.Float).SetPrec(prec).SetString(s)
+		return f
+	}
+
+	tests := []struct {
+		name          string
+		function      func() (*big.Float, error)
+		expected      string
+		expectedError error
+	}{
+		{"Pi", func() (*big.Float, error) { return Pi(prec), nil }, "3.14159265358979323846264338327950288419716939937510", nil},
+		{"Exp", func() (*big.Float, error) { return Exp(x("1")), nil }, "2.71828182845904523536028747135266249775724709369995", nil},
+		{"Exp of negative number", func() (*big.Float, error) { return Exp(x("-10")), nil }, "0.0000453999297624848515355915155605506102379180888665", nil},
+		{"Log", func() (*big.Float, error) { return Log(x("10")) }, "2.30258509299404568401799145468436420760110148862877", nil},
+		{"Log10", func() (*big.Float, error) { return Log10(x("0.001")) }, "-3", nil},
+		{"Integer power", func() (*big.Float, error) { return Pow(x("3"), x("-2")) }, "0.11111111111111111111111111111111111111111111111111", nil},
+		{"Fractional power", func() (*big.Float, error) { return Pow(x("2"), x("0.5")) }, "1.41421356237309504880168872420969807856967187537694", nil},
+		{"Sin", func() (*big.Float, error) { return Sin(x("100")), nil }, "-0.50636564110975879365655761045978543206503272129065", nil},
+		{"Cos", func() (*big.Float, error) { return Cos(x("-2")), nil }, "-0.41614683654714238699756822950076218976600077107554", nil},
+		{"Tan", func() (*big.Float, error) { return Tan(x("1")) }, "1.55740772465490223050697480745836017308725077238152", nil},
+		{"Atan", func() (*big.Float, error) { return Atan(x("-3")), nil }, "-1.24904577239825442582991707728109012307782940412990", nil},
+		{"Asin", func() (*big.Float, error) { return Asin(x("0.5")) }, "0.52359877559829887307710723054658381403286156656252", nil},
+		{"Acos", func() (*big.Float, error) { return Acos(x("-1")) }, "3.14159265358979323846264338327950288419716939937510", nil},
+		{"Sinh", func() (*big.Float, error) { return Sinh(x("1")), nil }, "1.17520119364380145688238185059560081515571798133410", nil},
+		{"Cosh", func() (*big.Float, error) { return Cosh(x("1")), nil }, "1.54308063481524377847790562075706168260152911236586", nil},
+		{"Tanh", func() (*big.Float, error) { return Tanh(x("1")), nil }, "0.76159415595576488811945828260479359041276859725794", nil},
+		{"Log of zero", func() (*big.Float, error) { return Log(x("0")) }, "", errors.NewCalculationError("logarithm of non-positive number")},
+		{"Asin out of range", func() (*big.Float, error) { return Asin(x("1.5")) }, "", errors.NewCalculationError("argument of arcsine has to be between -1 and 1")},
+		{"Fractional power of negative number", func() (*big.Float, error) { return Pow(x("-2"), x("0.5")) }, "", errors.NewCalculationError("non-integer power of negative number")},
+		{"Negative power of zero", func() (*big.Float, error) { return Pow(x("0"), x("-1")) }, "", errors.NewCalculationError("division by zero")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.function()
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			expected, _ := new(big.Float).SetPrec(prec).SetString(tt.expected)
+			difference := new(big.Float).Sub(result, expected)
+			if difference.Abs(difference).Cmp(big.NewFloat(1e-48)) > 0 {
+				t.Errorf("expected result to be %s, got %s", tt.expected, result.Text('g', 50))
+			}
+		})
+	}
+}
+
+func TestDecimalDigits(t *testing.T) {
+	tests := []struct {
+		name        string
+		denominator int64
+		expected    int
+		expectedOk  bool
+	}{
+		{"Integer", 1, 0, true},
+		{"Halves", 2, 1, true},
+		{"Powers of two", 16, 4, true},
+		{"Powers of five", 125, 3, true},
+		{"Mixed powers", 40, 3, true},
+		{"Thirds", 3, 0, false},
+		{"Powers of ten and other prime", 70, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digits, ok := DecimalDigits(big.NewInt(tt.denominator))
+
+			if digits != tt.expected || ok != tt.expectedOk {
+				t.Errorf("expected result to be %d %v, got %d %v", tt.expected, tt.expectedOk, digits, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package calculator
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+)
+
+// CacheStats describes usage of the cache
+type CacheStats struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Size     int    `json:"size"`
+	Capacity int    `json:"capacity"`
+}
+
+// Cache is a bounded cache of parsed operations evicting least recently used ones, it is safe for concurrent use
+type Cache struct {
+	mu         sync.Mutex
+	capacity   int
+	operations map[cacheKey]*list.Element
+	recent     *list.List
+	hits       uint64
+	misses     uint64
+}
+
+// cacheKey is input of operation and name of parser which parsed it, operations of different parsers
+// are cached separately
+type cacheKey struct {
+	parser string
+	input  string
+}
+
+type cacheEntry struct {
+	key       cacheKey
+	operation OperationInterface
+}
+
+// NewCache returns cache of at most capacity parsed operations
+func NewCache(capacity int) *Cache {
+	return &Cache{capacity: capacity, operations: map[cacheKey]*list.Element{}, recent: list.New()}
+}
+
+// Stats returns number of hits and misses since cache creation and its current size
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{Hits: c.hits, Misses: c.misses, Size: c.recent.Len(), Capacity: c.capacity}
+}
+
+// parser returns parser returning cached operations of parser with the name, operations missing in the cache
+// are parsed with parse and cached unless parsing fails
+func (c *Cache) parser(name string, parse parser) parser {
+	return func(ctx context.Context, input string) (OperationInterface, error) {
+		// runs of whitespace are collapsed to single spaces, lexers treat them alike, e.g. 3h  20m and 3h 20m,
+		// but whitespace is not removed, e.g. 2026 - 10 - 17 is a subtraction and 2026-10-17 is a date
+		key := cacheKey{parser: name, input: strings.Join(strings.Fields(input), " ")}
+
+		if operation, ok := c.get(key); ok {
+			return operation, nil
+		}
+
+		operation, err := parse(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		c.add(key, operation)
+
+		return operation, nil
+	}
+}
+
+func (c *Cache) get(key cacheKey) (OperationInterface, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.operations[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.recent.MoveToFront(e)
+
+	return e.Value.(cacheEntry).operation, true
+}
+
+func (c *Cache) add(key cacheKey, operation OperationInterface) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+
+	if e, ok := c.operations[key]; ok {
+		c.recent.MoveToFront(e)
+		return
+	}
+
+	c.operations[key] = c.recent.PushFront(cacheEntry{key: key, operation: operation})
+
+	if c.recent.Len() > c.capacity {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.operations, oldest.Value.(cacheEntry).key)
+	}
+}
@@ -0,0 +1,142 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCache(t *testing.T) {
+	parsed := 0
+	countingParser := func(ctx context.Context, input string) (OperationInterface, error) {
+		parsed++
+		return mockParser(ctx, input)
+	}
+
+	cache := NewCache(2)
+	c := New(countingParser, WithCache(cache))
+
+	tests := []struct {
+		name           string
+		operation      string
+		expectedResult float64
+		expectedParsed int
+	}{
+		{"Miss", "1 + 2", 5, 1},
+		{"Hit", "1 + 2", 5, 1},
+		{"Hit of operation with other runs of whitespace", " 1  +\t2\n", 5, 1},
+		{"Miss of differently spaced operation", "1+2", 3, 2},
+		{"Hit of recently used operation", "1 + 2", 5, 2},
+		{"Miss of third operation evicting the least recently used one", "5", 1, 3},
+		{"Hit of operation kept in the cache", "1 + 2", 5, 3},
+		{"Miss of evicted operation", "1+2", 3, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := c.Calculate(context.Background(), tt.operation)
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			if result != tt.expectedResult {
+				t.Errorf("expected result to be %v, got %v", tt.expectedResult, result)
+			}
+
+			if parsed != tt.expectedParsed {
+				t.Errorf("expected %d operations to be parsed, got %d", tt.expectedParsed, parsed)
+			}
+		})
+	}
+
+	expected := CacheStats{Hits: 4, Misses: 4, Size: 2, Capacity: 2}
+	if !cmp.Equal(expected, cache.Stats()) {
+		t.Errorf("expected stats to be %v, got %v", expected, cache.Stats())
+	}
+}
+
+func TestCacheParsingError(t *testing.T) {
+	cache := NewCache(2)
+	c := New(mockParserError, WithCache(cache))
+
+	for k := 0; k < 2; k++ {
+		if _, err := c.Calculate(context.Background(), "2+"); err == nil {
+			t.Fatalf("expected parsing error, got nil")
+		}
+	}
+
+	expected := CacheStats{Hits: 0, Misses: 2, Size: 0, Capacity: 2}
+	if !cmp.Equal(expected, cache.Stats()) {
+		t.Errorf("expected stats to be %v, got %v", expected, cache.Stats())
+	}
+}
+
+func TestCacheConcurrentAccess(t *testing.T) {
+	cache := NewCache(10)
+	c := New(mockParser, WithCache(cache))
+
+	var wg sync.WaitGroup
+	for k := 0; k < 20; k++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				c.Calculate(context.Background(), fmt.Sprintf("%d + %d", k, i%15))
+			}
+		}(k)
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	if stats.Hits+stats.Misses != 2000 {
+		t.Errorf("expected 2000 lookups, got %d", stats.Hits+stats.Misses)
+	}
+
+	if stats.Size != 10 {
+		t.Errorf("expected cache to be full, got %d operations", stats.Size)
+	}
+}
+
+func TestCacheModeParsers(t *testing.T) {
+	timeParser := func(ctx context.Context, input string) (OperationInterface, error) {
+		return mockModeParser(ctx, "date "+input)
+	}
+
+	cache := NewCache(10)
+	c := New(mockModeParser, WithCache(cache), WithModeParser(TimeMode, timeParser))
+
+	tests := []struct {
+		name          string
+		operation     string
+		mode          string
+		expectedValue Value
+	}{
+		{"Miss of spaced operation", "2026 - 10 - 17", UnitsMode, mockValue("units 2026 - 10 - 17")},
+		{"Miss of operation written without spaces", "2026-10-17", UnitsMode, mockValue("units 2026-10-17")},
+		{"Miss of operation of mode with its own parser", "2026-10-17", TimeMode, mockValue("time date 2026-10-17")},
+		{"Hit of operation of mode with its own parser", "2026-10-17", TimeMode, mockValue("time date 2026-10-17")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := calculateMode(context.Background(), c, tt.operation, Mode{Name: tt.mode})
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			if value != tt.expectedValue {
+				t.Errorf("expected value to be %v, got %v", tt.expectedValue, value)
+			}
+		})
+	}
+
+	expected := CacheStats{Hits: 1, Misses: 3, Size: 3, Capacity: 10}
+	if !cmp.Equal(expected, cache.Stats()) {
+		t.Errorf("expected stats to be %v, got %v", expected, cache.Stats())
+	}
+}
@@ -17,12 +17,50 @@ type Calculator interface {
 }
 
 type calculator struct {
-	parse parser
+	parse         parser
+	modeParsers   map[string]parser
+	cache         *Cache
+	specialValues SpecialValues
+	rates         RatesSource
+}
+
+// Option configures Calculator returned by New
+type Option func(*calculator)
+
+// WithCache makes calculator reuse operations parsed earlier and stored in the cache
+func WithCache(cache *Cache) Option {
+	return func(c *calculator) {
+		c.cache = cache
+	}
+}
+
+// WithModeParser makes calculator parse operations calculated in mode with parsingFunc instead of its parsing
+// function, e.g. to lex dates and durations only in time mode
+func WithModeParser(mode string, parsingFunc parser) Option {
+	return func(c *calculator) {
+		if c.modeParsers == nil {
+			c.modeParsers = map[string]parser{}
+		}
+		c.modeParsers[mode] = parsingFunc
+	}
 }
 
 // New returns new Calculator with provided parsing function
-func New(parsingFunc parser) Calculator {
-	return calculator{parse: parsingFunc}
+func New(parsingFunc parser, options ...Option) Calculator {
+	c := calculator{parse: parsingFunc}
+	for _, option := range options {
+		option(&c)
+	}
+
+	// operations parsed by parsers of modes are cached separately, the same input may be lexed differently
+	if c.cache != nil {
+		c.parse = c.cache.parser("", c.parse)
+		for mode, parse := range c.modeParsers {
+			c.modeParsers[mode] = c.cache.parser(mode, parse)
+		}
+	}
+
+	return c
 }
 
 // Calculate result of mathemtical operation passed as string
@@ -33,5 +71,13 @@ func (c calculator) Calculate(ctx context.Context, input string) (result float64
 	}
 
 	res, err := operation.Calculate(ctx)
-	return res, err
+	if err != nil {
+		return 0, err
+	}
+
+	if err := c.checkSpecial(ctx, operation, res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
 }
@@ -2,9 +2,13 @@ package calculator
 
 import (
 	"context"
+	"math"
 	"strings"
 	"testing"
 
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/mateuszkrasucki/calculator/pkg/errors"
 )
 
@@ -90,3 +94,364 @@ func TestParsingError(t *testing.T) {
 		t.Errorf("expected result to be %v, got %v", expectedResult, result)
 	}
 }
+
+type mockExactOperation struct {
+	mockOperation
+}
+
+func (o *mockExactOperation) CalculateExact(_ context.Context) (string, error) {
+	return "exact " + o.Operation, nil
+}
+
+func TestCalculateExact(t *testing.T) {
+	tests := []struct {
+		name           string
+		parser         parser
+		expectedResult float64
+		expectedExact  string
+		expectedError  error
+	}{
+		{
+			"Exact operation",
+			func(_ context.Context, operation string) (OperationInterface, error) {
+				return &mockExactOperation{mockOperation{Operation: operation}}, nil
+			},
+			3,
+			"exact 2+2",
+			nil,
+		},
+		{
+			"Operation without exact result",
+			mockParser,
+			3,
+			"",
+			nil,
+		},
+		{
+			"Parsing error",
+			mockParserError,
+			0,
+			"",
+			errors.NewParsingError("2+2"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, exact, err := New(tt.parser).(ExactCalculator).CalculateExact(context.Background(), "2+2")
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if result != tt.expectedResult {
+				t.Errorf("expected result to be %v, got %v", tt.expectedResult, result)
+			}
+
+			if exact != tt.expectedExact {
+				t.Errorf("expected exact result to be %s, got %s", tt.expectedExact, exact)
+			}
+		})
+	}
+}
+
+type mockValue string
+
+func (v mockValue) Float64() float64 {
+	return float64(len(v))
+}
+
+func (v mockValue) String() string {
+	return string(v)
+}
+
+type mockModeOperation struct {
+	mockOperation
+}
+
+func (o *mockModeOperation) CalculateMode(_ context.Context, mode Mode) (Value, error) {
+	if mode.Rates != nil {
+		return mockValue(mode.Name + " " + mode.Rates.Date + " " + o.Operation), nil
+	}
+
+	return mockValue(mode.Name + " " + o.Operation), nil
+}
+
+func mockModeParser(_ context.Context, operation string) (OperationInterface, error) {
+	return &mockModeOperation{mockOperation{Operation: operation}}, nil
+}
+
+func TestCalculateMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		calculator    Calculator
+		expectedValue Value
+		expectedError error
+	}{
+		{"Operation supporting modes", New(mockModeParser), mockValue("bigfloat 2+2"), nil},
+		{"Operation without modes", New(mockParser), nil, errors.NewInputError("Mode bigfloat is not supported")},
+		{"Parsing error", New(mockParserError), nil, errors.NewParsingError("2+2")},
+		{"Validated calculator", ValidateMiddleware()(New(mockModeParser)), mockValue("bigfloat 2+2"), nil},
+		{"Logged calculator", ServiceLoggingMiddleware(log.NewNopLogger())(New(mockModeParser)), mockValue("bigfloat 2+2"), nil},
+		{"Calculator without modes", ValidateMiddleware()(NewMockCalculator(nil)), nil, errors.NewInputError("Mode bigfloat is not supported")},
+		{"Calculator with rates", New(mockModeParser, WithRates(&Rates{Date: "2026-10-16"})), mockValue("bigfloat 2026-10-16 2+2"), nil},
+		{"Parser of mode", New(mockParser, WithModeParser(BigFloatMode, mockModeParser)), mockValue("bigfloat 2+2"), nil},
+		{"Parser of other mode", New(mockModeParser, WithModeParser(TimeMode, mockParser)), mockValue("bigfloat 2+2"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := calculateMode(context.Background(), tt.calculator, "2+2", Mode{Name: BigFloatMode})
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if value != tt.expectedValue {
+				t.Errorf("expected value to be %v, got %v", tt.expectedValue, value)
+			}
+		})
+	}
+}
+
+type mockFractionValue struct {
+	mockValue
+	fraction    string
+	approximate bool
+}
+
+func (v mockFractionValue) Fraction() string {
+	return v.fraction
+}
+
+func (v mockFractionValue) Mixed() string {
+	return "mixed " + v.fraction
+}
+
+func (v mockFractionValue) Approximate() bool {
+	return v.approximate
+}
+
+type mockComplexValue complex128
+
+func (v mockComplexValue) Float64() float64 {
+	return real(v)
+}
+
+func (v mockComplexValue) String() string {
+	return "complex"
+}
+
+func (v mockComplexValue) Complex() complex128 {
+	return complex128(v)
+}
+
+type mockIntervalValue [2]float64
+
+func (v mockIntervalValue) Float64() float64 {
+	return (v[0] + v[1]) / 2
+}
+
+func (v mockIntervalValue) String() string {
+	return "interval"
+}
+
+func (v mockIntervalValue) Bounds() (float64, float64) {
+	return v[0], v[1]
+}
+
+type mockSignificantValue float64
+
+func (v mockSignificantValue) Float64() float64 {
+	return float64(v)
+}
+
+func (v mockSignificantValue) String() string {
+	return "8.5"
+}
+
+func (v mockSignificantValue) Significance() *Significance {
+	return &Significance{Figures: 2, DecimalPlaces: 1}
+}
+
+type mockQuantityValue string
+
+func (v mockQuantityValue) Float64() float64 {
+	return 5.3
+}
+
+func (v mockQuantityValue) String() string {
+	return "5.3 " + string(v)
+}
+
+func (v mockQuantityValue) Quantity() *Quantity {
+	if v == "" {
+		return nil
+	}
+
+	return &Quantity{Value: 5.3, Unit: string(v), Dimension: "length", Formatted: v.String()}
+}
+
+type mockTimeValue string
+
+func (v mockTimeValue) Float64() float64 {
+	return 48000
+}
+
+func (v mockTimeValue) String() string {
+	return string(v)
+}
+
+func (v mockTimeValue) Time() *Time {
+	if v == "" {
+		return nil
+	}
+
+	return &Time{Kind: "duration", Formatted: v.String(), ISO: "PT13H20M", Seconds: 48000}
+}
+
+func TestModeResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Value
+		expected Response
+	}{
+		{"Decimal value", mockValue("0.5"), Response{Operation: "1/2", Result: 3, Decimal: "0.5"}},
+		{
+			"Fraction value",
+			mockFractionValue{mockValue("0.5"), "1/2", false},
+			Response{Operation: "1/2", Result: 3, Decimal: "0.5", Fraction: "1/2", Mixed: "mixed 1/2"},
+		},
+		{
+			"Approximate value",
+			mockFractionValue{mockValue("0.5"), "", true},
+			Response{Operation: "1/2", Result: 3, Decimal: "0.5", Mixed: "mixed ", Approximate: true},
+		},
+		{
+			"Complex value",
+			mockComplexValue(complex(3, 4)),
+			Response{Operation: "1/2", Result: 3, Complex: &Complex{Real: 3, Imag: 4, Formatted: "complex"}},
+		},
+		{
+			"Interval value",
+			mockIntervalValue{1, 2},
+			Response{Operation: "1/2", Result: 1.5, Interval: &Interval{Lower: 1, Upper: 2, Formatted: "interval"}},
+		},
+		{
+			"Significant value",
+			mockSignificantValue(8.5),
+			Response{Operation: "1/2", Result: 8.5, Decimal: "8.5", Significance: &Significance{Figures: 2, DecimalPlaces: 1}},
+		},
+		{
+			"Quantity value",
+			mockQuantityValue("km"),
+			Response{Operation: "1/2", Result: 5.3, Quantity: &Quantity{Value: 5.3, Unit: "km", Dimension: "length", Formatted: "5.3 km"}},
+		},
+		{
+			"Dimensionless quantity value",
+			mockQuantityValue(""),
+			Response{Operation: "1/2", Result: 5.3, Decimal: "5.3 "},
+		},
+		{
+			"Time value",
+			mockTimeValue("13h 20m"),
+			Response{Operation: "1/2", Result: 48000, Time: &Time{Kind: "duration", Formatted: "13h 20m", ISO: "PT13H20M", Seconds: 48000}},
+		},
+		{
+			"Number of time mode",
+			mockTimeValue(""),
+			Response{Operation: "1/2", Result: 48000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := modeResponse("1/2", tt.value)
+
+			if !cmp.Equal(tt.expected, response) {
+				t.Errorf("expected response to be %v, got %v", tt.expected, response)
+			}
+		})
+	}
+}
+
+type mockNonFiniteOperation struct {
+	result        float64
+	subexpression string
+}
+
+func (o mockNonFiniteOperation) Calculate(_ context.Context) (float64, error) {
+	return o.result, nil
+}
+
+func (o mockNonFiniteOperation) NonFinite(_ context.Context) (string, error) {
+	return o.subexpression, nil
+}
+
+func TestCalculateSpecialValues(t *testing.T) {
+	tests := []struct {
+		name           string
+		policy         SpecialValues
+		operation      OperationInterface
+		expectedResult string
+		expectedError  error
+	}{
+		{"IEEE infinity", IEEESpecialValues, mockNonFiniteOperation{math.Inf(1), "1 / 0"}, "Infinity", nil},
+		{"IEEE NaN", IEEESpecialValues, mockNonFiniteOperation{math.NaN(), "0 / 0"}, "NaN", nil},
+		{"Default policy", "", mockNonFiniteOperation{math.Inf(-1), "-1 / 0"}, "-Infinity", nil},
+		{"Strict finite", StrictSpecialValues, mockNonFiniteOperation{2, ""}, "", nil},
+		{
+			"Strict infinity",
+			StrictSpecialValues,
+			mockNonFiniteOperation{math.Inf(1), "1 / 0"},
+			"",
+			errors.NewCalculationError("result of 1 / 0 is not a finite number"),
+		},
+		{
+			"Strict NaN without sub-expression",
+			StrictSpecialValues,
+			mockNonFiniteOperation{math.NaN(), ""},
+			"",
+			errors.NewCalculationError("result is NaN"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parse := func(context.Context, string) (OperationInterface, error) {
+				return tt.operation, nil
+			}
+			c := New(parse, WithSpecialValues(tt.policy))
+
+			result, err := c.Calculate(context.Background(), "")
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && err != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if special := FormatSpecial(result); special != tt.expectedResult {
+				t.Errorf("expected special result to be %v, got %v", tt.expectedResult, special)
+			}
+		})
+	}
+}
+
+func TestParseSpecialValues(t *testing.T) {
+	for _, name := range []string{"ieee", "strict"} {
+		if policy, err := ParseSpecialValues(name); err != nil || string(policy) != name {
+			t.Errorf("expected policy %v, got %v, %v", name, policy, err)
+		}
+	}
+
+	if _, err := ParseSpecialValues("quiet"); err == nil || !strings.Contains(err.Error(), "Unknown special values policy: quiet") {
+		t.Errorf("expected unknown policy error, got %v", err)
+	}
+}
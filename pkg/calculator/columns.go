@@ -0,0 +1,23 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+)
+
+// RowError is an error of evaluation of a single row of columns, rows are numbered from 0
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+// ColumnProgram is a compiled operation which can be evaluated over columns of values of its variables,
+// it returns column of results and errors of rows which could not be calculated
+type ColumnProgram interface {
+	Program
+	EvalColumns(context.Context, map[string][]float64) ([]float64, []RowError, error)
+}
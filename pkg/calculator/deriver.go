@@ -0,0 +1,19 @@
+package calculator
+
+import (
+	"context"
+)
+
+// Derivative is simplified derivative written in canonical infix notation together with its tree,
+// value is the derivative calculated at requested point
+type Derivative struct {
+	Derivative string   `json:"derivative"`
+	Tree       string   `json:"tree"`
+	Value      *float64 `json:"value,omitempty"`
+}
+
+// Deriver interface, accepts context, notation, string representing mathematical operation, variable
+// to derive with respect to and values of variables at which derivative is calculated
+type Deriver interface {
+	Derive(context.Context, string, string, string, map[string]float64) (Derivative, error)
+}
@@ -2,27 +2,135 @@ package calculator
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-kit/kit/endpoint"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
 )
 
+// InfixNotation is the default notation of operations in requests
+const InfixNotation = "infix"
+
 // Request definition
 type Request struct {
-	Operation string `json:"operation"`
+	Operation  string             `json:"operation"`
+	Notation   string             `json:"notation,omitempty"`
+	Render     []string           `json:"render,omitempty"`
+	Variable   string             `json:"variable,omitempty"`
+	At         map[string]float64 `json:"at,omitempty"`
+	Other      string             `json:"other,omitempty"`
+	Mode       string             `json:"mode,omitempty"`
+	Precision  uint               `json:"precision,omitempty"`
+	Digits     uint               `json:"digits,omitempty"`
+	Rounding   string             `json:"rounding,omitempty"`
+	Strict     bool               `json:"strict,omitempty"`
+	Scale      *uint              `json:"scale,omitempty"`
+	Zone       string             `json:"zone,omitempty"`
+	TimeFormat string             `json:"time_format,omitempty"`
 }
 
 // Response definition
 type Response struct {
-	Operation string  `json:"operation,omitempty"`
-	Result    float64 `json:"result"`
+	Operation    string            `json:"operation,omitempty"`
+	Result       float64           `json:"result"`
+	Exact        string            `json:"exact,omitempty"`
+	Decimal      string            `json:"decimal,omitempty"`
+	Fraction     string            `json:"fraction,omitempty"`
+	Mixed        string            `json:"mixed,omitempty"`
+	Approximate  bool              `json:"approximate,omitempty"`
+	Complex      *Complex          `json:"complex,omitempty"`
+	Interval     *Interval         `json:"interval,omitempty"`
+	Significance *Significance     `json:"significance,omitempty"`
+	Quantity     *Quantity         `json:"quantity,omitempty"`
+	Time         *Time             `json:"time,omitempty"`
+	Rendered     map[string]string `json:"rendered,omitempty"`
+	RenderErrors map[string]string `json:"render_errors,omitempty"`
+	Warnings     []Warning         `json:"warnings,omitempty"`
+	LintError    string            `json:"lint_error,omitempty"`
+}
+
+// EvaluateRequest definition, expression is evaluated with bindings or with every set of bindings
+type EvaluateRequest struct {
+	Expression  string               `json:"expression"`
+	Notation    string               `json:"notation,omitempty"`
+	Bindings    map[string]float64   `json:"bindings,omitempty"`
+	BindingSets []map[string]float64 `json:"binding_sets,omitempty"`
+}
+
+// EvaluateResponse definition, results are in the order of binding sets
+type EvaluateResponse struct {
+	Variables []string  `json:"variables"`
+	Result    *float64  `json:"result,omitempty"`
+	Results   []float64 `json:"results,omitempty"`
+}
+
+// ProgramRequest definition, program is run with math library if it is requested
+type ProgramRequest struct {
+	Program string `json:"program"`
+	MathLib bool   `json:"mathlib,omitempty"`
+}
+
+// ProgramResponse definition
+type ProgramResponse struct {
+	Output string `json:"output"`
+}
+
+// FormatResponse definition
+type FormatResponse struct {
+	Formatted string `json:"formatted"`
+}
+
+// Endpoints collects endpoints of the service, nil endpoints are not exposed by transports
+type Endpoints struct {
+	Calculate  endpoint.Endpoint
+	Format     endpoint.Endpoint
+	Explain    endpoint.Endpoint
+	Simplify   endpoint.Endpoint
+	Derive     endpoint.Endpoint
+	Equivalent endpoint.Endpoint
+	Evaluate   endpoint.Endpoint
+	BC         endpoint.Endpoint
 }
 
-// MakeEndpoint creates endpoint for calculator
-func MakeEndpoint(c Calculator) endpoint.Endpoint {
+// Notations maps notation names accepted in requests to calculators parsing operations in them
+type Notations map[string]Calculator
+
+// MakeEndpoint creates endpoint for calculator, operations in notations other than infix are passed to calculators from notations,
+// operations with mode in the request are calculated in that mode
+func MakeEndpoint(c Calculator, notations Notations) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(Request)
 
-		result, err := c.Calculate(ctx, req.Operation)
+		calc, err := notations.calculator(c, req.Notation)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Mode != "" {
+			mode := Mode{Name: req.Mode, Precision: req.Precision, Digits: req.Digits, Rounding: req.Rounding, Strict: req.Strict, Scale: req.Scale, Zone: req.Zone, TimeFormat: req.TimeFormat}
+			value, err := calculateMode(ctx, calc, req.Operation, mode)
+			if err != nil {
+				return nil, err
+			}
+
+			return modeResponse(req.Operation, value), nil
+		}
+
+		if exactCalc, ok := calc.(ExactCalculator); ok {
+			result, exact, err := exactCalc.CalculateExact(ctx, req.Operation)
+			if err != nil {
+				return nil, err
+			}
+
+			return Response{
+				Operation: req.Operation,
+				Result:    result,
+				Exact:     exact,
+			}, nil
+		}
+
+		result, err := calc.Calculate(ctx, req.Operation)
 
 		if err != nil {
 			return nil, err
@@ -34,3 +142,135 @@ func MakeEndpoint(c Calculator) endpoint.Endpoint {
 		}, nil
 	}
 }
+
+// MakeFormatEndpoint creates endpoint for formatter
+func MakeFormatEndpoint(f Formatter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+
+		formatted, err := f.Format(ctx, req.Notation, req.Operation)
+		if err != nil {
+			return nil, err
+		}
+
+		return FormatResponse{Formatted: formatted}, nil
+	}
+}
+
+// MakeExplainEndpoint creates endpoint for explainer
+func MakeExplainEndpoint(e Explainer) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+
+		explanation, err := e.Explain(ctx, req.Notation, req.Operation)
+		if err != nil {
+			return nil, err
+		}
+
+		return explanation, nil
+	}
+}
+
+// MakeSimplifyEndpoint creates endpoint for simplifier
+func MakeSimplifyEndpoint(s Simplifier) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+
+		simplification, err := s.Simplify(ctx, req.Notation, req.Operation)
+		if err != nil {
+			return nil, err
+		}
+
+		return simplification, nil
+	}
+}
+
+// MakeDeriveEndpoint creates endpoint for deriver
+func MakeDeriveEndpoint(d Deriver) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+
+		derivative, err := d.Derive(ctx, req.Notation, req.Operation, req.Variable, req.At)
+		if err != nil {
+			return nil, err
+		}
+
+		return derivative, nil
+	}
+}
+
+// MakeEquivalentEndpoint creates endpoint for equivalence checker, operation is compared with the other one
+func MakeEquivalentEndpoint(c EquivalenceChecker) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+
+		equivalence, err := c.Equivalent(ctx, req.Notation, req.Operation, req.Other)
+		if err != nil {
+			return nil, err
+		}
+
+		return equivalence, nil
+	}
+}
+
+// MakeEvaluateEndpoint creates endpoint for compiler, expression is compiled once for all binding sets
+func MakeEvaluateEndpoint(c Compiler) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(EvaluateRequest)
+
+		program, err := c.Compile(ctx, req.Notation, req.Expression)
+		if err != nil {
+			return nil, err
+		}
+
+		response := EvaluateResponse{Variables: program.Variables()}
+
+		if len(req.BindingSets) == 0 {
+			result, err := program.Eval(ctx, req.Bindings)
+			if err != nil {
+				return nil, err
+			}
+			response.Result = &result
+
+			return response, nil
+		}
+
+		response.Results = make([]float64, 0, len(req.BindingSets))
+		for _, bindings := range req.BindingSets {
+			result, err := program.Eval(ctx, bindings)
+			if err != nil {
+				return nil, err
+			}
+			response.Results = append(response.Results, result)
+		}
+
+		return response, nil
+	}
+}
+
+// MakeBCEndpoint creates endpoint for interpreter of bc programs
+func MakeBCEndpoint(i Interpreter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ProgramRequest)
+
+		output, err := i.Run(ctx, req.Program, req.MathLib)
+		if err != nil {
+			return nil, err
+		}
+
+		return ProgramResponse{Output: output}, nil
+	}
+}
+
+func (n Notations) calculator(infix Calculator, notation string) (Calculator, error) {
+	if notation == "" || notation == InfixNotation {
+		return infix, nil
+	}
+
+	c, ok := n[notation]
+	if !ok {
+		return nil, errors.NewInputError(fmt.Sprintf("Unknown notation: %s", notation))
+	}
+
+	return c, nil
+}
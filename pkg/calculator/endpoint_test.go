@@ -0,0 +1,201 @@
+package calculator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestEndpointNotations(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	infixMock := NewMockCalculator(mockCtrl)
+	latexMock := NewMockCalculator(mockCtrl)
+	e := MakeEndpoint(infixMock, Notations{"latex": latexMock})
+
+	tests := []struct {
+		name       string
+		request    Request
+		infixCalls int
+		latexCalls int
+		response   interface{}
+		err        error
+	}{
+		{
+			"Default notation",
+			Request{Operation: "2+2"},
+			1,
+			0,
+			Response{Operation: "2+2", Result: 4.0},
+			nil,
+		},
+		{
+			"Infix notation",
+			Request{Operation: "2+2", Notation: InfixNotation},
+			1,
+			0,
+			Response{Operation: "2+2", Result: 4.0},
+			nil,
+		},
+		{
+			"LaTeX notation",
+			Request{Operation: "2+2", Notation: "latex"},
+			0,
+			1,
+			Response{Operation: "2+2", Result: 4.0},
+			nil,
+		},
+		{
+			"Unknown notation",
+			Request{Operation: "2+2", Notation: "mathml"},
+			0,
+			0,
+			nil,
+			errors.NewInputError("Unknown notation: mathml"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infixMock.EXPECT().Calculate(gomock.Any(), tt.request.Operation).Return(4.0, nil).Times(tt.infixCalls)
+			latexMock.EXPECT().Calculate(gomock.Any(), tt.request.Operation).Return(4.0, nil).Times(tt.latexCalls)
+
+			response, err := e(context.Background(), tt.request)
+
+			if (tt.err != nil && err == nil) || (tt.err == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.err, err)
+			}
+
+			if tt.err != nil && err != nil && err.Error() != tt.err.Error() {
+				t.Fatalf("expected error to be %v, got %v", tt.err, err)
+			}
+
+			if !cmp.Equal(tt.response, response) {
+				t.Errorf("expected response to be %v, got %v", tt.response, response)
+			}
+		})
+	}
+}
+
+func TestEndpointMode(t *testing.T) {
+	e := MakeEndpoint(New(mockModeParser), Notations{"latex": New(mockParser)})
+
+	response, err := e(context.Background(), Request{Operation: "2+2", Mode: BigFloatMode, Digits: 10})
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	expected := Response{Operation: "2+2", Result: 12, Decimal: "bigfloat 2+2"}
+	if !cmp.Equal(expected, response) {
+		t.Errorf("expected response to be %v, got %v", expected, response)
+	}
+
+	if _, err := e(context.Background(), Request{Operation: "2+2", Notation: "latex", Mode: BigFloatMode}); err == nil {
+		t.Errorf("expected error of notation without modes, got nil")
+	}
+}
+
+func TestSimplifyEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	simplifierMock := NewMockSimplifier(mockCtrl)
+	e := MakeSimplifyEndpoint(simplifierMock)
+
+	expected := Simplification{Simplified: "2 * x", Tree: "*\n├── 2\n└── x\n"}
+	simplifierMock.EXPECT().Simplify(gomock.Any(), "", "x + x").Return(expected, nil)
+
+	response, err := e(context.Background(), Request{Operation: "x + x"})
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	if !cmp.Equal(expected, response) {
+		t.Errorf("expected response to be %v, got %v", expected, response)
+	}
+}
+
+func TestEvaluateEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	compilerMock := NewMockCompiler(mockCtrl)
+	programMock := NewMockProgram(mockCtrl)
+	e := MakeEvaluateEndpoint(compilerMock)
+
+	one, two := 1.0, 2.0
+
+	tests := []struct {
+		name      string
+		request   EvaluateRequest
+		evalCalls int
+		response  interface{}
+	}{
+		{
+			"Single bindings",
+			EvaluateRequest{Expression: "x", Bindings: map[string]float64{"x": 1}},
+			1,
+			EvaluateResponse{Variables: []string{"x"}, Result: &one},
+		},
+		{
+			"Binding sets",
+			EvaluateRequest{Expression: "x", BindingSets: []map[string]float64{{"x": 1}, {"x": 2}}},
+			2,
+			EvaluateResponse{Variables: []string{"x"}, Results: []float64{one, two}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compilerMock.EXPECT().Compile(gomock.Any(), "", tt.request.Expression).Return(programMock, nil)
+			programMock.EXPECT().Variables().Return([]string{"x"})
+			programMock.EXPECT().Eval(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, bindings map[string]float64) (float64, error) {
+				return bindings["x"], nil
+			}).Times(tt.evalCalls)
+
+			response, err := e(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			if !cmp.Equal(tt.response, response) {
+				t.Errorf("expected response to be %v, got %v", tt.response, response)
+			}
+		})
+	}
+
+	compilerMock.EXPECT().Compile(gomock.Any(), "", "(").Return(nil, errors.NewParsingError("mismatched parantheses"))
+	if _, err := e(context.Background(), EvaluateRequest{Expression: "("}); err == nil {
+		t.Errorf("expected parsing error, got nil")
+	}
+}
+
+func TestBCEndpoint(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	interpreterMock := NewMockInterpreter(mockCtrl)
+	e := MakeBCEndpoint(interpreterMock)
+
+	interpreterMock.EXPECT().Run(gomock.Any(), "s(0)", true).Return("0\n", nil)
+
+	response, err := e(context.Background(), ProgramRequest{Program: "s(0)", MathLib: true})
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	expected := ProgramResponse{Output: "0\n"}
+	if !cmp.Equal(expected, response) {
+		t.Errorf("expected response to be %v, got %v", expected, response)
+	}
+
+	interpreterMock.EXPECT().Run(gomock.Any(), "1/0", false).Return("", errors.NewCalculationError("divide by zero"))
+	if _, err := e(context.Background(), ProgramRequest{Program: "1/0"}); err == nil {
+		t.Errorf("expected calculation error, got nil")
+	}
+}
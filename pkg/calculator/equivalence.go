@@ -0,0 +1,24 @@
+package calculator
+
+import (
+	"context"
+)
+
+// Counterexample is an assignment of variables for which expressions have different values
+type Counterexample struct {
+	Bindings map[string]float64 `json:"bindings"`
+	First    float64            `json:"first"`
+	Second   float64            `json:"second"`
+}
+
+// Equivalence is a verdict whether expressions are equivalent and the method used to decide it
+type Equivalence struct {
+	Equivalent     bool            `json:"equivalent"`
+	Method         string          `json:"method"`
+	Counterexample *Counterexample `json:"counterexample,omitempty"`
+}
+
+// EquivalenceChecker interface, accepts context, notation and strings representing two mathematical operations to be compared
+type EquivalenceChecker interface {
+	Equivalent(context.Context, string, string, string) (Equivalence, error)
+}
@@ -0,0 +1,48 @@
+package calculator
+
+import (
+	"context"
+)
+
+// ExactOperation is an operation calculated with arbitrary precision, CalculateExact returns its result
+// written without rounding, e.g. as an integer or a fraction
+type ExactOperation interface {
+	OperationInterface
+	CalculateExact(context.Context) (string, error)
+}
+
+// ExactCalculator is a Calculator returning also exact results of operations which support them,
+// exact result is empty for other operations
+type ExactCalculator interface {
+	Calculator
+	CalculateExact(context.Context, string) (float64, string, error)
+}
+
+// CalculateExact returns result of mathematical operation passed as string and its exact representation
+func (c calculator) CalculateExact(ctx context.Context, input string) (float64, string, error) {
+	operation, err := c.parse(ctx, input)
+	if err != nil {
+		return 0, "", err
+	}
+
+	result, err := operation.Calculate(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if err := c.checkSpecial(ctx, operation, result); err != nil {
+		return 0, "", err
+	}
+
+	exact, ok := operation.(ExactOperation)
+	if !ok {
+		return result, "", nil
+	}
+
+	exactResult, err := exact.CalculateExact(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return result, exactResult, nil
+}
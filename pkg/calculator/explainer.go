@@ -0,0 +1,35 @@
+package calculator
+
+import (
+	"context"
+)
+
+// Token is a single item returned by lexer
+type Token struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Step is a single step of calculation, stack is the state after the step
+type Step struct {
+	Item     string    `json:"item"`
+	Action   string    `json:"action"`
+	Operands []float64 `json:"operands,omitempty"`
+	Result   float64   `json:"result"`
+	Stack    []float64 `json:"stack"`
+}
+
+// Explanation describes how operation was lexed, parsed and calculated
+type Explanation struct {
+	Tokens  []Token  `json:"tokens"`
+	Postfix []string `json:"postfix"`
+	Tree    string   `json:"tree"`
+	Dot     string   `json:"dot"`
+	Steps   []Step   `json:"steps"`
+	Result  float64  `json:"result"`
+}
+
+// Explainer interface, accepts context, notation and string representing mathematical operation to be explained
+type Explainer interface {
+	Explain(context.Context, string, string) (Explanation, error)
+}
@@ -0,0 +1,56 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+type formatter func(OperationInterface) (string, error)
+
+// Parsers maps notation names to functions parsing operations written in them
+type Parsers map[string]parser
+
+// Formatter interface, accepts context, notation and string representing mathematical operation to be formatted
+type Formatter interface {
+	Format(context.Context, string, string) (string, error)
+}
+
+type operationFormatter struct {
+	parsers Parsers
+	format  formatter
+}
+
+// NewFormatter returns new Formatter with provided parsing functions and formatting function
+func NewFormatter(parsers Parsers, formattingFunc formatter) Formatter {
+	return operationFormatter{parsers: parsers, format: formattingFunc}
+}
+
+// Format parses operation written in notation and returns it formatted
+func (f operationFormatter) Format(ctx context.Context, notation string, input string) (string, error) {
+	parse, err := f.parsers.parser(notation)
+	if err != nil {
+		return "", err
+	}
+
+	operation, err := parse(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return f.format(operation)
+}
+
+func (p Parsers) parser(notation string) (parser, error) {
+	if notation == "" {
+		notation = InfixNotation
+	}
+
+	parse, ok := p[notation]
+	if !ok {
+		return nil, errors.NewInputError(fmt.Sprintf("Unknown notation: %s", notation))
+	}
+
+	return parse, nil
+}
@@ -0,0 +1,73 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func mockFormatter(operation OperationInterface) (string, error) {
+	return fmt.Sprintf("formatted %s", operation.(*mockOperation).Operation), nil
+}
+
+func TestFormat(t *testing.T) {
+	f := NewFormatter(Parsers{InfixNotation: mockParser, "error": mockParserError}, mockFormatter)
+
+	tests := []struct {
+		name           string
+		notation       string
+		operation      string
+		expectedResult string
+		expectedError  error
+	}{
+		{
+			"Success default notation",
+			"",
+			"2+2",
+			"formatted 2+2",
+			nil,
+		},
+		{
+			"Success infix notation",
+			InfixNotation,
+			"2+2",
+			"formatted 2+2",
+			nil,
+		},
+		{
+			"Parsing error",
+			"error",
+			"2+2",
+			"",
+			errors.NewParsingError("2+2"),
+		},
+		{
+			"Unknown notation",
+			"latex",
+			"2+2",
+			"",
+			errors.NewInputError("Unknown notation: latex"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := f.Format(context.Background(), tt.notation, tt.operation)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && err != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if result != tt.expectedResult {
+				t.Errorf("expected result to be %v, got %v", tt.expectedResult, result)
+			}
+		})
+	}
+}
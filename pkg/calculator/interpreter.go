@@ -0,0 +1,11 @@
+package calculator
+
+import (
+	"context"
+)
+
+// Interpreter interface, accepts context, program written in calculator language and whether
+// math library should be loaded, returns output of the program
+type Interpreter interface {
+	Run(context.Context, string, bool) (string, error)
+}
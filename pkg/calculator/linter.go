@@ -0,0 +1,16 @@
+package calculator
+
+import (
+	"context"
+)
+
+// Warning describes ambiguous or risky construct found in operation, warnings do not prevent calculation
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Linter interface, accepts context, notation and string representing mathematical operation to be linted
+type Linter interface {
+	Lint(context.Context, string, string) ([]Warning, error)
+}
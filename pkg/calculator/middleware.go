@@ -2,6 +2,7 @@ package calculator
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
@@ -25,14 +26,17 @@ type validateMiddleware struct {
 	next Calculator
 }
 
-func (mw validateMiddleware) Calculate(ctx context.Context, input string) (float64, error) {
-	matched, err := regexp.MatchString("^[ 0-9+\\(\\)\\^\\-*\\/\\.]*$", input)
-	if err != nil {
-		return 0, errors.NewCalcErrorWrap(err, "Validation regex failure")
-	}
+// operationPattern matches operations with numbers, operators and names of functions, constants and
+// variables, so derivatives may be calculated with diff
+const operationPattern = "^[ 0-9a-zA-Z_,+\\(\\)\\^\\-*\\/\\.]*$"
 
-	if matched == false {
-		return 0, errors.NewInputError("Invalid characters in input string")
+// modeOperationPattern matches operations calculated in modes, they may use names of functions, constants,
+// imaginary unit, intervals and timestamps
+const modeOperationPattern = "^[ 0-9a-zA-Z_,:+\\(\\)\\[\\]±\\^\\-*\\/\\.]*$"
+
+func (mw validateMiddleware) Calculate(ctx context.Context, input string) (float64, error) {
+	if err := validate(operationPattern, input); err != nil {
+		return 0, err
 	}
 
 	if strings.TrimSpace(input) == "" {
@@ -42,9 +46,35 @@ func (mw validateMiddleware) Calculate(ctx context.Context, input string) (float
 	return mw.next.Calculate(ctx, input)
 }
 
-// ServiceLoggingMiddleware is a logging middleware for service
+func (mw validateMiddleware) CalculateMode(ctx context.Context, input string, mode Mode) (Value, error) {
+	if err := validate(modeOperationPattern, input); err != nil {
+		return nil, err
+	}
+
+	return calculateMode(ctx, mw.next, input, mode)
+}
+
+// validate returns error if input contains characters not matched by pattern
+func validate(pattern string, input string) error {
+	matched, err := regexp.MatchString(pattern, input)
+	if err != nil {
+		return errors.NewCalcErrorWrap(err, "Validation regex failure")
+	}
+
+	if matched == false {
+		return errors.NewInputError("Invalid characters in input string")
+	}
+
+	return nil
+}
+
+// ServiceLoggingMiddleware is a logging middleware for service, exact calculators remain exact
 func ServiceLoggingMiddleware(log log.Logger) Middleware {
 	return func(next Calculator) Calculator {
+		if exact, ok := next.(ExactCalculator); ok {
+			return exactLoggingMiddleware{loggingMiddleware{log, next}, exact}
+		}
+
 		return loggingMiddleware{log, next}
 	}
 }
@@ -59,6 +89,21 @@ func (mw loggingMiddleware) Calculate(ctx context.Context, input string) (result
 	return mw.next.Calculate(ctx, input)
 }
 
+func (mw loggingMiddleware) CalculateMode(ctx context.Context, input string, mode Mode) (Value, error) {
+	mw.logger.Log("method", "CalculateMode", "operation", input, "mode", mode.Name)
+	return calculateMode(ctx, mw.next, input, mode)
+}
+
+type exactLoggingMiddleware struct {
+	loggingMiddleware
+	exact ExactCalculator
+}
+
+func (mw exactLoggingMiddleware) CalculateExact(ctx context.Context, input string) (float64, string, error) {
+	mw.logger.Log("method", "CalculateExact", "operation", input)
+	return mw.exact.CalculateExact(ctx, input)
+}
+
 // EndpointLoggingMiddleware returns an endpoint middleware that logs the
 // duration of each invocation, and the resulting error, if any.
 func EndpointLoggingMiddleware(logger log.Logger) endpoint.Middleware {
@@ -72,3 +117,105 @@ func EndpointLoggingMiddleware(logger log.Logger) endpoint.Middleware {
 		}
 	}
 }
+
+// Renderers maps names of rendering formats to formatters rendering operations in them
+type Renderers map[string]Formatter
+
+// RenderingMiddleware returns an endpoint middleware that adds to the response
+// renderings of the operation in formats listed in the request. Renderings do not
+// fail the calculation, formats which could not be rendered are reported with their errors.
+func RenderingMiddleware(renderers Renderers) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			response, err := next(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+
+			req := request.(Request)
+			resp := response.(Response)
+
+			if strings.TrimSpace(req.Operation) == "" {
+				return resp, nil
+			}
+
+			for _, format := range req.Render {
+				r, ok := renderers[format]
+				if !ok {
+					resp.addRenderError(format, errors.NewInputError(fmt.Sprintf("Unknown rendering format: %s", format)))
+					continue
+				}
+
+				rendered, err := r.Format(ctx, req.Notation, req.Operation)
+				if err != nil {
+					resp.addRenderError(format, err)
+					continue
+				}
+
+				if resp.Rendered == nil {
+					resp.Rendered = map[string]string{}
+				}
+				resp.Rendered[format] = rendered
+			}
+
+			return resp, nil
+		}
+	}
+}
+
+// addRenderError reports format which could not be rendered
+func (r *Response) addRenderError(format string, err error) {
+	if r.RenderErrors == nil {
+		r.RenderErrors = map[string]string{}
+	}
+	r.RenderErrors[format] = err.Error()
+}
+
+// LintingMiddleware returns an endpoint middleware that adds to the response
+// warnings about ambiguous or risky constructs found in the operation. Only operations
+// in listed notations are linted, or operations in all notations if none are listed. Operations of time mode
+// are not linted, linters lex them without dates and durations. Linting does not fail the calculation,
+// operations which could not be linted are reported with the error.
+func LintingMiddleware(linter Linter, notations ...string) endpoint.Middleware {
+	linted := map[string]bool{}
+	for _, notation := range notations {
+		linted[notation] = true
+	}
+
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			response, err := next(ctx, request)
+			if err != nil {
+				return nil, err
+			}
+
+			req := request.(Request)
+			resp := response.(Response)
+
+			if strings.TrimSpace(req.Operation) == "" || req.Mode == TimeMode {
+				return resp, nil
+			}
+
+			notation := req.Notation
+			if notation == "" {
+				notation = InfixNotation
+			}
+
+			if len(linted) > 0 && !linted[notation] {
+				return resp, nil
+			}
+
+			warnings, err := linter.Lint(ctx, req.Notation, req.Operation)
+			if err != nil {
+				resp.LintError = err.Error()
+				return resp, nil
+			}
+
+			if len(warnings) > 0 {
+				resp.Warnings = warnings
+			}
+
+			return resp, nil
+		}
+	}
+}
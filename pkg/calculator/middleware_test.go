@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/mateuszkrasucki/calculator/pkg/errors"
 )
@@ -52,9 +53,18 @@ func TestValidationMiddleware(t *testing.T) {
 			0.0,
 			nil,
 		},
+		{
+			"Successful validation, functions and constants",
+			"diff(2 * x, x) + sqrt(4)",
+			1,
+			4.0,
+			nil,
+			4.0,
+			nil,
+		},
 		{
 			"Failed validation, invalid character",
-			"2a+2",
+			"2#2",
 			0,
 			0.0,
 			nil,
@@ -86,3 +96,289 @@ func TestValidationMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestValidationMiddlewareMode(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	calcServiceMock := NewMockModeCalculator(mockCtrl)
+	c := ValidateMiddleware()(calcServiceMock).(ModeCalculator)
+	mode := Mode{Name: ComplexMode}
+
+	calcServiceMock.EXPECT().CalculateMode(gomock.Any(), "sqrt(-4) + 3i", mode).Return(mockValue("3+2i"), nil)
+
+	value, err := c.CalculateMode(context.Background(), "sqrt(-4) + 3i", mode)
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	if value != mockValue("3+2i") {
+		t.Errorf("expected value to be 3+2i, got %v", value)
+	}
+
+	intervalMode := Mode{Name: IntervalMode}
+	calcServiceMock.EXPECT().CalculateMode(gomock.Any(), "[1.9, 2.1] * 5 ± 0.2", intervalMode).Return(mockValue("[9.3, 10.7]"), nil)
+
+	if _, err := c.CalculateMode(context.Background(), "[1.9, 2.1] * 5 ± 0.2", intervalMode); err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	timeMode := Mode{Name: TimeMode}
+	calcServiceMock.EXPECT().CalculateMode(gomock.Any(), "2026-10-17T14:30:00+02:00 in Europe/Warsaw", timeMode).Return(mockValue("2026-10-17T14:30:00+02:00"), nil)
+
+	if _, err := c.CalculateMode(context.Background(), "2026-10-17T14:30:00+02:00 in Europe/Warsaw", timeMode); err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	_, err = c.CalculateMode(context.Background(), "2 # 2", mode)
+	if err == nil || err.Error() != errors.NewInputError("Invalid characters in input string").Error() {
+		t.Errorf("expected invalid characters error, got %v", err)
+	}
+}
+
+func TestRenderingMiddleware(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	latexMock := NewMockFormatter(mockCtrl)
+	e := RenderingMiddleware(Renderers{"latex": latexMock})(func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+		if req.Operation == errors.CalculationError {
+			return nil, errors.NewCalculationError("")
+		}
+
+		return Response{Operation: req.Operation, Result: 1}, nil
+	})
+
+	tests := []struct {
+		name           string
+		request        Request
+		formatterCalls int
+		formatterError error
+		rendered       map[string]string
+		renderErrors   map[string]string
+		err            error
+	}{
+		{
+			"Rendering requested",
+			Request{Operation: "1/1", Notation: "infix", Render: []string{"latex"}},
+			1,
+			nil,
+			map[string]string{"latex": "\\frac{1}{1}"},
+			nil,
+			nil,
+		},
+		{
+			"Rendering not requested",
+			Request{Operation: "1/1"},
+			0,
+			nil,
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"Empty operation is not rendered",
+			Request{Operation: " ", Render: []string{"latex"}},
+			0,
+			nil,
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"Error passed from endpoint",
+			Request{Operation: errors.CalculationError, Render: []string{"latex"}},
+			0,
+			nil,
+			nil,
+			nil,
+			errors.NewCalculationError(""),
+		},
+		{
+			"Error from formatter is reported",
+			Request{Operation: "1/1", Render: []string{"latex"}},
+			1,
+			errors.NewParsingError(""),
+			nil,
+			map[string]string{"latex": errors.NewParsingError("").Error()},
+			nil,
+		},
+		{
+			"Unknown rendering format is reported",
+			Request{Operation: "1/1", Render: []string{"svg"}},
+			0,
+			nil,
+			nil,
+			map[string]string{"svg": errors.NewInputError("Unknown rendering format: svg").Error()},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latexMock.EXPECT().
+				Format(gomock.Any(), tt.request.Notation, tt.request.Operation).
+				Return(tt.rendered["latex"], tt.formatterError).
+				Times(tt.formatterCalls)
+
+			response, err := e(context.Background(), tt.request)
+
+			if (tt.err != nil && err == nil) || (tt.err == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.err, err)
+			}
+
+			if tt.err != nil && err != nil && err.Error() != tt.err.Error() {
+				t.Fatalf("expected error to be %v, got %v", tt.err, err)
+			}
+
+			if err == nil && !cmp.Equal(tt.rendered, response.(Response).Rendered) {
+				t.Errorf("expected rendered to be %v, got %v", tt.rendered, response.(Response).Rendered)
+			}
+
+			if err == nil && !cmp.Equal(tt.renderErrors, response.(Response).RenderErrors) {
+				t.Errorf("expected render errors to be %v, got %v", tt.renderErrors, response.(Response).RenderErrors)
+			}
+		})
+	}
+}
+
+func TestLintingMiddleware(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	linterMock := NewMockLinter(mockCtrl)
+	e := LintingMiddleware(linterMock)(func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+		if req.Operation == errors.CalculationError {
+			return nil, errors.NewCalculationError("")
+		}
+
+		return Response{Operation: req.Operation, Result: 1}, nil
+	})
+
+	tests := []struct {
+		name        string
+		request     Request
+		linterCalls int
+		linterError error
+		warnings    []Warning
+		lintError   string
+		err         error
+	}{
+		{
+			"Warnings added",
+			Request{Operation: "1/0*0"},
+			1,
+			nil,
+			[]Warning{{Code: "division-by-zero", Message: "division by zero in 1 / 0"}},
+			"",
+			nil,
+		},
+		{
+			"No warnings",
+			Request{Operation: "1/1"},
+			1,
+			nil,
+			nil,
+			"",
+			nil,
+		},
+		{
+			"Empty operation is not linted",
+			Request{Operation: " "},
+			0,
+			nil,
+			nil,
+			"",
+			nil,
+		},
+		{
+			"Operation of time mode is not linted",
+			Request{Operation: "2026-10-17 + 3h 20m", Mode: TimeMode},
+			0,
+			nil,
+			nil,
+			"",
+			nil,
+		},
+		{
+			"Error passed from endpoint",
+			Request{Operation: errors.CalculationError},
+			0,
+			nil,
+			nil,
+			"",
+			errors.NewCalculationError(""),
+		},
+		{
+			"Error of linter reported in response",
+			Request{Operation: "1/1"},
+			1,
+			errors.NewParsingError(""),
+			nil,
+			errors.NewParsingError("").Error(),
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linterMock.EXPECT().
+				Lint(gomock.Any(), tt.request.Notation, tt.request.Operation).
+				Return(tt.warnings, tt.linterError).
+				Times(tt.linterCalls)
+
+			response, err := e(context.Background(), tt.request)
+
+			if (tt.err != nil && err == nil) || (tt.err == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.err, err)
+			}
+
+			if tt.err != nil && err != nil && err.Error() != tt.err.Error() {
+				t.Fatalf("expected error to be %v, got %v", tt.err, err)
+			}
+
+			if err == nil && !cmp.Equal(tt.warnings, response.(Response).Warnings) {
+				t.Errorf("expected warnings to be %v, got %v", tt.warnings, response.(Response).Warnings)
+			}
+
+			if err == nil && tt.lintError != response.(Response).LintError {
+				t.Errorf("expected lint error to be %q, got %q", tt.lintError, response.(Response).LintError)
+			}
+		})
+	}
+}
+
+func TestLintingMiddlewareNotations(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	linterMock := NewMockLinter(mockCtrl)
+	e := LintingMiddleware(linterMock, InfixNotation, "latex")(func(_ context.Context, request interface{}) (interface{}, error) {
+		return Response{Operation: request.(Request).Operation, Result: 1}, nil
+	})
+
+	tests := []struct {
+		name        string
+		request     Request
+		linterCalls int
+	}{
+		{"Default notation is linted", Request{Operation: "1/1"}, 1},
+		{"Listed notation is linted", Request{Operation: "\\frac{1}{1}", Notation: "latex"}, 1},
+		{"Other notation is not linted", Request{Operation: "1 / 1", Notation: "go"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linterMock.EXPECT().
+				Lint(gomock.Any(), tt.request.Notation, tt.request.Operation).
+				Return(nil, nil).
+				Times(tt.linterCalls)
+
+			if _, err := e(context.Background(), tt.request); err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+		})
+	}
+}
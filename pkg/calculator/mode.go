@@ -0,0 +1,249 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// BigFloatMode calculates operations with binary floating-point numbers of arbitrary precision
+const BigFloatMode = "bigfloat"
+
+// RationalMode calculates operations with exact fractions, operations without rational results
+// are calculated with big floats unless the mode is strict
+const RationalMode = "rational"
+
+// DecimalMode calculates operations with decimal numbers of fixed scale, results of every operation
+// are rounded to scale fractional digits
+const DecimalMode = "decimal"
+
+// ComplexMode calculates operations with complex numbers, i and j are the imaginary unit
+const ComplexMode = "complex"
+
+// IntervalMode calculates operations with closed intervals, e.g. [1.9, 2.1] or 5 ± 0.2, bounds of results
+// are rounded outward, so they always enclose the exact result
+const IntervalMode = "interval"
+
+// SignificantFiguresMode calculates operations with measured numbers, precision of numbers is given by digits
+// written in operation and results are rounded according to significant figures rules
+const SignificantFiguresMode = "sigfig"
+
+// UnitsMode calculates operations with quantities of units of measure, e.g. 5 km + 300 m, results are written
+// in units of operands unless they are converted with in or to, e.g. 60 mph to km/h. Currency codes are units
+// if calculator has exchange rates, sums of different currencies have to be converted, e.g. 100 USD + 50 EUR in PLN
+const UnitsMode = "units"
+
+// TimeMode calculates operations with dates, timestamps and durations, e.g. 2026-10-17 + 45 days, 3h 20m * 4
+// or 2026-12-24 - today, timestamps are converted to time zones with in or to, e.g. now in Europe/Warsaw,
+// and workdays are days from Monday to Friday, e.g. today + 10 workdays
+const TimeMode = "time"
+
+// Mode selects numbers used in calculation of operations instead of float64. Precision is the number
+// of bits of mantissa and digits is the number of significant decimal digits, only one of them should be set.
+// Rounding is one of half-even, half-up, down, up, floor and ceiling, half-even is the default.
+// Strict modes fail instead of approximating results which cannot be exact. Scale is the number of fractional
+// digits of fixed-point modes, nil selects default scale of the mode. Rates are exchange rates of currencies
+// of units mode, they are set by calculators with rates. Zone is the time zone of now, today and timestamps
+// without offset in time mode, local time zone is the default, and TimeFormat is iso, rfc1123, unix
+// or a layout of package time, e.g. 02.01.2006 15:04, iso is the default
+type Mode struct {
+	Name       string
+	Precision  uint
+	Digits     uint
+	Rounding   string
+	Strict     bool
+	Scale      *uint
+	Rates      *Rates
+	Zone       string
+	TimeFormat string
+}
+
+// Value is a result of operation calculated in a mode, String writes it as a decimal number
+type Value interface {
+	Float64() float64
+	String() string
+}
+
+// FractionValue is a value which can be written as a fraction, e.g. 3/2, and as a mixed number, e.g. 1 1/2,
+// both are empty if the value is approximate
+type FractionValue interface {
+	Value
+	Fraction() string
+	Mixed() string
+}
+
+// ComplexValue is a value with imaginary part
+type ComplexValue interface {
+	Value
+	Complex() complex128
+}
+
+// Complex is a complex result of operation with its parts and the number written as a string, e.g. 3+4i
+type Complex struct {
+	Real      float64 `json:"real"`
+	Imag      float64 `json:"imag"`
+	Formatted string  `json:"formatted"`
+}
+
+// IntervalValue is a value of interval mode, bounds are rounded outward to float64
+type IntervalValue interface {
+	Value
+	Bounds() (lower, upper float64)
+}
+
+// Interval is a result of operation in interval mode with its bounds and the interval written as a string
+type Interval struct {
+	Lower     float64 `json:"lower"`
+	Upper     float64 `json:"upper"`
+	Formatted string  `json:"formatted"`
+}
+
+// SignificantValue is a value of measured numbers rounded to its precision
+type SignificantValue interface {
+	Value
+	Significance() *Significance
+}
+
+// Significance is the precision of a measured result, decimal places are negative for results rounded
+// to tens, hundreds and so on
+type Significance struct {
+	Figures       int `json:"figures"`
+	DecimalPlaces int `json:"decimal_places"`
+}
+
+// QuantityValue is a value of units mode, Quantity is nil for dimensionless numbers calculated without exchange rates
+type QuantityValue interface {
+	Value
+	Quantity() *Quantity
+}
+
+// Quantity is a result of operation with unit of measure, value is written in the unit, e.g. 5.3 of km,
+// dimension is the name of measured quantity, e.g. length or velocity, rates date is the date of exchange rates
+// used in calculation of amounts of money
+type Quantity struct {
+	Value     float64 `json:"value"`
+	Unit      string  `json:"unit"`
+	Dimension string  `json:"dimension"`
+	Formatted string  `json:"formatted"`
+	RatesDate string  `json:"rates_date,omitempty"`
+}
+
+// TimeValue is a value of time mode, Time is nil for numbers, e.g. ratios of durations
+type TimeValue interface {
+	Value
+	Time() *Time
+}
+
+// Time is a result of operation in time mode, kind is date, timestamp or duration, ISO is the result written
+// in ISO 8601, e.g. 2026-10-17T14:30:00+02:00 or P45DT3H, unix is the number of seconds since 1970-01-01 UTC
+// of dates and timestamps and seconds is the length of durations, durations of months are calculated
+// with average length of months
+type Time struct {
+	Kind      string  `json:"kind"`
+	Formatted string  `json:"formatted"`
+	ISO       string  `json:"iso,omitempty"`
+	Unix      int64   `json:"unix,omitempty"`
+	Seconds   float64 `json:"seconds,omitempty"`
+	Weekday   string  `json:"weekday,omitempty"`
+	Zone      string  `json:"zone,omitempty"`
+}
+
+// ApproximateValue is a value of an exact mode which had to be approximated
+type ApproximateValue interface {
+	Value
+	Approximate() bool
+}
+
+// ModeOperation is an operation which can be calculated in modes
+type ModeOperation interface {
+	OperationInterface
+	CalculateMode(context.Context, Mode) (Value, error)
+}
+
+// ModeCalculator is a Calculator calculating operations also in modes
+type ModeCalculator interface {
+	Calculator
+	CalculateMode(context.Context, string, Mode) (Value, error)
+}
+
+// CalculateMode returns result of mathematical operation passed as string calculated in mode
+func (c calculator) CalculateMode(ctx context.Context, input string, mode Mode) (Value, error) {
+	parse, ok := c.modeParsers[mode.Name]
+	if !ok {
+		parse = c.parse
+	}
+
+	operation, err := parse(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	modeOperation, ok := operation.(ModeOperation)
+	if !ok {
+		return nil, errors.NewInputError(fmt.Sprintf("Mode %s is not supported", mode.Name))
+	}
+
+	if c.rates != nil {
+		mode.Rates = c.rates.Rates()
+	}
+
+	return modeOperation.CalculateMode(ctx, mode)
+}
+
+// modeResponse returns response with value and all its representations
+func modeResponse(operation string, value Value) Response {
+	response := Response{
+		Operation: operation,
+		Result:    value.Float64(),
+		Decimal:   value.String(),
+	}
+
+	if fraction, ok := value.(FractionValue); ok {
+		response.Fraction = fraction.Fraction()
+		response.Mixed = fraction.Mixed()
+	}
+
+	if approximate, ok := value.(ApproximateValue); ok {
+		response.Approximate = approximate.Approximate()
+	}
+
+	// complex numbers are not decimals, they are written with their parts
+	if complexValue, ok := value.(ComplexValue); ok {
+		c := complexValue.Complex()
+		response.Decimal = ""
+		response.Complex = &Complex{Real: real(c), Imag: imag(c), Formatted: value.String()}
+	}
+
+	if intervalValue, ok := value.(IntervalValue); ok {
+		lower, upper := intervalValue.Bounds()
+		response.Decimal = ""
+		response.Interval = &Interval{Lower: lower, Upper: upper, Formatted: value.String()}
+	}
+
+	if significant, ok := value.(SignificantValue); ok {
+		response.Significance = significant.Significance()
+	}
+
+	if quantityValue, ok := value.(QuantityValue); ok && quantityValue.Quantity() != nil {
+		response.Decimal = ""
+		response.Quantity = quantityValue.Quantity()
+	}
+
+	if timeValue, ok := value.(TimeValue); ok && timeValue.Time() != nil {
+		response.Decimal = ""
+		response.Time = timeValue.Time()
+	}
+
+	return response
+}
+
+// calculateMode calculates input in mode if calculator supports modes
+func calculateMode(ctx context.Context, c Calculator, input string, mode Mode) (Value, error) {
+	modeCalculator, ok := c.(ModeCalculator)
+	if !ok {
+		return nil, errors.NewInputError(fmt.Sprintf("Mode %s is not supported", mode.Name))
+	}
+
+	return modeCalculator.CalculateMode(ctx, input, mode)
+}
@@ -0,0 +1,17 @@
+package calculator
+
+import (
+	"context"
+)
+
+// Program is a compiled operation which can be evaluated many times with different values of variables,
+// it is safe for concurrent use
+type Program interface {
+	Eval(context.Context, map[string]float64) (float64, error)
+	Variables() []string
+}
+
+// Compiler interface, accepts context, notation and string representing mathematical operation to be compiled
+type Compiler interface {
+	Compile(context.Context, string, string) (Program, error)
+}
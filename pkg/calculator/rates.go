@@ -0,0 +1,202 @@
+package calculator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// ratesDateLayout is the layout of dates of exchange rates
+const ratesDateLayout = "2006-01-02"
+
+// currencyPattern matches ISO 4217 currency codes
+var currencyPattern = regexp.MustCompile("^[A-Z]{3}$")
+
+// Rates are exchange rates of currencies from a single day, rates of currencies are their amounts worth
+// the same amount of money, e.g. rate of base currency is 1
+type Rates struct {
+	Date       string
+	Currencies map[string]float64
+}
+
+// RatesSource returns exchange rates used by calculator, rates may change between calculations
+type RatesSource interface {
+	Rates() *Rates
+}
+
+// WithRates makes calculator use exchange rates of the source for currencies in units mode
+func WithRates(source RatesSource) Option {
+	return func(c *calculator) {
+		c.rates = source
+	}
+}
+
+// Rates returns rates themselves, so rates loaded once are a source of rates too
+func (r *Rates) Rates() *Rates {
+	return r
+}
+
+// LoadRates reads exchange rates from JSON file, e.g. {"date": "2026-10-16", "base": "EUR", "rates": {"USD": 1.08}},
+// or from CSV file with date, currency and rate columns and a header, all rates of CSV file have to be from the same day
+func LoadRates(path string) (*Rates, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.NewInputErrorWrap(err, fmt.Sprintf("Failed to open rates file %s", path))
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return decodeCSVRates(f)
+	}
+
+	return decodeJSONRates(f)
+}
+
+func decodeJSONRates(r io.Reader) (*Rates, error) {
+	var file struct {
+		Date  string             `json:"date"`
+		Base  string             `json:"base"`
+		Rates map[string]float64 `json:"rates"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, errors.NewInputErrorWrap(err, "Failed to decode rates")
+	}
+
+	if file.Base != "" {
+		if file.Rates == nil {
+			file.Rates = map[string]float64{}
+		}
+		file.Rates[file.Base] = 1
+	}
+
+	return newRates(file.Date, file.Rates)
+}
+
+func decodeCSVRates(r io.Reader) (*Rates, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, errors.NewInputErrorWrap(err, "Failed to decode rates")
+	}
+
+	if len(records) == 0 || len(records[0]) != 3 {
+		return nil, errors.NewInputError("Rates have to have date, currency and rate columns")
+	}
+
+	date := ""
+	rates := map[string]float64{}
+	for _, record := range records[1:] {
+		if date != "" && record[0] != date {
+			return nil, errors.NewInputError(fmt.Sprintf("Rates have to be from the same day, got %s and %s", date, record[0]))
+		}
+		date = record[0]
+
+		rate, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return nil, errors.NewInputErrorWrap(err, fmt.Sprintf("Invalid rate of %s: %s", record[1], record[2]))
+		}
+		rates[record[1]] = rate
+	}
+
+	return newRates(date, rates)
+}
+
+// newRates validates date, currency codes and rates
+func newRates(date string, rates map[string]float64) (*Rates, error) {
+	if _, err := time.Parse(ratesDateLayout, date); err != nil {
+		return nil, errors.NewInputErrorWrap(err, fmt.Sprintf("Invalid date of rates: %s", date))
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.NewInputError("Rates are empty")
+	}
+
+	for currency, rate := range rates {
+		if !currencyPattern.MatchString(currency) {
+			return nil, errors.NewInputError(fmt.Sprintf("Invalid currency code: %s", currency))
+		}
+		if !(rate > 0) {
+			return nil, errors.NewInputError(fmt.Sprintf("Rate of %s has to be positive", currency))
+		}
+	}
+
+	return &Rates{Date: date, Currencies: rates}, nil
+}
+
+// RatesFile is a source of exchange rates read from file, the file can be reloaded while calculator is running,
+// it is safe for concurrent use
+type RatesFile struct {
+	path     string
+	mu       sync.RWMutex
+	rates    *Rates
+	modified time.Time
+}
+
+// NewRatesFile returns source of rates read from the file
+func NewRatesFile(path string) (*RatesFile, error) {
+	f := &RatesFile{path: path}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Rates returns rates read from the file most recently
+func (f *RatesFile) Rates() *Rates {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.rates
+}
+
+// Reload reads the file again, rates read earlier are kept if the file cannot be read
+func (f *RatesFile) Reload() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return errors.NewInputErrorWrap(err, fmt.Sprintf("Failed to open rates file %s", f.path))
+	}
+
+	rates, err := LoadRates(f.path)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rates, f.modified = rates, info.ModTime()
+
+	return nil
+}
+
+// ReloadIfModified reads the file again if it was modified since it was read, it returns true if rates were reloaded
+func (f *RatesFile) ReloadIfModified() (bool, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, errors.NewInputErrorWrap(err, fmt.Sprintf("Failed to open rates file %s", f.path))
+	}
+
+	f.mu.RLock()
+	modified := f.modified
+	f.mu.RUnlock()
+
+	if info.ModTime().Equal(modified) {
+		return false, nil
+	}
+
+	if err := f.Reload(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,140 @@
+package calculator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestLoadRates(t *testing.T) {
+	tests := []struct {
+		name          string
+		file          string
+		content       string
+		expected      *Rates
+		expectedError error
+	}{
+		{
+			"JSON rates",
+			"rates.json",
+			`{"date": "2026-10-16", "base": "EUR", "rates": {"USD": 1.08, "PLN": 4.32}}`,
+			&Rates{Date: "2026-10-16", Currencies: map[string]float64{"EUR": 1, "USD": 1.08, "PLN": 4.32}},
+			nil,
+		},
+		{
+			"CSV rates",
+			"rates.csv",
+			"date,currency,rate\n2026-10-16,EUR,1\n2026-10-16,USD,1.08\n",
+			&Rates{Date: "2026-10-16", Currencies: map[string]float64{"EUR": 1, "USD": 1.08}},
+			nil,
+		},
+		{
+			"CSV rates from different days",
+			"rates.csv",
+			"date,currency,rate\n2026-10-16,EUR,1\n2026-10-15,USD,1.08\n",
+			nil,
+			errors.NewInputError("Rates have to be from the same day, got 2026-10-16 and 2026-10-15"),
+		},
+		{
+			"Invalid date",
+			"rates.json",
+			`{"date": "16.10.2026", "rates": {"USD": 1.08}}`,
+			nil,
+			errors.NewInputError("Invalid date of rates: 16.10.2026"),
+		},
+		{
+			"Invalid currency code",
+			"rates.json",
+			`{"date": "2026-10-16", "rates": {"usd": 1.08}}`,
+			nil,
+			errors.NewInputError("Invalid currency code: usd"),
+		},
+		{
+			"Rate which is not positive",
+			"rates.json",
+			`{"date": "2026-10-16", "rates": {"USD": 0}}`,
+			nil,
+			errors.NewInputError("Rate of USD has to be positive"),
+		},
+		{
+			"Empty rates",
+			"rates.csv",
+			"date,currency,rate\n",
+			nil,
+			errors.NewInputError("Invalid date of rates"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			rates, err := LoadRates(path)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if !cmp.Equal(tt.expected, rates) {
+				t.Errorf("expected rates to be %v, got %v", tt.expected, rates)
+			}
+		})
+	}
+}
+
+func TestRatesFileReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	write := func(content string, modified time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("expected error to be nil, got %v", err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatalf("expected error to be nil, got %v", err)
+		}
+	}
+
+	modified := time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	write(`{"date": "2026-10-16", "rates": {"USD": 1.08}}`, modified)
+
+	rates, err := NewRatesFile(path)
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	if reloaded, err := rates.ReloadIfModified(); reloaded || err != nil {
+		t.Errorf("expected unmodified file not to be reloaded, got %t, %v", reloaded, err)
+	}
+
+	write(`{"date": "2026-10-17", "rates": {"USD": 1.09}}`, modified.Add(24*time.Hour))
+
+	if reloaded, err := rates.ReloadIfModified(); !reloaded || err != nil {
+		t.Errorf("expected modified file to be reloaded, got %t, %v", reloaded, err)
+	}
+
+	if date := rates.Rates().Date; date != "2026-10-17" {
+		t.Errorf("expected rates date to be 2026-10-17, got %s", date)
+	}
+
+	write(`{"date": "2026-10-18"`, modified.Add(48*time.Hour))
+
+	if reloaded, err := rates.ReloadIfModified(); reloaded || err == nil {
+		t.Errorf("expected invalid file not to be reloaded, got %t, %v", reloaded, err)
+	}
+
+	if date := rates.Rates().Date; date != "2026-10-17" {
+		t.Errorf("expected rates loaded earlier to be kept, got rates of %s", date)
+	}
+}
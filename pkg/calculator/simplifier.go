@@ -0,0 +1,16 @@
+package calculator
+
+import (
+	"context"
+)
+
+// Simplification is simplified operation written in canonical infix notation together with its tree
+type Simplification struct {
+	Simplified string `json:"simplified"`
+	Tree       string `json:"tree"`
+}
+
+// Simplifier interface, accepts context, notation and string representing mathematical operation to be simplified
+type Simplifier interface {
+	Simplify(context.Context, string, string) (Simplification, error)
+}
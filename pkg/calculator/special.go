@@ -0,0 +1,192 @@
+package calculator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// SpecialValues is a policy for results which are not finite numbers, e.g. of 1 / 0 or 0 / 0
+type SpecialValues string
+
+const (
+	// IEEESpecialValues returns infinities and NaN as results, they are written as Infinity, -Infinity and NaN
+	IEEESpecialValues SpecialValues = "ieee"
+	// StrictSpecialValues fails calculations with results which are not finite numbers
+	StrictSpecialValues SpecialValues = "strict"
+)
+
+// NonFiniteOperation is an operation which can find its innermost sub-expression with result which is not
+// a finite number, NonFinite returns empty string if results of all sub-expressions are finite
+type NonFiniteOperation interface {
+	OperationInterface
+	NonFinite(context.Context) (string, error)
+}
+
+// WithSpecialValues sets policy for results which are not finite numbers, IEEESpecialValues is the default
+func WithSpecialValues(policy SpecialValues) Option {
+	return func(c *calculator) {
+		c.specialValues = policy
+	}
+}
+
+// ParseSpecialValues returns policy with the given name
+func ParseSpecialValues(name string) (SpecialValues, error) {
+	switch policy := SpecialValues(name); policy {
+	case IEEESpecialValues, StrictSpecialValues:
+		return policy, nil
+	default:
+		return "", errors.NewInputError(fmt.Sprintf("Unknown special values policy: %s", name))
+	}
+}
+
+// FormatSpecial returns Infinity, -Infinity or NaN for results which are not finite numbers
+// and empty string for finite ones
+func FormatSpecial(x float64) string {
+	switch {
+	case math.IsInf(x, 1):
+		return "Infinity"
+	case math.IsInf(x, -1):
+		return "-Infinity"
+	case math.IsNaN(x):
+		return "NaN"
+	default:
+		return ""
+	}
+}
+
+// checkSpecial returns CalculationError naming the sub-expression which caused result not to be finite
+// if the policy is strict
+func (c calculator) checkSpecial(ctx context.Context, operation OperationInterface, result float64) error {
+	special := FormatSpecial(result)
+	if c.specialValues != StrictSpecialValues || special == "" {
+		return nil
+	}
+
+	locator, ok := operation.(NonFiniteOperation)
+	if !ok {
+		return errors.NewCalculationError(fmt.Sprintf("result is %s", special))
+	}
+
+	subexpression, err := locator.NonFinite(ctx)
+	if err != nil {
+		return err
+	}
+	if subexpression == "" {
+		return errors.NewCalculationError(fmt.Sprintf("result is %s", special))
+	}
+
+	return errors.NewCalculationError(fmt.Sprintf("result of %s is not a finite number", subexpression))
+}
+
+// MarshalJSON writes result which is not a finite number as a string, Infinity, -Infinity or NaN,
+// encoding/json rejects such numbers
+func (r Response) MarshalJSON() ([]byte, error) {
+	type response Response
+	special := FormatSpecial(r.Result)
+	if special == "" {
+		return json.Marshal(response(r))
+	}
+
+	return json.Marshal(struct {
+		Result string `json:"result"`
+		response
+	}{special, response(r)})
+}
+
+// number is float64 written as a string, Infinity, -Infinity or NaN, if it is not a finite number
+type number float64
+
+func (n number) MarshalJSON() ([]byte, error) {
+	if special := FormatSpecial(float64(n)); special != "" {
+		return json.Marshal(special)
+	}
+
+	return json.Marshal(float64(n))
+}
+
+func numbers(values []float64) []number {
+	if values == nil {
+		return nil
+	}
+
+	result := make([]number, len(values))
+	for k, value := range values {
+		result[k] = number(value)
+	}
+
+	return result
+}
+
+// MarshalJSON writes operands, result and stack which are not finite numbers as strings
+func (s Step) MarshalJSON() ([]byte, error) {
+	type step Step
+	return json.Marshal(struct {
+		step
+		Operands []number `json:"operands,omitempty"`
+		Result   number   `json:"result"`
+		Stack    []number `json:"stack"`
+	}{step(s), numbers(s.Operands), number(s.Result), numbers(s.Stack)})
+}
+
+// MarshalJSON writes result which is not a finite number as a string
+func (e Explanation) MarshalJSON() ([]byte, error) {
+	type explanation Explanation
+	return json.Marshal(struct {
+		explanation
+		Result number `json:"result"`
+	}{explanation(e), number(e.Result)})
+}
+
+// MarshalJSON writes results which are not finite numbers as strings
+func (r EvaluateResponse) MarshalJSON() ([]byte, error) {
+	type response EvaluateResponse
+	result := struct {
+		response
+		Result  *number  `json:"result,omitempty"`
+		Results []number `json:"results,omitempty"`
+	}{response: response(r), Results: numbers(r.Results)}
+	if r.Result != nil {
+		value := number(*r.Result)
+		result.Result = &value
+	}
+
+	return json.Marshal(result)
+}
+
+// MarshalJSON writes value which is not a finite number as a string
+func (d Derivative) MarshalJSON() ([]byte, error) {
+	type derivative Derivative
+	result := struct {
+		derivative
+		Value *number `json:"value,omitempty"`
+	}{derivative: derivative(d)}
+	if d.Value != nil {
+		value := number(*d.Value)
+		result.Value = &value
+	}
+
+	return json.Marshal(result)
+}
+
+// MarshalJSON writes bounds which are not finite numbers as strings
+func (i Interval) MarshalJSON() ([]byte, error) {
+	type interval Interval
+	return json.Marshal(struct {
+		Lower number `json:"lower"`
+		Upper number `json:"upper"`
+		interval
+	}{number(i.Lower), number(i.Upper), interval(i)})
+}
+
+// MarshalJSON writes value which is not a finite number as a string
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	type quantity Quantity
+	return json.Marshal(struct {
+		Value number `json:"value"`
+		quantity
+	}{number(q.Value), quantity(q)})
+}
@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	httptransport "github.com/go-kit/kit/transport/http"
 
@@ -17,9 +16,18 @@ import (
 
 func decodeFormParamRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 	operation := r.FormValue("operation")
+	notation := r.FormValue("notation")
+
+	// operation is shown in MathML on the page unless other formats are requested
+	render := r.Form["render"]
+	if len(render) == 0 {
+		render = []string{"mathml"}
+	}
 
 	return Request{
 		Operation: operation,
+		Notation:  notation,
+		Render:    render,
 	}, nil
 }
 
@@ -40,11 +48,65 @@ func decodeJSONRequest(_ context.Context, r *http.Request) (interface{}, error)
 	return request, nil
 }
 
+func decodeJSONEvaluateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if r.Body == nil {
+		return nil, errors.NewInputError("Body cannot be empty")
+	}
+
+	var request EvaluateRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return nil, errors.NewInputErrorWrap(err, "Failed to decode JSON")
+	}
+
+	return request, nil
+}
+
+func decodeJSONProgramRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if r.Body == nil {
+		return nil, errors.NewInputError("Body cannot be empty")
+	}
+
+	var request ProgramRequest
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		return nil, errors.NewInputErrorWrap(err, "Failed to decode JSON")
+	}
+
+	return request, nil
+}
+
 func encodePlainResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	resp := response.(Response)
 
 	w.Header().Add("Content-type", "text/plain")
 	result := strconv.FormatFloat(resp.Result, 'f', -1, 64)
+	if special := FormatSpecial(resp.Result); special != "" {
+		result = special
+	}
+	if resp.Exact != "" {
+		result = resp.Exact
+	}
+	if resp.Decimal != "" {
+		result = resp.Decimal
+	}
+	if resp.Fraction != "" {
+		result = resp.Fraction
+	}
+	if resp.Complex != nil {
+		result = resp.Complex.Formatted
+	}
+	if resp.Interval != nil {
+		result = resp.Interval.Formatted
+	}
+	if resp.Quantity != nil {
+		result = resp.Quantity.Formatted
+	}
+	if resp.Time != nil {
+		result = resp.Time.Formatted
+	}
 
 	_, err := fmt.Fprint(w, result)
 	if err != nil {
@@ -55,7 +117,7 @@ func encodePlainResponse(_ context.Context, w http.ResponseWriter, response inte
 
 func encodeJSONResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	r := response.(Response)
-	jsonResp := Response{Result: r.Result}
+	jsonResp := Response{Result: r.Result, Exact: r.Exact, Decimal: r.Decimal, Fraction: r.Fraction, Mixed: r.Mixed, Approximate: r.Approximate, Complex: r.Complex, Interval: r.Interval, Significance: r.Significance, Quantity: r.Quantity, Time: r.Time, Rendered: r.Rendered, RenderErrors: r.RenderErrors, Warnings: r.Warnings, LintError: r.LintError}
 
 	w.Header().Add("Content-Type", "application/json; charset=utf-8")
 	err := json.NewEncoder(w).Encode(jsonResp)
@@ -65,11 +127,20 @@ func encodeJSONResponse(_ context.Context, w http.ResponseWriter, response inter
 	return nil
 }
 
+func encodeJSON(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Add("Content-Type", "application/json; charset=utf-8")
+	err := json.NewEncoder(w).Encode(response)
+	if err != nil {
+		return errors.NewEncodingErrorWrap(err, "Failed to encode response")
+	}
+	return nil
+}
+
 func encodeHTMLResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	tmpl := `<form method="post">
         <input name="operation" required> <input type="submit" value="Calculate">
         </form>
-        {{ if .Result }}<h1>{{ .Operation }} = {{ .Result }}</h1>{{ end }}
+        {{ if or .Result .Complex .Interval .Quantity .Time }}<h1>{{ if .MathML }}{{ .MathML }}{{ else }}{{ .Operation }}{{ end }} = {{ if .Exact }}{{ .Exact }}{{ else if .Complex }}{{ .Complex.Formatted }}{{ else if .Interval }}{{ .Interval.Formatted }}{{ else if .Quantity }}{{ .Quantity.Formatted }}{{ else if .Time }}{{ .Time.Formatted }}{{ else if .Fraction }}{{ .Fraction }}{{ else if .Decimal }}{{ .Decimal }}{{ else }}{{ .Result }}{{ end }}</h1>{{ end }}
         {{ if .Error }}<h1>{{ .Error }}</h1>{{ end }}`
 
 	resp := response.(Response)
@@ -80,25 +151,96 @@ func encodeHTMLResponse(_ context.Context, w http.ResponseWriter, response inter
 		return errors.NewEncodingErrorWrap(err, "Failed to parse response template")
 	}
 
-	t.Execute(w, resp)
+	// MathML is rendered from parsed operation, not from the raw input, so it is safe to embed,
+	// results which are not finite numbers are written like in other encodings
+	var result interface{} = resp.Result
+	if special := FormatSpecial(resp.Result); special != "" {
+		result = special
+	}
+
+	view := struct {
+		Response
+		Result interface{}
+		MathML template.HTML
+	}{resp, result, template.HTML(resp.Rendered["mathml"])}
+
+	t.Execute(w, view)
 	return nil
 }
 
-// NewHTTPHandler creates greeter handlers
-func NewHTTPHandler(endpoint endpoint.Endpoint, logger log.Logger) http.Handler {
+// NewHTTPHandler creates calculator handlers
+func NewHTTPHandler(endpoints Endpoints, logger log.Logger) http.Handler {
 	m := http.NewServeMux()
 
-	m.Handle("/api/calculate", httptransport.NewServer(
-		EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/calculate"))(endpoint),
-		decodeJSONRequest,
-		encodeJSONResponse,
-	))
-
-	m.Handle("/calculator", httptransport.NewServer(
-		EndpointLoggingMiddleware(log.With(logger, "endpoint", "/calculator"))(endpoint),
-		decodeFormParamRequest,
-		encodeHTMLResponse,
-	))
+	if endpoints.Calculate != nil {
+		m.Handle("/api/calculate", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/calculate"))(endpoints.Calculate),
+			decodeJSONRequest,
+			encodeJSONResponse,
+		))
+
+		m.Handle("/calculator", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/calculator"))(endpoints.Calculate),
+			decodeFormParamRequest,
+			encodeHTMLResponse,
+		))
+	}
+
+	if endpoints.Format != nil {
+		m.Handle("/api/format", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/format"))(endpoints.Format),
+			decodeJSONRequest,
+			encodeJSON,
+		))
+	}
+
+	if endpoints.Explain != nil {
+		m.Handle("/api/explain", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/explain"))(endpoints.Explain),
+			decodeJSONRequest,
+			encodeJSON,
+		))
+	}
+
+	if endpoints.Simplify != nil {
+		m.Handle("/api/simplify", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/simplify"))(endpoints.Simplify),
+			decodeJSONRequest,
+			encodeJSON,
+		))
+	}
+
+	if endpoints.Derive != nil {
+		m.Handle("/api/derive", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/derive"))(endpoints.Derive),
+			decodeJSONRequest,
+			encodeJSON,
+		))
+	}
+
+	if endpoints.Equivalent != nil {
+		m.Handle("/api/equivalent", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/equivalent"))(endpoints.Equivalent),
+			decodeJSONRequest,
+			encodeJSON,
+		))
+	}
+
+	if endpoints.Evaluate != nil {
+		m.Handle("/api/evaluate", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/evaluate"))(endpoints.Evaluate),
+			decodeJSONEvaluateRequest,
+			encodeJSON,
+		))
+	}
+
+	if endpoints.BC != nil {
+		m.Handle("/api/bc", httptransport.NewServer(
+			EndpointLoggingMiddleware(log.With(logger, "endpoint", "/api/bc"))(endpoints.BC),
+			decodeJSONProgramRequest,
+			encodeJSON,
+		))
+	}
 
 	return m
 }
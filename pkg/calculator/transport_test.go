@@ -3,6 +3,7 @@ package calculator
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -27,6 +28,30 @@ func TestApi(t *testing.T) {
 			return nil, errors.NewCalculationError(errors.CalculationError)
 		case errors.EncodingError:
 			return nil, errors.NewEncodingError(errors.EncodingError)
+		case "1/3":
+			return Response{Operation: req.Operation, Result: 1.0 / 3, Exact: "1/3"}, nil
+		case "1/3+1/6":
+			return Response{Operation: req.Operation, Result: 0.5, Decimal: "0.5", Fraction: "1/2", Mixed: "1/2"}, nil
+		case "10/4":
+			if req.Mode == DecimalMode && req.Scale != nil && *req.Scale == 0 {
+				return Response{Operation: req.Operation, Result: 2, Decimal: "2"}, nil
+			}
+		case "sqrt(-4)":
+			return Response{Operation: req.Operation, Complex: &Complex{Real: 0, Imag: 2, Formatted: "2i"}}, nil
+		case "[1.5, 2.5] * 2":
+			return Response{Operation: req.Operation, Result: 4, Interval: &Interval{Lower: 3, Upper: 5, Formatted: "[3, 5]"}}, nil
+		case "5 km + 300 m":
+			return Response{Operation: req.Operation, Result: 5.3, Quantity: &Quantity{Value: 5.3, Unit: "km", Dimension: "length", Formatted: "5.3 km"}}, nil
+		case "2026-10-17 + 45 days":
+			if req.Mode == TimeMode && req.Zone == "UTC" {
+				return Response{Operation: req.Operation, Result: 1796083200, Time: &Time{Kind: "date", Formatted: "2026-12-01", ISO: "2026-12-01", Unix: 1796083200, Weekday: "Tuesday"}}, nil
+			}
+		case "2.5*3.42":
+			return Response{Operation: req.Operation, Result: 8.5, Decimal: "8.5", Significance: &Significance{Figures: 2, DecimalPlaces: 1}}, nil
+		case "0.1+0.2":
+			if req.Mode == BigFloatMode && req.Digits == 20 {
+				return Response{Operation: req.Operation, Result: 0.3, Decimal: "0.3"}, nil
+			}
 		}
 
 		return Response{
@@ -35,12 +60,21 @@ func TestApi(t *testing.T) {
 		}, nil
 	}
 
-	handler := NewHTTPHandler(endpoint, log.NewNopLogger())
+	handler := NewHTTPHandler(Endpoints{Calculate: endpoint}, log.NewNopLogger())
 
 	type respBodyStruct struct {
-		Result           float64 `json:"result"`
-		Error            string  `json:"error"`
-		ErrorDescription string  `json:"error_description"`
+		Result           float64       `json:"result"`
+		Exact            string        `json:"exact"`
+		Decimal          string        `json:"decimal"`
+		Fraction         string        `json:"fraction"`
+		Mixed            string        `json:"mixed"`
+		Complex          *Complex      `json:"complex"`
+		Interval         *Interval     `json:"interval"`
+		Significance     *Significance `json:"significance"`
+		Quantity         *Quantity     `json:"quantity"`
+		Time             *Time         `json:"time"`
+		Error            string        `json:"error"`
+		ErrorDescription string        `json:"error_description"`
 	}
 
 	tests := []struct {
@@ -55,6 +89,60 @@ func TestApi(t *testing.T) {
 			http.StatusOK,
 			respBodyStruct{Result: 0},
 		},
+		{
+			"API success with exact result",
+			"{\"operation\": \"1/3\"}",
+			http.StatusOK,
+			respBodyStruct{Result: 1.0 / 3, Exact: "1/3"},
+		},
+		{
+			"API success in mode",
+			"{\"operation\": \"0.1+0.2\", \"mode\": \"bigfloat\", \"digits\": 20}",
+			http.StatusOK,
+			respBodyStruct{Result: 0.3, Decimal: "0.3"},
+		},
+		{
+			"API success with zero scale",
+			"{\"operation\": \"10/4\", \"mode\": \"decimal\", \"scale\": 0, \"rounding\": \"half-even\"}",
+			http.StatusOK,
+			respBodyStruct{Result: 2, Decimal: "2"},
+		},
+		{
+			"API success with complex result",
+			"{\"operation\": \"sqrt(-4)\", \"mode\": \"complex\"}",
+			http.StatusOK,
+			respBodyStruct{Complex: &Complex{Real: 0, Imag: 2, Formatted: "2i"}},
+		},
+		{
+			"API success with interval result",
+			"{\"operation\": \"[1.5, 2.5] * 2\", \"mode\": \"interval\"}",
+			http.StatusOK,
+			respBodyStruct{Result: 4, Interval: &Interval{Lower: 3, Upper: 5, Formatted: "[3, 5]"}},
+		},
+		{
+			"API success with significant figures",
+			"{\"operation\": \"2.5*3.42\", \"mode\": \"sigfig\"}",
+			http.StatusOK,
+			respBodyStruct{Result: 8.5, Decimal: "8.5", Significance: &Significance{Figures: 2, DecimalPlaces: 1}},
+		},
+		{
+			"API success with quantity",
+			"{\"operation\": \"5 km + 300 m\", \"mode\": \"units\"}",
+			http.StatusOK,
+			respBodyStruct{Result: 5.3, Quantity: &Quantity{Value: 5.3, Unit: "km", Dimension: "length", Formatted: "5.3 km"}},
+		},
+		{
+			"API success with date",
+			"{\"operation\": \"2026-10-17 + 45 days\", \"mode\": \"time\", \"zone\": \"UTC\"}",
+			http.StatusOK,
+			respBodyStruct{Result: 1796083200, Time: &Time{Kind: "date", Formatted: "2026-12-01", ISO: "2026-12-01", Unix: 1796083200, Weekday: "Tuesday"}},
+		},
+		{
+			"API success with fraction",
+			"{\"operation\": \"1/3+1/6\", \"mode\": \"rational\"}",
+			http.StatusOK,
+			respBodyStruct{Result: 0.5, Decimal: "0.5", Fraction: "1/2", Mixed: "1/2"},
+		},
 		{
 			"API InputError",
 			"{\"operation\": \"InputError\"}",
@@ -111,3 +199,151 @@ func TestApi(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatApi(t *testing.T) {
+	endpoint := func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+
+		if req.Operation == errors.ParsingError {
+			return nil, errors.NewParsingError(errors.ParsingError)
+		}
+
+		return FormatResponse{Formatted: req.Operation}, nil
+	}
+
+	handler := NewHTTPHandler(Endpoints{Format: endpoint}, log.NewNopLogger())
+
+	type respBodyStruct struct {
+		Formatted        string `json:"formatted"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		wantStatusCode int
+		wantBody       respBodyStruct
+	}{
+		{
+			"API success",
+			"{\"operation\": \"1 + 2\"}",
+			http.StatusOK,
+			respBodyStruct{Formatted: "1 + 2"},
+		},
+		{
+			"API ParsingError",
+			"{\"operation\": \"ParsingError\"}",
+			http.StatusBadRequest,
+			respBodyStruct{Error: errors.ParsingError, ErrorDescription: errors.ParsingError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+
+			req, err := http.NewRequest(http.MethodPost, "/api/format", strings.NewReader(tt.reqBody))
+
+			if err != nil {
+				t.Fatalf("expected error to be nil, got '%v'", err)
+			}
+
+			handler.ServeHTTP(rw, req)
+
+			if expect, got := tt.wantStatusCode, rw.Code; expect != got {
+				t.Fatalf("expected '%v', got '%v'", expect, got)
+			}
+
+			var bodyDecoded respBodyStruct
+
+			err = json.NewDecoder(rw.Body).Decode(&bodyDecoded)
+			if err != nil {
+				t.Fatalf("expected nil, got '%v'", err)
+			}
+
+			if !cmp.Equal(tt.wantBody, bodyDecoded) {
+				t.Errorf("expected '%v', got '%v'", tt.wantBody, bodyDecoded)
+			}
+		})
+	}
+}
+
+func TestEncodeSpecialValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoder  func(context.Context, http.ResponseWriter, interface{}) error
+		response Response
+		expected string
+	}{
+		{"JSON Infinity", encodeJSONResponse, Response{Result: math.Inf(1)}, `{"result":"Infinity"}`},
+		{"JSON negative Infinity", encodeJSONResponse, Response{Result: math.Inf(-1), Exact: "-inf"}, `{"result":"-Infinity","exact":"-inf"}`},
+		{"JSON NaN", encodeJSONResponse, Response{Result: math.NaN()}, `{"result":"NaN"}`},
+		{"JSON finite", encodeJSONResponse, Response{Result: 2.5}, `{"result":2.5}`},
+		{"Plain Infinity", encodePlainResponse, Response{Result: math.Inf(1)}, "Infinity"},
+		{"Plain NaN", encodePlainResponse, Response{Result: math.NaN()}, "NaN"},
+		{"Plain interval", encodePlainResponse, Response{Result: 2, Interval: &Interval{Lower: 1, Upper: 3, Formatted: "[1, 3]"}}, "[1, 3]"},
+		{"Plain quantity", encodePlainResponse, Response{Result: 5.3, Quantity: &Quantity{Value: 5.3, Unit: "km", Dimension: "length", Formatted: "5.3 km"}}, "5.3 km"},
+		{"Plain time", encodePlainResponse, Response{Result: 48000, Time: &Time{Kind: "duration", Formatted: "13h 20m", ISO: "PT13H20M", Seconds: 48000}}, "13h 20m"},
+		{"HTML interval", encodeHTMLResponse, Response{Operation: "2 ± 1", Result: 2, Interval: &Interval{Lower: 1, Upper: 3, Formatted: "[1, 3]"}}, "<h1>2 ± 1 = [1, 3]</h1>"},
+		{"HTML Infinity", encodeHTMLResponse, Response{Operation: "1/0", Result: math.Inf(1)}, "<h1>1/0 = Infinity</h1>"},
+		{"HTML NaN", encodeHTMLResponse, Response{Operation: "0/0", Result: math.NaN()}, "<h1>0/0 = NaN</h1>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+
+			err := tt.encoder(context.Background(), rw, tt.response)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got '%v'", err)
+			}
+
+			if body := rw.Body.String(); !strings.Contains(body, tt.expected) {
+				t.Errorf("expected body to contain '%v', got '%v'", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestApiSpecialValues(t *testing.T) {
+	inf := math.Inf(1)
+	respond := func(response interface{}) func(context.Context, interface{}) (interface{}, error) {
+		return func(context.Context, interface{}) (interface{}, error) { return response, nil }
+	}
+
+	handler := NewHTTPHandler(Endpoints{
+		Calculate: respond(Response{Result: inf, Quantity: &Quantity{Value: inf, Unit: "m", Dimension: "length", Formatted: "Infinity m"}, Interval: &Interval{Lower: 1, Upper: inf, Formatted: "[1, Infinity]"}}),
+		Explain:   respond(Explanation{Steps: []Step{{Item: "/", Action: "apply", Operands: []float64{1, 0}, Result: inf, Stack: []float64{inf}}}, Result: inf}),
+		Evaluate:  respond(EvaluateResponse{Variables: []string{"x"}, Result: &inf, Results: []float64{math.NaN(), 1}}),
+		Derive:    respond(Derivative{Derivative: "1 / x", Tree: "(/ 1 x)", Value: &inf}),
+	}, log.NewNopLogger())
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"Calculate", "/api/calculate", `{"result":"Infinity","interval":{"lower":1,"upper":"Infinity","formatted":"[1, Infinity]"},"quantity":{"value":"Infinity","unit":"m","dimension":"length","formatted":"Infinity m"}}`},
+		{"Explain", "/api/explain", `{"tokens":null,"postfix":null,"tree":"","dot":"","steps":[{"item":"/","action":"apply","operands":[1,0],"result":"Infinity","stack":["Infinity"]}],"result":"Infinity"}`},
+		{"Evaluate", "/api/evaluate", `{"variables":["x"],"result":"Infinity","results":["NaN",1]}`},
+		{"Derive", "/api/derive", `{"derivative":"1 / x","tree":"(/ 1 x)","value":"Infinity"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"operation": "1/x", "expression": "1/x", "variable": "x"}`))
+
+			handler.ServeHTTP(rw, req)
+
+			if rw.Code != http.StatusOK {
+				t.Fatalf("expected status code %v, got %v: %v", http.StatusOK, rw.Code, rw.Body.String())
+			}
+
+			if body := strings.TrimSpace(rw.Body.String()); body != tt.expected {
+				t.Errorf("expected body to be '%v', got '%v'", tt.expected, body)
+			}
+		})
+	}
+}
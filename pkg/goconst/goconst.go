@@ -0,0 +1,93 @@
+package goconst
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math/big"
+
+	"github.com/mateuszkrasucki/calculator/pkg/bigmath"
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// Notation is the name of Go constant expressions notation
+const Notation = "go"
+
+type operation struct {
+	value constant.Value
+}
+
+// Parse parses input as Go constant expression and evaluates it the way Go compiler does, with arbitrary precision
+// untyped constants, conversions to typed constants and the same error messages
+func Parse(_ context.Context, input string) (calculator.OperationInterface, error) {
+	fset := token.NewFileSet()
+
+	expr, err := parser.ParseExprFrom(fset, "", input, 0)
+	if err != nil {
+		return nil, errors.NewParsingErrorWrap(err, "could not parse Go expression")
+	}
+
+	// expression is type-checked as the value of constant declaration: const _ = expr
+	file := &ast.File{
+		Name: ast.NewIdent("p"),
+		Decls: []ast.Decl{&ast.GenDecl{
+			Tok:   token.CONST,
+			Specs: []ast.Spec{&ast.ValueSpec{Names: []*ast.Ident{ast.NewIdent("_")}, Values: []ast.Expr{expr}}},
+		}},
+	}
+	info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}}
+
+	if _, err := (&types.Config{}).Check("p", fset, []*ast.File{file}, info); err != nil {
+		if typeErr, ok := err.(types.Error); ok {
+			return nil, errors.NewCalculationError(typeErr.Msg)
+		}
+		return nil, errors.NewCalculationErrorWrap(err, "could not evaluate Go expression")
+	}
+
+	value := info.Types[expr].Value
+	if value == nil || value.Kind() == constant.Unknown {
+		return nil, errors.NewCalculationError(fmt.Sprintf("%s is not constant", input))
+	}
+
+	return &operation{value: value}, nil
+}
+
+// Calculate returns result rounded to float64, booleans are returned as 1 and 0
+func (o *operation) Calculate(_ context.Context) (float64, error) {
+	switch o.value.Kind() {
+	case constant.Bool:
+		if constant.BoolVal(o.value) {
+			return 1, nil
+		}
+		return 0, nil
+	case constant.Int, constant.Float:
+		result, _ := constant.Float64Val(o.value)
+		return result, nil
+	default:
+		return 0, errors.NewCalculationError(fmt.Sprintf("result is not a number: %s", o.value.ExactString()))
+	}
+}
+
+// CalculateExact returns exact result, floating point results are written as decimals if they terminate, as fractions otherwise
+func (o *operation) CalculateExact(_ context.Context) (string, error) {
+	if o.value.Kind() != constant.Float {
+		return o.value.ExactString(), nil
+	}
+
+	r, ok := constant.Val(o.value).(*big.Rat)
+	if !ok {
+		return o.value.ExactString(), nil
+	}
+
+	digits, ok := bigmath.DecimalDigits(r.Denom())
+	if !ok {
+		return r.RatString(), nil
+	}
+
+	return r.FloatString(digits), nil
+}
@@ -0,0 +1,61 @@
+package goconst
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedResult float64
+		expectedExact  string
+		expectedError  error
+	}{
+		{"Integer division of integer operands", "7 / 2", 3, "3", nil},
+		{"Integer division truncates towards zero", "-7 / 2", -3, "-3", nil},
+		{"Division of untyped floats", "7 / 2.0", 3.5, "3.5", nil},
+		{"Exact decimal", "0.1 + 0.2", 0.3, "0.3", nil},
+		{"Non terminating fraction", "1 / 3.0", 1.0 / 3, "1/3", nil},
+		{"Remainder", "7 % 3", 1, "1", nil},
+		{"Shifts", "1 << 70 >> 68", 4, "4", nil},
+		{"Arbitrary precision integers", "1 << 100", 1 << 100, "1267650600228229401496703205376", nil},
+		{"Bitwise operators", "0xF0 | 0x0F &^ 0x03 ^ 1", 253, "253", nil},
+		{"Typed constant rounded to its type", "float32(0.1)", 0.10000000149011612, "0.100000001490116119384765625", nil},
+		{"Comparison", "2 > 1 && 1 == 1.0", 1, "true", nil},
+		{"Division by zero", "1 / 0", 0, "", errors.NewCalculationError("invalid operation: division by zero")},
+		{"Shift overflow", "1 << 600", 0, "", errors.NewCalculationError("constant shift overflow")},
+		{"Typed overflow", "int8(100) * 2", 0, "", errors.NewCalculationError("int8(100) * 2 (constant 200 of type int8) overflows int8")},
+		{"Remainder of floats", "1.5 % 2", 0, "", errors.NewCalculationError("invalid operation: operator % not defined on 1.5 (untyped float constant)")},
+		{"Undefined identifier", "x + 1", 0, "", errors.NewCalculationError("undefined: x")},
+		{"String result", `"a" + "b"`, 0, "", errors.NewCalculationError(`result is not a number: "ab"`)},
+		{"Syntax error", "1 +", 0, "", errors.NewParsingError("could not parse Go expression")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, exact, err := calculator.New(Parse).(calculator.ExactCalculator).CalculateExact(context.Background(), tt.input)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if result != tt.expectedResult {
+				t.Errorf("expected result to be %v, got %v", tt.expectedResult, result)
+			}
+
+			if exact != tt.expectedExact {
+				t.Errorf("expected exact result to be %s, got %s", tt.expectedExact, exact)
+			}
+		})
+	}
+}
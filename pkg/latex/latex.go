@@ -0,0 +1,533 @@
+package latex
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+	rpn "github.com/mateuszkrasucki/calculator/pkg/reversepolish"
+)
+
+type tokenType int
+
+const (
+	tokenNumber  tokenType = iota
+	tokenCommand           // \frac, \sqrt, \pi etc., value without leading backslash
+	tokenSymbol            // single character: + - * / ^ { } ( ) [ ] | and letters
+)
+
+type token struct {
+	typ   tokenType
+	value string
+	pos   int
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// commands mapped to functions known to postfix calculator
+var functionCommands = map[string]string{
+	"sin":    "sin",
+	"cos":    "cos",
+	"tan":    "tan",
+	"arcsin": "asin",
+	"arccos": "acos",
+	"arctan": "atan",
+	"sinh":   "sinh",
+	"cosh":   "cosh",
+	"tanh":   "tanh",
+	"exp":    "exp",
+	"ln":     "ln",
+	"log":    "log",
+}
+
+// commands mapped to binary operators
+var operatorCommands = map[string]lexer.Item{
+	"cdot":  lexer.NewItem(lexer.Multiplication, "*"),
+	"times": lexer.NewItem(lexer.Multiplication, "*"),
+	"div":   lexer.NewItem(lexer.Division, "/"),
+}
+
+// commands changing only the way formula is typeset, ignored during parsing
+var ignoredCommands = map[string]bool{
+	",":            true,
+	";":            true,
+	":":            true,
+	"!":            true,
+	" ":            true,
+	"quad":         true,
+	"qquad":        true,
+	"displaystyle": true,
+	"textstyle":    true,
+}
+
+// Parse provides parsing of mathematical operations written in LaTeX for postfix calculator
+func Parse(ctx context.Context, input string) (calculator.OperationInterface, error) {
+	return rpn.ParseItems(ctx, Lex(input))
+}
+
+// Lex returns lexer translating LaTeX input into infix items, errors are returned as lexer.Error items
+func Lex(input string) lexer.Lexer {
+	items, err := translate(input)
+	if err != nil {
+		return lexer.NewItemsLexer([]lexer.Item{lexer.NewItem(lexer.Error, err.Error())})
+	}
+
+	return lexer.NewItemsLexer(items)
+}
+
+func translate(input string) ([]lexer.Item, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// empty input is calculated as 0, the same as empty infix input
+	if len(tokens) == 0 {
+		return []lexer.Item{lexer.NewItem(lexer.Number, "0")}, nil
+	}
+
+	p := &parser{tokens: tokens}
+
+	items, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if t, ok := p.peek(); ok {
+		return nil, unexpectedToken(t)
+	}
+
+	return items, nil
+}
+
+func tokenize(input string) ([]token, error) {
+	tokens := []token{}
+
+	for pos := 0; pos < len(input); {
+		r, w := utf8.DecodeRuneInString(input[pos:])
+
+		switch {
+		case unicode.IsSpace(r):
+			pos += w
+		case r < utf8.RuneSelf && isDigit(byte(r)) || r == '.':
+			end := pos
+			for end < len(input) && (isDigit(input[end]) || input[end] == '.') {
+				end++
+			}
+			if strings.Count(input[pos:end], ".") > 1 || input[pos] == '.' {
+				return nil, fmt.Errorf("invalid number at: %d; could not lex: %s", pos, input[pos:end])
+			}
+			tokens = append(tokens, token{tokenNumber, input[pos:end], pos})
+			pos = end
+		case r == '\\':
+			end := pos + 1
+			for end < len(input) && isLetter(input[end]) {
+				end++
+			}
+			if end == pos+1 && end < len(input) {
+				// single non-letter command, i.e. \, or \{
+				_, w := utf8.DecodeRuneInString(input[end:])
+				end += w
+			}
+			if end == pos+1 {
+				return nil, fmt.Errorf("invalid command at: %d; could not lex: \\", pos)
+			}
+			if !ignoredCommands[input[pos+1:end]] {
+				tokens = append(tokens, token{tokenCommand, input[pos+1 : end], pos})
+			}
+			pos = end
+		case strings.ContainsRune("+-*/^{}()[]|", r) || unicode.IsLetter(r):
+			tokens = append(tokens, token{tokenSymbol, string(r), pos})
+			pos += w
+		default:
+			return nil, fmt.Errorf("invalid rune at: %d; could not lex: %s", pos, string(r))
+		}
+	}
+
+	return tokens, nil
+}
+
+// parseExpression parses terms separated by + and -
+func (p *parser) parseExpression() ([]lexer.Item, error) {
+	items, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.peek()
+		if !ok || t.typ != tokenSymbol || (t.value != "+" && t.value != "-") {
+			return items, nil
+		}
+		p.pos++
+
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, signItem(t.value))
+		items = append(items, term...)
+	}
+}
+
+// parseTerm parses factors separated by explicit or implicit multiplication and division
+func (p *parser) parseTerm() ([]lexer.Item, error) {
+	items, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.peek()
+		if !ok {
+			return items, nil
+		}
+
+		var operator lexer.Item
+		switch {
+		case t.typ == tokenSymbol && t.value == "*":
+			operator = lexer.NewItem(lexer.Multiplication, "*")
+			p.pos++
+		case t.typ == tokenSymbol && t.value == "/":
+			operator = lexer.NewItem(lexer.Division, "/")
+			p.pos++
+		case t.typ == tokenCommand && operatorCommands[t.value] != nil:
+			operator = operatorCommands[t.value]
+			p.pos++
+		case p.startsAtom(t):
+			operator = lexer.NewItem(lexer.Multiplication, "*")
+		default:
+			return items, nil
+		}
+
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, operator)
+		items = append(items, factor...)
+	}
+}
+
+// parseFactor parses signed powers
+func (p *parser) parseFactor() ([]lexer.Item, error) {
+	t, ok := p.peek()
+	if ok && t.typ == tokenSymbol && (t.value == "-" || t.value == "+") {
+		p.pos++
+
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]lexer.Item{signItem(t.value)}, factor...), nil
+	}
+
+	return p.parsePower()
+}
+
+// parsePower parses atom optionally raised to the power given in superscript
+func (p *parser) parsePower() ([]lexer.Item, error) {
+	base, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parseSuperscript(base)
+}
+
+func (p *parser) parseSuperscript(base []lexer.Item) ([]lexer.Item, error) {
+	t, ok := p.peek()
+	if !ok || t.typ != tokenSymbol || t.value != "^" {
+		return base, nil
+	}
+	p.pos++
+
+	exponent, err := p.parseArgument()
+	if err != nil {
+		return nil, err
+	}
+
+	items := append(base, lexer.NewItem(lexer.Exponent, "^"))
+	return append(items, lexer.Parenthesized(exponent)...), nil
+}
+
+// parseAtom parses numbers, constants, groups and commands, returned items are always self-contained operand
+func (p *parser) parseAtom() ([]lexer.Item, error) {
+	t, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+
+	switch {
+	case t.typ == tokenNumber:
+		return []lexer.Item{lexer.NewItem(lexer.Number, t.value)}, nil
+	case t.typ == tokenSymbol && t.value == "{":
+		return p.parseGroup("}")
+	case t.typ == tokenSymbol && t.value == "(":
+		return p.parseGroup(")")
+	case t.typ == tokenSymbol && t.value == "[":
+		return p.parseGroup("]")
+	case t.typ == tokenSymbol && t.value == "|":
+		group, err := p.parseGroup("|")
+		if err != nil {
+			return nil, err
+		}
+		return lexer.FunctionCall("abs", group), nil
+	case t.typ == tokenSymbol && t.value == "e":
+		return []lexer.Item{lexer.NewItem(lexer.Constant, "e")}, nil
+	case t.typ == tokenCommand:
+		return p.parseCommand(t)
+	default:
+		return nil, unexpectedToken(t)
+	}
+}
+
+func (p *parser) parseCommand(t token) ([]lexer.Item, error) {
+	switch t.value {
+	case "pi":
+		return []lexer.Item{lexer.NewItem(lexer.Constant, "pi")}, nil
+	case "left":
+		return p.parseLeftRight()
+	case "frac", "dfrac", "tfrac":
+		numerator, err := p.parseArgument()
+		if err != nil {
+			return nil, err
+		}
+
+		denominator, err := p.parseArgument()
+		if err != nil {
+			return nil, err
+		}
+
+		items := append(lexer.Parenthesized(numerator), lexer.NewItem(lexer.Division, "/"))
+		return lexer.Parenthesized(append(items, lexer.Parenthesized(denominator)...)), nil
+	case "sqrt":
+		return p.parseSqrt()
+	}
+
+	function, ok := functionCommands[t.value]
+	if !ok {
+		return nil, fmt.Errorf("unsupported command at: %d; could not parse: \\%s", t.pos, t.value)
+	}
+
+	// \sin^2 x is typeset convention for (\sin x)^2
+	var exponent []lexer.Item
+	if next, ok := p.peek(); ok && next.typ == tokenSymbol && next.value == "^" {
+		p.pos++
+
+		var err error
+		exponent, err = p.parseArgument()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	argument, err := p.parseFunctionArgument()
+	if err != nil {
+		return nil, err
+	}
+
+	items := lexer.FunctionCall(function, argument)
+	if exponent != nil {
+		items = append(lexer.Parenthesized(items), lexer.NewItem(lexer.Exponent, "^"))
+		items = append(items, lexer.Parenthesized(exponent)...)
+	}
+
+	return items, nil
+}
+
+func (p *parser) parseSqrt() ([]lexer.Item, error) {
+	var degree []lexer.Item
+	if t, ok := p.peek(); ok && t.typ == tokenSymbol && t.value == "[" {
+		p.pos++
+
+		var err error
+		degree, err = p.parseGroup("]")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	radicand, err := p.parseArgument()
+	if err != nil {
+		return nil, err
+	}
+
+	if degree == nil {
+		return lexer.FunctionCall("sqrt", radicand), nil
+	}
+
+	// n-th root is calculated as radicand^(1/n)
+	exponent := append([]lexer.Item{lexer.NewItem(lexer.Number, "1"), lexer.NewItem(lexer.Division, "/")}, degree...)
+	items := append(lexer.Parenthesized(radicand), lexer.NewItem(lexer.Exponent, "^"))
+	return lexer.Parenthesized(append(items, lexer.Parenthesized(exponent)...)), nil
+}
+
+// parseLeftRight parses \left( ... \right) pairs, \left| ... \right| is an absolute value
+func (p *parser) parseLeftRight() ([]lexer.Item, error) {
+	opening, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+
+	closing := map[string]string{"(": ")", "[": "]", "|": "|"}[opening.value]
+	if opening.typ != tokenSymbol || closing == "" {
+		return nil, unexpectedToken(opening)
+	}
+
+	items, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(tokenCommand, "right"); err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(tokenSymbol, closing); err != nil {
+		return nil, err
+	}
+
+	if opening.value == "|" {
+		return lexer.FunctionCall("abs", items), nil
+	}
+
+	return lexer.Parenthesized(items), nil
+}
+
+// parseGroup parses expression enclosed in brackets, opening bracket has to be already consumed
+func (p *parser) parseGroup(closing string) ([]lexer.Item, error) {
+	items, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(tokenSymbol, closing); err != nil {
+		return nil, err
+	}
+
+	return lexer.Parenthesized(items), nil
+}
+
+// parseArgument parses command argument or superscript: group in braces or a single character
+func (p *parser) parseArgument() ([]lexer.Item, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+
+	if t.typ == tokenSymbol && t.value == "{" {
+		p.pos++
+		return p.parseGroup("}")
+	}
+
+	// only the first digit of a number is an argument, i.e. \frac12 is \frac{1}{2}
+	if t.typ == tokenNumber && len(t.value) > 1 {
+		p.tokens[p.pos] = token{tokenNumber, t.value[1:], t.pos + 1}
+		return []lexer.Item{lexer.NewItem(lexer.Number, t.value[:1])}, nil
+	}
+
+	return p.parseAtom()
+}
+
+// parseFunctionArgument parses argument of functions like \sin, which spans until next explicit operator, i.e. \sin 2x
+func (p *parser) parseFunctionArgument() ([]lexer.Item, error) {
+	t, ok := p.peek()
+	if ok && t.typ == tokenSymbol && t.value == "(" {
+		return p.parseAtom()
+	}
+
+	items, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+
+	for t, ok := p.peek(); ok && p.startsAtom(t) && t.value != "(" && functionCommands[t.value] == ""; t, ok = p.peek() {
+		power, err := p.parsePower()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, lexer.NewItem(lexer.Multiplication, "*"))
+		items = append(items, power...)
+	}
+
+	return items, nil
+}
+
+// startsAtom returns true if token begins operand, which means implicit multiplication when it follows another operand
+func (p *parser) startsAtom(t token) bool {
+	switch t.typ {
+	case tokenNumber:
+		return true
+	case tokenCommand:
+		_, isFunction := functionCommands[t.value]
+		return isFunction || t.value == "pi" || t.value == "left" || t.value == "frac" || t.value == "dfrac" || t.value == "tfrac" || t.value == "sqrt"
+	default:
+		return t.value == "{" || t.value == "(" || t.value == "[" || t.value == "e"
+	}
+}
+
+func (p *parser) expect(typ tokenType, value string) error {
+	t, ok := p.next()
+	if !ok {
+		return fmt.Errorf("unexpected end of input, expected: %s", value)
+	}
+
+	if t.typ != typ || t.value != value {
+		return unexpectedToken(t)
+	}
+
+	return nil
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, bool) {
+	t, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+
+	return t, ok
+}
+
+func unexpectedToken(t token) error {
+	if t.typ == tokenCommand {
+		return fmt.Errorf("unexpected token at: %d; could not parse: \\%s", t.pos, t.value)
+	}
+
+	return fmt.Errorf("unexpected token at: %d; could not parse: %s", t.pos, t.value)
+}
+
+func signItem(sign string) lexer.Item {
+	if sign == "-" {
+		return lexer.NewItem(lexer.Subtraction, "-")
+	}
+
+	return lexer.NewItem(lexer.Addition, "+")
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,156 @@
+package latex
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedResult float64
+		expectedError  error
+	}{
+		{
+			"Success example from docs",
+			"\\frac{3}{4} \\cdot \\sqrt{16} + 2^{10}",
+			1027,
+			nil,
+		},
+		{
+			"Success n-th root",
+			"\\sqrt[3]{27} \\times 2",
+			6,
+			nil,
+		},
+		{
+			"Success left right parantheses",
+			"\\left( 1 + 2 \\right) \\cdot 3",
+			9,
+			nil,
+		},
+		{
+			"Success braces grouping",
+			"{1 + 2}^{2}",
+			9,
+			nil,
+		},
+		{
+			"Success superscript takes single digit",
+			"2^34",
+			32,
+			nil,
+		},
+		{
+			"Success frac without braces",
+			"\\frac12",
+			0.5,
+			nil,
+		},
+		{
+			"Success implicit multiplication",
+			"2\\pi",
+			2 * math.Pi,
+			nil,
+		},
+		{
+			"Success function",
+			"\\sin \\frac{\\pi}{2} + \\cos(0)",
+			2,
+			nil,
+		},
+		{
+			"Success function argument until operator",
+			"\\sin 2\\pi \\cdot 3 + 1",
+			1,
+			nil,
+		},
+		{
+			"Success function power",
+			"\\sin^2 \\pi + \\cos^{2} \\pi",
+			1,
+			nil,
+		},
+		{
+			"Success negative numbers",
+			"-2^{2} \\div -4",
+			1,
+			nil,
+		},
+		{
+			"Success absolute value",
+			"\\left| 1 - 3 \\right| + |2 - 5|",
+			5,
+			nil,
+		},
+		{
+			"Success spacing commands ignored",
+			"1 \\, + \\; 2",
+			3,
+			nil,
+		},
+		{
+			"Success empty input",
+			" \\quad ",
+			0,
+			nil,
+		},
+		{
+			"Error unsupported command",
+			"\\int 2",
+			0,
+			errors.NewParsingError("unsupported command at: 0; could not parse: \\int"),
+		},
+		{
+			"Error unknown symbol",
+			"2 + x",
+			0,
+			errors.NewParsingError("unexpected token at: 4; could not parse: x"),
+		},
+		{
+			"Error unclosed brace",
+			"\\frac{1}{2",
+			0,
+			errors.NewParsingError("unexpected end of input, expected: }"),
+		},
+		{
+			"Error non-ASCII digit",
+			"٣ + 1",
+			0,
+			errors.NewParsingError("invalid rune at: 0; could not lex: ٣"),
+		},
+		{
+			"Error mismatched left right",
+			"\\left( 1 + 2 \\right]",
+			0,
+			errors.NewParsingError("unexpected token at: 19; could not parse: ]"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result float64
+			operation, err := Parse(context.Background(), tt.input)
+			if err == nil {
+				result, err = operation.Calculate(context.Background())
+			}
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && err != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if math.Abs(tt.expectedResult-result) > 1e-9 {
+				t.Errorf("expected result to be %v, got %v", tt.expectedResult, result)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package lexer
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -31,6 +33,12 @@ type lexer struct {
 	pos      int       // current position of scanning in th input
 	lastStep int       // length of last step
 	items    chan Item // channel of scanned items
+	time     bool      // dates, durations and time zones are lexed
+}
+
+type itemsLexer struct {
+	items []Item
+	pos   int
 }
 
 const eof = -1 // rune value used when reached end of string
@@ -46,14 +54,63 @@ const (
 	Multiplication
 	Division
 	Exponent
+	Negation
+	Function
+	Constant
+	Identifier
+	Variable
+	Separator
+	LeftSquareBracket
+	RightSquareBracket
+	PlusMinus
+	Interval
+	Conversion
+	Date
+	Duration
+	Zone
 	Error
 )
 
+var itemTypeNames = map[ItemType]string{
+	Empty:              "Empty",
+	Number:             "Number",
+	LeftParenthesis:    "LeftParenthesis",
+	RightParenthesis:   "RightParenthesis",
+	Addition:           "Addition",
+	Subtraction:        "Subtraction",
+	Multiplication:     "Multiplication",
+	Division:           "Division",
+	Exponent:           "Exponent",
+	Negation:           "Negation",
+	Function:           "Function",
+	Constant:           "Constant",
+	Identifier:         "Identifier",
+	Variable:           "Variable",
+	Separator:          "Separator",
+	LeftSquareBracket:  "LeftSquareBracket",
+	RightSquareBracket: "RightSquareBracket",
+	PlusMinus:          "PlusMinus",
+	Interval:           "Interval",
+	Conversion:         "Conversion",
+	Date:               "Date",
+	Duration:           "Duration",
+	Zone:               "Zone",
+	Error:              "Error",
+}
+
 type stateFn func(*lexer) stateFn
 
 // Lex returns lexer
 func Lex(input string) Lexer {
-	l, _ := lex(input)
+	l, _ := lex(input, false)
+
+	return l
+}
+
+// LexTime returns lexer of operations of time mode, which lexes also dates, durations and time zones,
+// e.g. 2026-10-17, 3h 20m and Europe/Warsaw
+func LexTime(input string) Lexer {
+	l, _ := lex(input, true)
 
 	return l
 }
@@ -68,10 +125,11 @@ func (l *lexer) NextItem() Item {
 	return NewEmptyItem()
 }
 
-func lex(input string) (*lexer, chan Item) {
+func lex(input string, time bool) (*lexer, chan Item) {
 	l := &lexer{
 		input: input,
 		items: make(chan Item),
+		time:  time,
 	}
 
 	go l.run()
@@ -116,6 +174,17 @@ func (l *lexer) next() rune {
 	return r
 }
 
+// peek returns next rune without consuming it
+func (l *lexer) peek() rune {
+	if l.pos >= len(l.input) {
+		return eof
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.input[l.pos:])
+
+	return r
+}
+
 func (l *lexer) stepBack() {
 	l.pos -= l.lastStep
 	l.lastStep = 0
@@ -132,12 +201,22 @@ func lexUnknown(l *lexer) stateFn {
 		return nil
 	case unicode.IsDigit(r):
 		return lexNumber
+	case isPartOfIdentifier(r) && !unicode.IsDigit(r):
+		return lexIdentifier
 	case unicode.IsSpace(r):
 		l.skip()
 	case r == '(':
 		l.emit(LeftParenthesis)
 	case r == ')':
 		l.emit(RightParenthesis)
+	case r == ',':
+		l.emit(Separator)
+	case r == '[':
+		l.emit(LeftSquareBracket)
+	case r == ']':
+		l.emit(RightSquareBracket)
+	case r == '±':
+		l.emit(PlusMinus)
 	case r == '+':
 		l.emit(Addition)
 	case r == '-':
@@ -156,7 +235,35 @@ func lexUnknown(l *lexer) stateFn {
 	return lexUnknown
 }
 
+// dateLiteral matches dates and timestamps written in ISO 8601, e.g. 2026-10-17 or 2026-10-17T14:30:00+02:00
+var dateLiteral = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:\d{2})?)?`)
+
+// durationUnits are units of components of duration literals, longer units are matched first, e.g. 20min
+var durationUnits = []string{"ms", "min", "w", "d", "h", "m", "s"}
+
+// zoneAreas are areas of names of time zones in the tz database, e.g. Europe/Warsaw
+var zoneAreas = map[string]bool{
+	"Africa": true, "America": true, "Antarctica": true, "Arctic": true, "Asia": true, "Atlantic": true,
+	"Australia": true, "Europe": true, "Indian": true, "Pacific": true, "Etc": true,
+}
+
+// lexNumber lexes numbers, and in time mode dates and durations of two or more components, e.g. 3h 20m,
+// durations of a single component are numbers followed by names, e.g. 45 days
 func lexNumber(l *lexer) stateFn {
+	if l.time {
+		if n := dateLength(l.input[l.start:]); n > 0 {
+			l.pos = l.start + n
+			l.emit(Date)
+			return lexUnknown
+		}
+
+		if n := durationLength(l.input[l.start:]); n > 0 {
+			l.pos = l.start + n
+			l.emit(Duration)
+			return lexUnknown
+		}
+	}
+
 	dotCounter := 0
 
 	for r := l.next(); r != eof && isPartOfNumber(r); r = l.next() {
@@ -175,6 +282,91 @@ func lexNumber(l *lexer) stateFn {
 	return lexUnknown
 }
 
+// dateLength returns length of date at the beginning of input, it is 0 if input does not begin with a date
+func dateLength(input string) int {
+	n := len(dateLiteral.FindString(input))
+	if r, _ := utf8.DecodeRuneInString(input[n:]); n < len(input) && (isPartOfIdentifier(r) || r == '.') {
+		return 0
+	}
+
+	return n
+}
+
+// durationLength returns length of duration of two or more components at the beginning of input,
+// components may be separated with spaces, e.g. 1h 30m or 1h30m
+func durationLength(input string) int {
+	components, end := 0, 0
+
+	for pos := 0; ; components++ {
+		n := len(input[pos:]) - len(strings.TrimLeftFunc(input[pos:], func(r rune) bool { return isPartOfNumber(r) }))
+		if n == 0 {
+			break
+		}
+
+		unit := ""
+		for _, u := range durationUnits {
+			if strings.HasPrefix(input[pos+n:], u) {
+				unit = u
+				break
+			}
+		}
+
+		next := pos + n + len(unit)
+		if r, _ := utf8.DecodeRuneInString(input[next:]); unit == "" || next < len(input) && (unicode.IsLetter(r) || r == '_') {
+			break
+		}
+
+		end = next
+		pos = next + len(input[next:]) - len(strings.TrimLeftFunc(input[next:], unicode.IsSpace))
+	}
+
+	if components < 2 {
+		return 0
+	}
+
+	return end
+}
+
+// lexIdentifier lexes names, names followed by parenthesis are functions, in and to are conversions
+// of units, e.g. 5 km in m, and in time mode names of areas followed by slash are time zones, e.g. Europe/Warsaw
+func lexIdentifier(l *lexer) stateFn {
+	for r := l.next(); r != eof && isPartOfIdentifier(r); r = l.next() {
+	}
+
+	l.stepBack()
+
+	if l.time && zoneAreas[l.input[l.start:l.pos]] && strings.HasPrefix(l.input[l.pos:], "/") {
+		return lexZone
+	}
+
+	rest := strings.TrimLeftFunc(l.input[l.pos:], unicode.IsSpace)
+	switch name := l.input[l.start:l.pos]; {
+	case name == "in" || name == "to":
+		l.emit(Conversion)
+	case strings.HasPrefix(rest, "("):
+		l.emit(Function)
+	default:
+		l.emit(Identifier)
+	}
+
+	return lexUnknown
+}
+
+// lexZone lexes the rest of name of time zone, e.g. America/Port-au-Prince
+func lexZone(l *lexer) stateFn {
+	for r := l.next(); r != eof && (isPartOfIdentifier(r) || r == '/' || r == '-' && unicode.IsLetter(l.peek())); r = l.next() {
+	}
+
+	l.stepBack()
+	l.emit(Zone)
+
+	return lexUnknown
+}
+
+func isPartOfIdentifier(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
 func isPartOfNumber(r rune) bool {
 	return r == '.' || unicode.IsDigit(r)
 }
@@ -184,11 +376,81 @@ func NewItem(t ItemType, val string) Item {
 	return item{t, val}
 }
 
+// NewItemsLexer returns lexer returning already lexed items, allows other notations to feed infix parsers
+func NewItemsLexer(items []Item) Lexer {
+	l := &itemsLexer{items: items}
+
+	return l
+}
+
 // NewEmptyItem returns empty lexer item
 func NewEmptyItem() Item {
 	return item{Empty, ""}
 }
 
+func (l *itemsLexer) NextItem() Item {
+	if l.pos >= len(l.items) {
+		return NewEmptyItem()
+	}
+
+	i := l.items[l.pos]
+	l.pos++
+
+	return i
+}
+
+// FunctionCall returns items of function called with argument, used by notations translated to items
+func FunctionCall(function string, argument []Item) []Item {
+	items := []Item{NewItem(Function, function)}
+	if len(argument) == 1 {
+		argument = []Item{NewItem(LeftParenthesis, "("), argument[0], NewItem(RightParenthesis, ")")}
+	}
+
+	return append(items, Parenthesized(argument)...)
+}
+
+// Parenthesized wraps items in parentheses unless they are a single item or already parenthesized
+func Parenthesized(items []Item) []Item {
+	if len(items) == 1 || (len(items) > 1 && isParenthesized(items)) {
+		return items
+	}
+
+	result := append([]Item{NewItem(LeftParenthesis, "(")}, items...)
+	return append(result, NewItem(RightParenthesis, ")"))
+}
+
+// isParenthesized checks if the first parenthesis is matched by the last item
+func isParenthesized(items []Item) bool {
+	if items[0].GetType() != LeftParenthesis {
+		return false
+	}
+
+	depth := 0
+	for k, i := range items {
+		switch i.GetType() {
+		case LeftParenthesis:
+			depth++
+		case RightParenthesis:
+			depth--
+			if depth == 0 {
+				return k == len(items)-1
+			}
+		}
+	}
+
+	return false
+}
+
+// String returns name of the item type
+func (t ItemType) String() string {
+	name, ok := itemTypeNames[t]
+	if !ok {
+		return fmt.Sprintf("ItemType(%d)", int(t))
+	}
+
+	return name
+}
+
 func (i item) GetType() ItemType {
 	return i.typ
 }
@@ -6,12 +6,14 @@ import (
 	"github.com/google/go-cmp/cmp"
 )
 
+type lexerTestCase struct {
+	name     string
+	input    string
+	expected []Item
+}
+
 func TestLexer(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected []Item
-	}{
+	tests := []lexerTestCase{
 		{
 			"Success",
 			"1+2*(3^2/0.5534)-   5.0",
@@ -31,6 +33,96 @@ func TestLexer(t *testing.T) {
 				item{Number, "5.0"},
 			},
 		},
+		{
+			"Success identifiers and functions",
+			"2*x_1 + sqrt (pi)",
+			[]Item{
+				item{Number, "2"},
+				item{Multiplication, "*"},
+				item{Identifier, "x_1"},
+				item{Addition, "+"},
+				item{Function, "sqrt"},
+				item{LeftParenthesis, "("},
+				item{Identifier, "pi"},
+				item{RightParenthesis, ")"},
+			},
+		},
+		{
+			"Success intervals",
+			"[1.9, 2.1] * 5 ± 0.2",
+			[]Item{
+				item{LeftSquareBracket, "["},
+				item{Number, "1.9"},
+				item{Separator, ","},
+				item{Number, "2.1"},
+				item{RightSquareBracket, "]"},
+				item{Multiplication, "*"},
+				item{Number, "5"},
+				item{PlusMinus, "±"},
+				item{Number, "0.2"},
+			},
+		},
+		{
+			"Success units conversion",
+			"60 mph to km/h in m/s",
+			[]Item{
+				item{Number, "60"},
+				item{Identifier, "mph"},
+				item{Conversion, "to"},
+				item{Identifier, "km"},
+				item{Division, "/"},
+				item{Identifier, "h"},
+				item{Conversion, "in"},
+				item{Identifier, "m"},
+				item{Division, "/"},
+				item{Identifier, "s"},
+			},
+		},
+		{
+			"Success dates and durations are numbers outside time mode",
+			"2026-10-17 + 3h 20m",
+			[]Item{
+				item{Number, "2026"},
+				item{Subtraction, "-"},
+				item{Number, "10"},
+				item{Subtraction, "-"},
+				item{Number, "17"},
+				item{Addition, "+"},
+				item{Number, "3"},
+				item{Identifier, "h"},
+				item{Number, "20"},
+				item{Identifier, "m"},
+			},
+		},
+		{
+			"Success time zones are names outside time mode",
+			"Europe/Warsaw",
+			[]Item{
+				item{Identifier, "Europe"},
+				item{Division, "/"},
+				item{Identifier, "Warsaw"},
+			},
+		},
+		{
+			"Error, single equals sign",
+			"1=2",
+			[]Item{
+				item{Number, "1"},
+				item{Error, "invalid rune at: 1; could not lex: ="},
+			},
+		},
+		{
+			"Success separator",
+			"diff(x, x)",
+			[]Item{
+				item{Function, "diff"},
+				item{LeftParenthesis, "("},
+				item{Identifier, "x"},
+				item{Separator, ","},
+				item{Identifier, "x"},
+				item{RightParenthesis, ")"},
+			},
+		},
 		{
 			"Error, cannot start with .",
 			".5534-5.0",
@@ -54,9 +146,62 @@ func TestLexer(t *testing.T) {
 		},
 	}
 
+	runLexerTests(t, tests, Lex)
+}
+
+func TestLexTime(t *testing.T) {
+	tests := []lexerTestCase{
+		{
+			"Success dates and durations",
+			"2026-10-17 + 45 days - 2026-10-17T14:30:00+02:00 + 3h 20m * 4 + 1h30m",
+			[]Item{
+				item{Date, "2026-10-17"},
+				item{Addition, "+"},
+				item{Number, "45"},
+				item{Identifier, "days"},
+				item{Subtraction, "-"},
+				item{Date, "2026-10-17T14:30:00+02:00"},
+				item{Addition, "+"},
+				item{Duration, "3h 20m"},
+				item{Multiplication, "*"},
+				item{Number, "4"},
+				item{Addition, "+"},
+				item{Duration, "1h30m"},
+			},
+		},
+		{
+			"Success time zones",
+			"now in Europe/Warsaw - America/Port-au-Prince",
+			[]Item{
+				item{Identifier, "now"},
+				item{Conversion, "in"},
+				item{Zone, "Europe/Warsaw"},
+				item{Subtraction, "-"},
+				item{Zone, "America/Port-au-Prince"},
+			},
+		},
+		{
+			"Success area which is not a time zone",
+			"Europe / 2 + 5m 3",
+			[]Item{
+				item{Identifier, "Europe"},
+				item{Division, "/"},
+				item{Number, "2"},
+				item{Addition, "+"},
+				item{Number, "5"},
+				item{Identifier, "m"},
+				item{Number, "3"},
+			},
+		},
+	}
+
+	runLexerTests(t, tests, LexTime)
+}
+
+func runLexerTests(t *testing.T, tests []lexerTestCase, lex func(string) Lexer) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			l := Lex(tt.input)
+			l := lex(tt.input)
 
 			result := []Item{}
 
@@ -75,3 +220,57 @@ func TestLexer(t *testing.T) {
 	}
 
 }
+
+func TestItemsLexer(t *testing.T) {
+	expected := []Item{
+		item{Function, "sqrt"},
+		item{LeftParenthesis, "("},
+		item{Number, "4"},
+		item{RightParenthesis, ")"},
+	}
+
+	l := NewItemsLexer(expected)
+
+	result := []Item{}
+	for i := l.NextItem(); i.GetType() != Empty; i = l.NextItem() {
+		result = append(result, i)
+	}
+
+	if !cmp.Equal(expected, result, cmp.AllowUnexported(item{})) {
+		t.Errorf("expected: %v, got: %v", expected, result)
+	}
+}
+
+func TestFunctionCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		argument []Item
+		expected []Item
+	}{
+		{
+			"Single item",
+			[]Item{item{Number, "4"}},
+			[]Item{item{Function, "sqrt"}, item{LeftParenthesis, "("}, item{Number, "4"}, item{RightParenthesis, ")"}},
+		},
+		{
+			"Already parenthesized",
+			[]Item{item{LeftParenthesis, "("}, item{Number, "4"}, item{RightParenthesis, ")"}},
+			[]Item{item{Function, "sqrt"}, item{LeftParenthesis, "("}, item{Number, "4"}, item{RightParenthesis, ")"}},
+		},
+		{
+			"Parentheses not matched by the last item",
+			[]Item{item{LeftParenthesis, "("}, item{Number, "1"}, item{RightParenthesis, ")"}, item{Multiplication, "*"}, item{LeftParenthesis, "("}, item{Number, "2"}, item{RightParenthesis, ")"}},
+			[]Item{item{Function, "sqrt"}, item{LeftParenthesis, "("}, item{LeftParenthesis, "("}, item{Number, "1"}, item{RightParenthesis, ")"}, item{Multiplication, "*"}, item{LeftParenthesis, "("}, item{Number, "2"}, item{RightParenthesis, ")"}, item{RightParenthesis, ")"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FunctionCall("sqrt", tt.argument)
+
+			if !cmp.Equal(tt.expected, result, cmp.AllowUnexported(item{})) {
+				t.Errorf("expected: %v, got: %v", tt.expected, result)
+			}
+		})
+	}
+}
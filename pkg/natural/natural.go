@@ -0,0 +1,570 @@
+package natural
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+	rpn "github.com/mateuszkrasucki/calculator/pkg/reversepolish"
+)
+
+type word struct {
+	value string
+	pos   int
+}
+
+type parser struct {
+	words []word
+	pos   int
+}
+
+var units = map[string]int64{
+	"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7, "eight": 8, "nine": 9,
+	"ten": 10, "eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16,
+	"seventeen": 17, "eighteen": 18, "nineteen": 19,
+}
+
+var tens = map[string]int64{
+	"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50, "sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+}
+
+var scales = map[string]int64{
+	"thousand": 1000, "million": 1000000, "billion": 1000000000, "trillion": 1000000000000,
+}
+
+// phrases applying function to the operand following them
+var functionPhrases = []struct {
+	words    []string
+	function string
+}{
+	{[]string{"square", "root", "of"}, "sqrt"},
+	{[]string{"absolute", "value", "of"}, "abs"},
+	{[]string{"natural", "logarithm", "of"}, "ln"},
+	{[]string{"natural", "log", "of"}, "ln"},
+	{[]string{"logarithm", "of"}, "log"},
+	{[]string{"log", "of"}, "log"},
+	{[]string{"sine", "of"}, "sin"},
+	{[]string{"cosine", "of"}, "cos"},
+	{[]string{"tangent", "of"}, "tan"},
+}
+
+// phrases multiplying the operand following them by a fraction
+var fractionPhrases = []struct {
+	words       []string
+	numerator   string
+	denominator string
+}{
+	{[]string{"half", "of"}, "1", "2"},
+	{[]string{"a", "half", "of"}, "1", "2"},
+	{[]string{"one", "half", "of"}, "1", "2"},
+	{[]string{"a", "third", "of"}, "1", "3"},
+	{[]string{"one", "third", "of"}, "1", "3"},
+	{[]string{"a", "quarter", "of"}, "1", "4"},
+	{[]string{"one", "quarter", "of"}, "1", "4"},
+	{[]string{"quarter", "of"}, "1", "4"},
+	{[]string{"double"}, "2", "1"},
+	{[]string{"twice"}, "2", "1"},
+}
+
+// words and phrases without mathematical meaning
+var fillers = [][]string{
+	{"what", "is"},
+	{"what's"},
+	{"how", "much", "is"},
+	{"calculate"},
+	{"compute"},
+	{"?"},
+}
+
+// Parse provides parsing of arithmetic operations written as english phrases for postfix calculator
+func Parse(ctx context.Context, input string) (calculator.OperationInterface, error) {
+	return rpn.ParseItems(ctx, Lex(input))
+}
+
+// Lex returns lexer translating english phrases into infix items, errors are returned as lexer.Error items
+func Lex(input string) lexer.Lexer {
+	items, err := translate(input)
+	if err != nil {
+		return lexer.NewItemsLexer([]lexer.Item{lexer.NewItem(lexer.Error, err.Error())})
+	}
+
+	return lexer.NewItemsLexer(items)
+}
+
+func translate(input string) ([]lexer.Item, error) {
+	p := &parser{words: split(input)}
+
+	p.skipFillers()
+	if len(p.words) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	items, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipFillers()
+	if w, ok := p.peek(); ok {
+		return nil, unknownWord(w)
+	}
+
+	return items, nil
+}
+
+// split divides input into lowercase words, parentheses and operator signs are separate words, hyphens join words like twenty-five
+func split(input string) []word {
+	words := []word{}
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 {
+			words = append(words, word{strings.ToLower(input[start:end]), start})
+			start = -1
+		}
+	}
+
+	for pos := 0; pos < len(input); {
+		r, w := utf8.DecodeRuneInString(input[pos:])
+
+		switch {
+		case unicode.IsSpace(r) || r == ',':
+			flush(pos)
+		case r == '-' && start >= 0 && !isNumeric(input[start:pos]):
+			flush(pos)
+		case strings.ContainsRune("()%?+-*/", r):
+			flush(pos)
+			words = append(words, word{string(r), pos})
+		case start < 0:
+			start = pos
+		}
+
+		pos += w
+	}
+	flush(len(input))
+
+	return words
+}
+
+// parseExpression parses terms separated by plus and minus
+func (p *parser) parseExpression() ([]lexer.Item, error) {
+	items, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var operator lexer.Item
+		switch {
+		case p.accept("plus"), p.accept("+"):
+			operator = lexer.NewItem(lexer.Addition, "+")
+		case p.accept("minus"), p.accept("-"):
+			operator = lexer.NewItem(lexer.Subtraction, "-")
+		default:
+			return items, nil
+		}
+
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, operator)
+		items = append(items, term...)
+	}
+}
+
+// parseTerm parses factors separated by times and divided by
+func (p *parser) parseTerm() ([]lexer.Item, error) {
+	items, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var operator lexer.Item
+		switch {
+		case p.accept("times"), p.accept("multiplied", "by"), p.accept("*"):
+			operator = lexer.NewItem(lexer.Multiplication, "*")
+		case p.accept("divided", "by"), p.accept("over"), p.accept("/"):
+			operator = lexer.NewItem(lexer.Division, "/")
+		default:
+			return items, nil
+		}
+
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, operator)
+		items = append(items, factor...)
+	}
+}
+
+// parseFactor parses negated powers
+func (p *parser) parseFactor() ([]lexer.Item, error) {
+	if p.accept("minus") || p.accept("negative") || p.accept("-") {
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]lexer.Item{lexer.NewItem(lexer.Subtraction, "-")}, factor...), nil
+	}
+
+	return p.parsePower()
+}
+
+// parsePower parses operand raised to the power, squared or cubed
+func (p *parser) parsePower() ([]lexer.Item, error) {
+	base, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var exponent []lexer.Item
+		switch {
+		case p.accept("squared"):
+			exponent = []lexer.Item{lexer.NewItem(lexer.Number, "2")}
+		case p.accept("cubed"):
+			exponent = []lexer.Item{lexer.NewItem(lexer.Number, "3")}
+		case p.accept("to", "the", "power", "of"), p.accept("raised", "to", "the", "power", "of"), p.accept("raised", "to"):
+			exponent, err = p.parseFactor()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return base, nil
+		}
+
+		base = append(lexer.Parenthesized(base), lexer.NewItem(lexer.Exponent, "^"))
+		base = lexer.Parenthesized(append(base, lexer.Parenthesized(exponent)...))
+	}
+}
+
+// parseOperand parses numbers, percentages, parenthesized expressions and phrases like square root of
+func (p *parser) parseOperand() ([]lexer.Item, error) {
+	p.accept("the")
+
+	for _, phrase := range functionPhrases {
+		if p.accept(phrase.words...) {
+			operand, err := p.parseFactor()
+			if err != nil {
+				return nil, err
+			}
+
+			return lexer.FunctionCall(phrase.function, operand), nil
+		}
+	}
+
+	if p.accept("cube", "root", "of") {
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		items := append(lexer.Parenthesized(operand), lexer.NewItem(lexer.Exponent, "^"))
+		return lexer.Parenthesized(append(items, lexer.Parenthesized(numbers("1", "/", "3"))...)), nil
+	}
+
+	for _, phrase := range fractionPhrases {
+		if p.accept(phrase.words...) {
+			operand, err := p.parseFactor()
+			if err != nil {
+				return nil, err
+			}
+
+			items := append(numbers(phrase.numerator, "*"), lexer.Parenthesized(operand)...)
+			return lexer.Parenthesized(append(items, numbers("/", phrase.denominator)...)), nil
+		}
+	}
+
+	if p.accept("(") {
+		items, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.accept(")") {
+			return nil, p.unexpected()
+		}
+
+		return lexer.Parenthesized(items), nil
+	}
+
+	if p.accept("pi") {
+		return []lexer.Item{lexer.NewItem(lexer.Constant, "pi")}, nil
+	}
+
+	number, err := p.parseNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parsePercent(number)
+}
+
+// parsePercent parses optional percent or percent of following the number
+func (p *parser) parsePercent(number []lexer.Item) ([]lexer.Item, error) {
+	if !p.accept("percent") && !p.accept("per", "cent") && !p.accept("%") {
+		return number, nil
+	}
+
+	items := append(number, numbers("/", "100")...)
+	if !p.accept("of") {
+		return lexer.Parenthesized(items), nil
+	}
+
+	operand, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	items = append(items, lexer.NewItem(lexer.Multiplication, "*"))
+	return lexer.Parenthesized(append(items, lexer.Parenthesized(operand)...)), nil
+}
+
+// parseNumber parses number written with digits or words, i.e. "three hundred and five point two"
+func (p *parser) parseNumber() ([]lexer.Item, error) {
+	w, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+
+	if isNumeric(w.value) {
+		p.pos++
+
+		if _, err := strconv.ParseFloat(w.value, 64); err != nil {
+			return nil, unknownWord(w)
+		}
+
+		if scale, ok := p.peekScale(); ok {
+			p.pos++
+			return lexer.Parenthesized(append(numbers(w.value, "*"), numbers(strconv.FormatInt(scale, 10))...)), nil
+		}
+
+		return numbers(w.value), nil
+	}
+
+	if !p.startsNumber() {
+		return nil, unknownWord(w)
+	}
+
+	integer := int64(0)
+	if w.value != "point" {
+		var err error
+		integer, err = p.parseInteger()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	value := strconv.FormatInt(integer, 10)
+	if !p.accept("point") {
+		return numbers(value), nil
+	}
+
+	digits := ""
+	for w, ok := p.peek(); ok && isDigitWord(w.value); w, ok = p.peek() {
+		digits += strconv.FormatInt(units[w.value], 10)
+		p.pos++
+	}
+
+	if digits == "" {
+		return nil, p.unexpected()
+	}
+
+	return numbers(value + "." + digits), nil
+}
+
+// parseInteger parses integers written with words up to trillions
+func (p *parser) parseInteger() (int64, error) {
+	var total, current int64
+	lastScale := int64(0)
+	expectsNumber := true
+
+	for w, ok := p.peek(); ok; w, ok = p.peek() {
+		u, isUnit := units[w.value]
+		t, isTens := tens[w.value]
+		scale, isScale := scales[w.value]
+
+		switch {
+		case w.value == "zero" && expectsNumber:
+			// zero is only written as the whole number, i.e. not in "zero five" or "twenty zero"
+			p.pos++
+			return 0, nil
+		case isUnit && u > 0 && current%100 == 0:
+			current += u
+		case isUnit && u > 0 && u < 10 && current%100 >= 20 && current%10 == 0:
+			current += u
+		case isTens && current%100 == 0:
+			current += t
+		case w.value == "a" && current == 0 && p.nextIsScale():
+			current = 1
+		case w.value == "hundred" && !expectsNumber && current > 0 && current < 100:
+			current *= 100
+		case isScale && !expectsNumber && current > 0 && (lastScale == 0 || scale < lastScale):
+			total += current * scale
+			current = 0
+			lastScale = scale
+		case w.value == "and" && !expectsNumber && p.afterAndIsNumber():
+		default:
+			if expectsNumber {
+				return 0, p.unexpected()
+			}
+
+			return total + current, nil
+		}
+
+		expectsNumber = false
+		p.pos++
+	}
+
+	if expectsNumber {
+		return 0, p.unexpected()
+	}
+
+	return total + current, nil
+}
+
+func (p *parser) startsNumber() bool {
+	w, ok := p.peek()
+	if !ok {
+		return false
+	}
+
+	_, isUnit := units[w.value]
+	_, isTens := tens[w.value]
+
+	return isUnit || isTens || w.value == "point" || (w.value == "a" && p.nextIsScale())
+}
+
+func (p *parser) nextIsScale() bool {
+	if p.pos+1 >= len(p.words) {
+		return false
+	}
+
+	_, isScale := scales[p.words[p.pos+1].value]
+	return isScale || p.words[p.pos+1].value == "hundred"
+}
+
+// afterAndIsNumber allows "and" inside numbers, i.e. "one hundred and five", but not between numbers and other words
+func (p *parser) afterAndIsNumber() bool {
+	if p.pos+1 >= len(p.words) {
+		return false
+	}
+
+	_, isUnit := units[p.words[p.pos+1].value]
+	_, isTens := tens[p.words[p.pos+1].value]
+
+	return isUnit || isTens
+}
+
+func (p *parser) peekScale() (int64, bool) {
+	w, ok := p.peek()
+	if !ok {
+		return 0, false
+	}
+
+	if w.value == "hundred" {
+		return 100, true
+	}
+
+	scale, ok := scales[w.value]
+	return scale, ok
+}
+
+func (p *parser) skipFillers() {
+	for {
+		skipped := false
+		for _, filler := range fillers {
+			if p.accept(filler...) {
+				skipped = true
+			}
+		}
+
+		if !skipped {
+			return
+		}
+	}
+}
+
+// accept consumes the words if they are next in the input
+func (p *parser) accept(words ...string) bool {
+	if p.pos+len(words) > len(p.words) {
+		return false
+	}
+
+	for k, w := range words {
+		if p.words[p.pos+k].value != w {
+			return false
+		}
+	}
+
+	p.pos += len(words)
+	return true
+}
+
+func (p *parser) peek() (word, bool) {
+	if p.pos >= len(p.words) {
+		return word{}, false
+	}
+
+	return p.words[p.pos], true
+}
+
+func (p *parser) unexpected() error {
+	w, ok := p.peek()
+	if !ok {
+		return fmt.Errorf("unexpected end of input")
+	}
+
+	return unknownWord(w)
+}
+
+func unknownWord(w word) error {
+	return fmt.Errorf("unknown word at: %d; could not understand: %s", w.pos, w.value)
+}
+
+func isNumeric(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if !unicode.IsDigit(r) && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDigitWord(value string) bool {
+	u, ok := units[value]
+	return ok && u < 10
+}
+
+// numbers returns number items for numeric strings and operator items for operator signs
+func numbers(values ...string) []lexer.Item {
+	items := []lexer.Item{}
+	for _, v := range values {
+		switch v {
+		case "*":
+			items = append(items, lexer.NewItem(lexer.Multiplication, v))
+		case "/":
+			items = append(items, lexer.NewItem(lexer.Division, v))
+		default:
+			items = append(items, lexer.NewItem(lexer.Number, v))
+		}
+	}
+
+	return items
+}
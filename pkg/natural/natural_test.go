@@ -0,0 +1,150 @@
+package natural
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedResult float64
+		expectedError  error
+	}{
+		{
+			"Success times parantheses",
+			"twelve times (three plus four)",
+			84,
+			nil,
+		},
+		{
+			"Success half of",
+			"half of 250",
+			125,
+			nil,
+		},
+		{
+			"Success percent of",
+			"20 percent of 80",
+			16,
+			nil,
+		},
+		{
+			"Success square root",
+			"What is the square root of 2?",
+			math.Sqrt2,
+			nil,
+		},
+		{
+			"Success large numbers",
+			"two trillion three hundred and five billion forty-two million a thousand and nineteen",
+			2305042001019,
+			nil,
+		},
+		{
+			"Success digits with scale",
+			"5 million divided by twelve hundred",
+			5000000.0 / 1200.0,
+			nil,
+		},
+		{
+			"Success decimals",
+			"three point one four plus point five",
+			3.64,
+			nil,
+		},
+		{
+			"Success negatives",
+			"minus five minus negative two times -3",
+			-11,
+			nil,
+		},
+		{
+			"Success powers",
+			"two to the power of ten minus three squared",
+			1015,
+			nil,
+		},
+		{
+			"Success cube root and fraction phrases",
+			"cube root of twenty-seven times a quarter of eight",
+			6,
+			nil,
+		},
+		{
+			"Error unknown word",
+			"twelve times banana",
+			0,
+			errors.NewParsingError("unknown word at: 13; could not understand: banana"),
+		},
+		{
+			"Error unknown word after number",
+			"five apples plus two",
+			0,
+			errors.NewParsingError("unknown word at: 5; could not understand: apples"),
+		},
+		{
+			"Error invalid number",
+			"twenty twenty",
+			0,
+			errors.NewParsingError("unknown word at: 7; could not understand: twenty"),
+		},
+		{
+			"Success zero",
+			"zero point five plus zero",
+			0.5,
+			nil,
+		},
+		{
+			"Error number after zero",
+			"zero five",
+			0,
+			errors.NewParsingError("unknown word at: 5; could not understand: five"),
+		},
+		{
+			"Error zero after tens",
+			"twenty zero",
+			0,
+			errors.NewParsingError("unknown word at: 7; could not understand: zero"),
+		},
+		{
+			"Error zero after hundreds",
+			"one hundred zero",
+			0,
+			errors.NewParsingError("unknown word at: 12; could not understand: zero"),
+		},
+		{
+			"Error unexpected end",
+			"two plus",
+			0,
+			errors.NewParsingError("unexpected end of input"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result float64
+			operation, err := Parse(context.Background(), tt.input)
+			if err == nil {
+				result, err = operation.Calculate(context.Background())
+			}
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && err != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if math.Abs(tt.expectedResult-result) > 1e-9 {
+				t.Errorf("expected result to be %v, got %v", tt.expectedResult, result)
+			}
+		})
+	}
+}
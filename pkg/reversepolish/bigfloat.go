@@ -0,0 +1,285 @@
+package reversepolish
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/bigmath"
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// defaultBigFloatDigits is the number of significant decimal digits of big floats if mode does not set precision
+const defaultBigFloatDigits = 50
+
+// maxBigFloatPrecision is the highest precision of big floats in bits
+const maxBigFloatPrecision = 1 << 16
+
+// guardBits are added to precision of functions, so their results are rounded correctly in most cases
+const guardBits = 64
+
+// bigFloatArithmetic rounds result of every operation to precision bits using rounding mode,
+// results are written with digits significant decimal digits rounded once more using rounding mode
+type bigFloatArithmetic struct {
+	precision uint
+	digits    int
+	rounding  big.RoundingMode
+}
+
+type bigFloatValue struct {
+	value    *big.Float
+	digits   int
+	rounding big.RoundingMode
+}
+
+func newBigFloatArithmetic(mode calculator.Mode) (arithmetic, error) {
+	rounding, err := roundingMode(mode.Rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	a := bigFloatArithmetic{precision: mode.Precision, digits: int(mode.Digits), rounding: rounding}
+
+	switch {
+	case mode.Precision > 0 && mode.Digits > 0:
+		return nil, errors.NewInputError("Only one of precision and digits can be set")
+	case mode.Precision > 0:
+		a.digits = max(int(float64(mode.Precision)*math.Log10(2)), 1)
+	case mode.Digits > 0:
+		a.precision = digitsPrecision(a.digits)
+	default:
+		a.digits = defaultBigFloatDigits
+		a.precision = digitsPrecision(a.digits)
+	}
+
+	if a.precision > maxBigFloatPrecision {
+		return nil, errors.NewInputError(fmt.Sprintf("Precision has to be at most %d bits", maxBigFloatPrecision))
+	}
+
+	return a, nil
+}
+
+// digitsPrecision returns precision in bits of results written with digits significant decimal digits, guard bits
+// keep errors of rounded operations out of the last written digit, e.g. 2 / 3 is written as 0.666…667
+func digitsPrecision(digits int) uint {
+	return uint(math.Ceil(float64(digits)*math.Log2(10))) + guardBits
+}
+
+func (a bigFloatArithmetic) newFloat() *big.Float {
+	return new(big.Float).SetPrec(a.precision).SetMode(a.rounding)
+}
+
+// round returns result of function rounded to precision, infinite results are calculation errors
+func (a bigFloatArithmetic) round(f *big.Float) (interface{}, error) {
+	if f.IsInf() {
+		return nil, errors.NewCalculationError("result is too large")
+	}
+
+	return a.newFloat().Set(f), nil
+}
+
+func (a bigFloatArithmetic) number(item lexer.Item) (interface{}, error) {
+	switch item.GetString() {
+	case "pi":
+		return a.round(bigmath.Pi(a.precision + guardBits))
+	case "e":
+		return a.round(bigmath.Exp(new(big.Float).SetPrec(a.precision + guardBits).SetInt64(1)))
+	}
+
+	f, ok := a.newFloat().SetString(item.GetString())
+	if !ok {
+		return nil, errors.NewParsingError(fmt.Sprintf("could not parse %s as a number", item.GetString()))
+	}
+
+	return f, nil
+}
+
+func (a bigFloatArithmetic) operator(operator string, x, y interface{}) (interface{}, error) {
+	f, g := x.(*big.Float), y.(*big.Float)
+
+	switch operator {
+	case "+":
+		return a.round(a.newFloat().Add(f, g))
+	case "-":
+		return a.round(a.newFloat().Sub(f, g))
+	case "*":
+		return a.round(a.newFloat().Mul(f, g))
+	case "/":
+		if g.Sign() == 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+		return a.round(a.newFloat().Quo(f, g))
+	case "^":
+		power, err := bigmath.Pow(new(big.Float).SetPrec(a.precision+guardBits).Set(f), g)
+		if err != nil {
+			return nil, err
+		}
+		return a.round(power)
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown operator: %s", operator))
+	}
+}
+
+func (a bigFloatArithmetic) negate(x interface{}) (interface{}, error) {
+	return a.newFloat().Neg(x.(*big.Float)), nil
+}
+
+func (a bigFloatArithmetic) function(name string, x interface{}) (interface{}, error) {
+	f := new(big.Float).SetPrec(a.precision + guardBits).Set(x.(*big.Float))
+
+	var result *big.Float
+	var err error
+
+	switch name {
+	case "sqrt":
+		if f.Sign() < 0 {
+			return nil, errors.NewCalculationError("square root of negative number")
+		}
+		result = new(big.Float).SetPrec(a.precision + guardBits).Sqrt(f)
+	case "abs":
+		return a.newFloat().Abs(x.(*big.Float)), nil
+	case "re", "conj":
+		return x, nil
+	case "im":
+		return a.newFloat(), nil
+	case "arg":
+		if f.Sign() >= 0 {
+			return a.newFloat(), nil
+		}
+		result = bigmath.Pi(f.Prec())
+	case "floor", "ceil":
+		i, accuracy := f.Int(nil)
+		if name == "floor" && accuracy == big.Above {
+			i.Sub(i, big.NewInt(1))
+		}
+		if name == "ceil" && accuracy == big.Below {
+			i.Add(i, big.NewInt(1))
+		}
+		return a.newFloat().SetInt(i), nil
+	case "exp":
+		result = bigmath.Exp(f)
+	case "ln":
+		result, err = bigmath.Log(f)
+	case "log":
+		result, err = bigmath.Log10(f)
+	case "sin":
+		result = bigmath.Sin(f)
+	case "cos":
+		result = bigmath.Cos(f)
+	case "tan":
+		result, err = bigmath.Tan(f)
+	case "asin":
+		result, err = bigmath.Asin(f)
+	case "acos":
+		result, err = bigmath.Acos(f)
+	case "atan":
+		result = bigmath.Atan(f)
+	case "sinh":
+		result = bigmath.Sinh(f)
+	case "cosh":
+		result = bigmath.Cosh(f)
+	case "tanh":
+		result = bigmath.Tanh(f)
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown function: %s", name))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.round(result)
+}
+
+func (a bigFloatArithmetic) value(x interface{}) (calculator.Value, error) {
+	return bigFloatValue{x.(*big.Float), a.digits, a.rounding}, nil
+}
+
+func (v bigFloatValue) Float64() float64 {
+	f, _ := v.value.Float64()
+
+	return f
+}
+
+// String writes value rounded to significant digits without trailing zeros, exponent is used only
+// for values which would need more than digits zeros
+func (v bigFloatValue) String() string {
+	return formatDecimal(roundedText(v.value, v.digits, v.rounding), v.digits)
+}
+
+// roundedText writes x in scientific notation rounded to significant digits using rounding mode,
+// e.g. 6.6667e-01 for 2 / 3 rounded up to 5 digits
+func roundedText(x *big.Float, digits int, rounding big.RoundingMode) string {
+	scientific := x.Text('e', digits-1)
+	if rounding == big.ToNearestEven || x.Sign() == 0 || x.IsInf() {
+		return scientific
+	}
+
+	_, exponentText, _ := strings.Cut(scientific, "e")
+	exponent, _ := strconv.Atoi(exponentText)
+
+	exact, _ := x.Rat(nil)
+	one := decimalArithmetic{one: big.NewInt(1), rounding: rounding}
+
+	// exponent of value rounded to nearest may differ by one from exponent of value rounded using rounding mode,
+	// e.g. 9.996 is 1.00e+01 rounded to nearest but 9.99e+00 rounded down
+	for {
+		scaled := new(big.Rat).Set(exact)
+		if scale := digits - 1 - exponent; scale >= 0 {
+			scaled.Mul(scaled, new(big.Rat).SetInt(pow10(scale)))
+		} else {
+			scaled.Quo(scaled, new(big.Rat).SetInt(pow10(-scale)))
+		}
+
+		mantissa := new(big.Int).Abs(one.round(scaled)).String()
+		switch {
+		case len(mantissa) > digits:
+			exponent++
+		case len(mantissa) < digits:
+			exponent--
+		default:
+			sign := ""
+			if x.Sign() < 0 {
+				sign = "-"
+			}
+			return fmt.Sprintf("%s%s.%se%+03d", sign, mantissa[:1], mantissa[1:], exponent)
+		}
+	}
+}
+
+// formatDecimal rewrites number in scientific notation, e.g. -1.2500e+02, in positional notation
+// if it has at most digits zeros before or after significant digits
+func formatDecimal(scientific string, digits int) string {
+	mantissa, exponentText, _ := strings.Cut(scientific, "e")
+
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") {
+		sign, mantissa = "-", mantissa[1:]
+	}
+
+	significant := strings.TrimRight(strings.Replace(mantissa, ".", "", 1), "0")
+	if significant == "" {
+		return "0"
+	}
+
+	var exponent int
+	fmt.Sscan(exponentText, &exponent)
+
+	switch {
+	case exponent >= digits || exponent < -digits:
+		if len(significant) > 1 {
+			significant = significant[:1] + "." + significant[1:]
+		}
+		return fmt.Sprintf("%s%se%+d", sign, significant, exponent)
+	case exponent < 0:
+		return sign + "0." + strings.Repeat("0", -exponent-1) + significant
+	case exponent+1 >= len(significant):
+		return sign + significant + strings.Repeat("0", exponent+1-len(significant))
+	default:
+		return sign + significant[:exponent+1] + "." + significant[exponent+1:]
+	}
+}
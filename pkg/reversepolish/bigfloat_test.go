@@ -0,0 +1,117 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestCalculateBigFloat(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		mode            calculator.Mode
+		expectedDecimal string
+		expectedError   error
+	}{
+		{"Decimal fractions", "0.1 + 0.2", calculator.Mode{}, "0.3", nil},
+		{"Double precision", "0.1 + 0.2", calculator.Mode{Precision: 53}, "0.3", nil},
+		{"Digits", "1 / 3", calculator.Mode{Digits: 10}, "0.3333333333", nil},
+		{"Rounding up", "2 / 3", calculator.Mode{Digits: 5, Rounding: "up"}, "0.66667", nil},
+		{"Rounding down", "2 / 3", calculator.Mode{Digits: 5, Rounding: "down"}, "0.66666", nil},
+		{"Rounding floor of negative numbers", "-2 / 3", calculator.Mode{Digits: 5, Rounding: "floor"}, "-0.66667", nil},
+		{"Rounding ceiling of negative numbers", "-2 / 3", calculator.Mode{Digits: 5, Rounding: "ceiling"}, "-0.66666", nil},
+		{"Rounding down to lower exponent", "9.996", calculator.Mode{Digits: 3, Rounding: "down"}, "9.99", nil},
+		{"Rounding up to higher exponent", "9.991", calculator.Mode{Digits: 3, Rounding: "up"}, "10", nil},
+		{"Last digit of default digits", "2 / 3", calculator.Mode{}, "0.66666666666666666666666666666666666666666666666667", nil},
+		{"Last digit of digits", "sqrt(2)", calculator.Mode{Digits: 50}, "1.4142135623730950488016887242096980785696718753769", nil},
+		{"Last digit of sum", "1 / 3 + 1 / 3", calculator.Mode{Digits: 50}, "0.66666666666666666666666666666666666666666666666667", nil},
+		{"Large integers", "2 ^ 100 + 1", calculator.Mode{Digits: 40}, "1267650600228229401496703205377", nil},
+		{"Exponent", "10 ^ 60", calculator.Mode{Digits: 20}, "1e+60", nil},
+		{"Small numbers", "1 / 2 ^ 10", calculator.Mode{}, "0.0009765625", nil},
+		{"Negative numbers", "-(3 - 5.5)^3", calculator.Mode{}, "15.625", nil},
+		{"Fractional power", "2 ^ 0.5", calculator.Mode{Digits: 30}, "1.41421356237309504880168872421", nil},
+		{"Square root", "sqrt(2)", calculator.Mode{Digits: 30}, "1.41421356237309504880168872421", nil},
+		{"Pi", "pi", calculator.Mode{Digits: 40}, "3.141592653589793238462643383279502884197", nil},
+		{"Exponential function", "exp(1) - e", calculator.Mode{}, "0", nil},
+		{"Logarithms", "ln(e ^ 2) + log(1000)", calculator.Mode{}, "5", nil},
+		{"Trigonometric functions", "sin(pi / 6) + cos(pi / 3) + tan(pi / 4)", calculator.Mode{Digits: 30}, "2", nil},
+		{"Inverse trigonometric functions", "asin(1) + acos(1) - atan(1) * 2", calculator.Mode{Digits: 30}, "0", nil},
+		{"Hyperbolic functions", "cosh(1) ^ 2 - sinh(1) ^ 2 + tanh(0)", calculator.Mode{Digits: 30}, "1", nil},
+		{"Floor and ceiling", "floor(-2.5) + ceil(2.5) + abs(-1)", calculator.Mode{}, "1", nil},
+		{"Division by zero", "1 / 0", calculator.Mode{}, "", errors.NewCalculationError("division by zero")},
+		{"Logarithm of zero", "ln(0)", calculator.Mode{}, "", errors.NewCalculationError("logarithm of non-positive number")},
+		{"Square root of negative number", "sqrt(-1)", calculator.Mode{}, "", errors.NewCalculationError("square root of negative number")},
+		{"Arcsine out of range", "asin(2)", calculator.Mode{}, "", errors.NewCalculationError("argument of arcsine has to be between -1 and 1")},
+		{"Unknown rounding mode", "1", calculator.Mode{Rounding: "sideways"}, "", errors.NewInputError("Unknown rounding mode: sideways")},
+		{"Precision and digits", "1", calculator.Mode{Precision: 10, Digits: 10}, "", errors.NewInputError("Only one of precision and digits can be set")},
+		{"Precision too high", "1", calculator.Mode{Precision: 1 << 20}, "", errors.NewInputError("Precision has to be at most 65536 bits")},
+		{"Unbound variable", "x + 1", calculator.Mode{}, "", errors.NewCalculationError("unbound variable: x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			tt.mode.Name = calculator.BigFloatMode
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), tt.mode)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if value.String() != tt.expectedDecimal {
+				t.Errorf("expected decimal result to be %s, got %s", tt.expectedDecimal, value.String())
+			}
+		})
+	}
+}
+
+func TestCalculateUnknownMode(t *testing.T) {
+	operation, err := ParseInfix(context.Background(), "1 + 1")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	_, err = operation.(calculator.ModeOperation).CalculateMode(context.Background(), calculator.Mode{Name: "unknown"})
+	if err == nil || !strings.Contains(err.Error(), "Unknown mode: unknown") {
+		t.Errorf("expected unknown mode error, got %v", err)
+	}
+}
+
+func TestFormatDecimal(t *testing.T) {
+	tests := []struct {
+		scientific string
+		digits     int
+		expected   string
+	}{
+		{"0.000e+00", 4, "0"},
+		{"1.250e+02", 4, "125"},
+		{"-1.250e+00", 4, "-1.25"},
+		{"1.200e+03", 4, "1200"},
+		{"1.200e+04", 4, "1.2e+4"},
+		{"1.000e-04", 4, "0.0001"},
+		{"1.500e-05", 4, "1.5e-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scientific, func(t *testing.T) {
+			if result := formatDecimal(tt.scientific, tt.digits); result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
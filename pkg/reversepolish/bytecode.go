@@ -0,0 +1,242 @@
+package reversepolish
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+type opcode uint8
+
+const (
+	opConstant opcode = iota // push constant of index arg
+	opLoad                   // push value of variable of index arg
+	opAdd
+	opSubtract
+	opMultiply
+	opDivide
+	opPower
+	opSquare // x ^ 2 calculated as x * x
+	opNegate
+	opCall // replace top of the stack with result of function of index arg
+)
+
+// stackBufferSize is the size of stack and variables buffers allocated on goroutine stack,
+// bigger programs allocate their buffers on every evaluation
+const stackBufferSize = 32
+
+type instruction struct {
+	op  opcode
+	arg int
+}
+
+// bytecode is an operation compiled to instructions of stack machine, it is never modified after compilation
+type bytecode struct {
+	code      []instruction
+	constants []float64
+	functions []func(float64) float64
+	variables []string
+	stackSize int
+}
+
+var binaryOpcodes = map[lexer.ItemType]opcode{
+	lexer.Addition:       opAdd,
+	lexer.Subtraction:    opSubtract,
+	lexer.Multiplication: opMultiply,
+	lexer.Division:       opDivide,
+	lexer.Exponent:       opPower,
+}
+
+// ParseBytecode parses infix operation and compiles it to bytecode, it can be used instead of ParseInfix
+// when the same operation is calculated many times
+func ParseBytecode(ctx context.Context, input string) (calculator.OperationInterface, error) {
+	operation, err := ParseInfix(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompileBytecode(operation)
+}
+
+// CompileBytecode compiles operation parsed by this package to bytecode, returned operation is a calculator.Program too
+func CompileBytecode(operation calculator.OperationInterface) (calculator.OperationInterface, error) {
+	o, ok := operation.(*rpnOperation)
+	if !ok {
+		return nil, errors.NewCalcError(fmt.Sprintf("unsupported operation type: %T", operation))
+	}
+
+	return compileItems(o.items)
+}
+
+func compileItems(items []lexer.Item) (*bytecode, error) {
+	b := &bytecode{}
+	slots := map[string]int{}
+	for _, i := range items {
+		if _, ok := slots[i.GetString()]; isVariable(i) && !ok {
+			slots[i.GetString()] = 0
+			b.variables = append(b.variables, i.GetString())
+		}
+	}
+	sort.Strings(b.variables)
+	for k, name := range b.variables {
+		slots[name] = k
+	}
+
+	depth := 0
+
+	for _, i := range items {
+		if depth < getArity(i) {
+			return nil, errors.NewParsingError("not enough operands on stack")
+		}
+
+		switch {
+		case isNumber(i):
+			b.constants = append(b.constants, getNumericValue(i))
+			b.code = append(b.code, instruction{opConstant, len(b.constants) - 1})
+			depth++
+		case isVariable(i):
+			b.code = append(b.code, instruction{opLoad, slots[i.GetString()]})
+			depth++
+		case isNegation(i):
+			b.code = append(b.code, instruction{opNegate, 0})
+			b.fold(1)
+		case isFunction(i):
+			f, ok := functions[i.GetString()]
+			if !ok {
+				return nil, errors.NewParsingError(fmt.Sprintf("unknown function: %s", i.GetString()))
+			}
+			b.functions = append(b.functions, f)
+			b.code = append(b.code, instruction{opCall, len(b.functions) - 1})
+			b.fold(1)
+		case isMathOperator(i):
+			b.code = append(b.code, instruction{binaryOpcodes[i.GetType()], 0})
+			b.fold(2)
+			b.square()
+			depth--
+		default:
+			return nil, errors.NewParsingError(fmt.Sprintf("invalid item in the RPN operation: %s", i.GetString()))
+		}
+
+		if depth > b.stackSize {
+			b.stackSize = depth
+		}
+	}
+
+	if depth != 1 {
+		return nil, errors.NewParsingError("operation has to consist of exactly one expression")
+	}
+
+	return b, nil
+}
+
+// fold replaces the last instruction with its result if all of its operands are constants
+func (b *bytecode) fold(operands int) {
+	n := len(b.code)
+	if n <= operands {
+		return
+	}
+
+	for _, in := range b.code[n-1-operands : n-1] {
+		if in.op != opConstant {
+			return
+		}
+	}
+
+	folded := &bytecode{code: b.code[n-1-operands:], constants: b.constants, functions: b.functions}
+	value := folded.run(nil)
+
+	if b.code[n-1].op == opCall {
+		b.functions = b.functions[:len(b.functions)-1]
+	}
+	b.constants = append(b.constants[:len(b.constants)-operands], value)
+	b.code = append(b.code[:n-1-operands], instruction{opConstant, len(b.constants) - 1})
+}
+
+// square replaces raising to the power of constant 2 with multiplication
+func (b *bytecode) square() {
+	n := len(b.code)
+	if n < 2 || b.code[n-1].op != opPower || b.code[n-2].op != opConstant || b.constants[b.code[n-2].arg] != 2 {
+		return
+	}
+
+	b.constants = b.constants[:len(b.constants)-1]
+	b.code = append(b.code[:n-2], instruction{opSquare, 0})
+}
+
+// Calculate runs the program, operations with variables have to be evaluated with Eval
+func (b *bytecode) Calculate(ctx context.Context) (float64, error) {
+	return b.Eval(ctx, nil)
+}
+
+// Eval runs the program with variables replaced by their values from bindings
+func (b *bytecode) Eval(_ context.Context, bindings map[string]float64) (float64, error) {
+	var buffer [stackBufferSize]float64
+	values := buffer[:]
+	if len(b.variables) > len(buffer) {
+		values = make([]float64, len(b.variables))
+	}
+
+	for k, name := range b.variables {
+		value, ok := bindings[name]
+		if !ok {
+			return 0.0, errors.NewCalculationError(fmt.Sprintf("unbound variable: %s", name))
+		}
+		values[k] = value
+	}
+
+	return b.run(values), nil
+}
+
+// Variables returns sorted names of free variables
+func (b *bytecode) Variables() []string {
+	return append([]string{}, b.variables...)
+}
+
+// run executes instructions with values of variables in the order of Variables
+func (b *bytecode) run(values []float64) float64 {
+	var buffer [stackBufferSize]float64
+	stack := buffer[:]
+	if b.stackSize > len(buffer) {
+		stack = make([]float64, b.stackSize)
+	}
+
+	sp := 0
+	for _, in := range b.code {
+		switch in.op {
+		case opConstant:
+			stack[sp] = b.constants[in.arg]
+			sp++
+		case opLoad:
+			stack[sp] = values[in.arg]
+			sp++
+		case opAdd:
+			sp--
+			stack[sp-1] += stack[sp]
+		case opSubtract:
+			sp--
+			stack[sp-1] -= stack[sp]
+		case opMultiply:
+			sp--
+			stack[sp-1] *= stack[sp]
+		case opDivide:
+			sp--
+			stack[sp-1] /= stack[sp]
+		case opPower:
+			sp--
+			stack[sp-1] = math.Pow(stack[sp-1], stack[sp])
+		case opSquare:
+			stack[sp-1] *= stack[sp-1]
+		case opNegate:
+			stack[sp-1] = -stack[sp-1]
+		case opCall:
+			stack[sp-1] = b.functions[in.arg](stack[sp-1])
+		}
+	}
+
+	return stack[0]
+}
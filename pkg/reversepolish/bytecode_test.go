@@ -0,0 +1,182 @@
+package reversepolish
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+const benchmarkOperation = "sqrt(x ^ 2 + y ^ 2) * (3.5 - -x) / 4 + sin(y) * 2 ^ 3"
+
+func TestParseBytecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		bindings map[string]float64
+	}{
+		{"Arithmetic", "1 + 2 * 3 - 4 / 8", nil},
+		{"Parentheses and exponent", "(1 + 2) ^ 2 ^ 0.5", nil},
+		{"Negation", "-(2 + 3) * -4", nil},
+		{"Functions and constants", "sqrt(16) + sin(pi / 2) + ln(e)", nil},
+		{"Division by zero", "1 / 0", nil},
+		{"Variables", benchmarkOperation, map[string]float64{"x": 3, "y": 4}},
+		{"Squares", "x ^ 2 - (x + 1) ^ 2 + 2 ^ x", map[string]float64{"x": -1.5}},
+		{"Derivative", "diff(x ^ 3, x)", map[string]float64{"x": 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+			expected, err := operation.(*rpnOperation).Eval(context.Background(), tt.bindings)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			compiled, err := ParseBytecode(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+			result, err := compiled.(calculator.Program).Eval(context.Background(), tt.bindings)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			if result != expected && !(math.IsNaN(result) && math.IsNaN(expected)) {
+				t.Errorf("expected result to be %v, got %v", expected, result)
+			}
+		})
+	}
+}
+
+func TestBytecodeEval(t *testing.T) {
+	operation, err := ParseBytecode(context.Background(), "a * x ^ 2 + b * x")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+	program := operation.(calculator.Program)
+
+	if expected := []string{"a", "b", "x"}; !cmp.Equal(expected, program.Variables()) {
+		t.Errorf("expected variables to be %v, got %v", expected, program.Variables())
+	}
+
+	tests := []struct {
+		name          string
+		bindings      map[string]float64
+		expected      float64
+		expectedError error
+	}{
+		{"All variables bound", map[string]float64{"a": 1, "b": 2, "x": 3}, 15, nil},
+		{"Unbound variable", map[string]float64{"a": 1, "x": 3}, 0, errors.NewCalculationError("unbound variable: b")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := program.Eval(context.Background(), tt.bindings)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("expected result to be %v, got %v", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := operation.Calculate(context.Background()); err == nil || !strings.Contains(err.Error(), "unbound variable: a") {
+		t.Errorf("expected unbound variable error, got %v", err)
+	}
+}
+
+func TestCompileBytecodeErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		operation     calculator.OperationInterface
+		expectedError error
+	}{
+		{
+			"Unsupported operation",
+			&calculator.MockOperationInterface{},
+			errors.NewCalcError("unsupported operation type: *calculator.MockOperationInterface"),
+		},
+		{
+			"Missing operand",
+			&rpnOperation{[]lexer.Item{lexer.NewItem(lexer.Number, "1"), lexer.NewItem(lexer.Addition, "+")}},
+			errors.NewParsingError("not enough operands on stack"),
+		},
+		{
+			"Too many operands",
+			&rpnOperation{[]lexer.Item{lexer.NewItem(lexer.Number, "1"), lexer.NewItem(lexer.Number, "2")}},
+			errors.NewParsingError("operation has to consist of exactly one expression"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileBytecode(tt.operation)
+
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Errorf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestBytecodeAllocations(t *testing.T) {
+	operation, err := ParseBytecode(context.Background(), benchmarkOperation)
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+	program := operation.(calculator.Program)
+	bindings := map[string]float64{"x": 3, "y": 4}
+
+	allocations := testing.AllocsPerRun(100, func() {
+		program.Eval(context.Background(), bindings)
+	})
+
+	if allocations != 0 {
+		t.Errorf("expected evaluation not to allocate, got %v allocations", allocations)
+	}
+}
+
+func BenchmarkRPNEval(b *testing.B) {
+	operation, err := ParseInfix(context.Background(), benchmarkOperation)
+	if err != nil {
+		b.Fatal(err)
+	}
+	program := operation.(calculator.Program)
+	bindings := map[string]float64{"x": 3, "y": 4}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		program.Eval(context.Background(), bindings)
+	}
+}
+
+func BenchmarkBytecodeEval(b *testing.B) {
+	operation, err := ParseBytecode(context.Background(), benchmarkOperation)
+	if err != nil {
+		b.Fatal(err)
+	}
+	program := operation.(calculator.Program)
+	bindings := map[string]float64{"x": 3, "y": 4}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		program.Eval(context.Background(), bindings)
+	}
+}
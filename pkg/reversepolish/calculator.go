@@ -3,7 +3,10 @@ package reversepolish
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
 	"github.com/mateuszkrasucki/calculator/pkg/errors"
 	"github.com/mateuszkrasucki/calculator/pkg/lexer"
 	"github.com/mateuszkrasucki/calculator/pkg/simplecalculator"
@@ -17,11 +20,73 @@ type numericStack struct {
 	stack []float64
 }
 
+// stepObserver is notified about every step of calculation
+type stepObserver func(calculator.Step)
+
 func (o rpnOperation) Calculate(ctx context.Context) (float64, error) {
+	return o.calculate(ctx, nil, nil)
+}
+
+// Eval calculates operation with variables replaced by their values from bindings
+func (o rpnOperation) Eval(ctx context.Context, bindings map[string]float64) (float64, error) {
+	return o.calculate(ctx, bindings, nil)
+}
+
+// NonFinite returns innermost sub-expression of operation with result which is not a finite number,
+// it is found by building operation tree along with the calculation steps
+func (o rpnOperation) NonFinite(ctx context.Context) (string, error) {
+	stack := []*node{}
+	subexpression := ""
+	next := 0
+
+	_, err := o.calculate(ctx, nil, func(step calculator.Step) {
+		i := o.items[next]
+		next++
+
+		arity := getArity(i)
+		n := &node{item: i, children: append([]*node{}, stack[len(stack)-arity:]...)}
+		stack = append(stack[:len(stack)-arity], n)
+
+		if subexpression == "" && (math.IsInf(step.Result, 0) || math.IsNaN(step.Result)) {
+			subexpression = n.format()
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return subexpression, nil
+}
+
+// Variables returns sorted names of free variables used in operation
+func (o rpnOperation) Variables() []string {
+	names := map[string]bool{}
+	for _, i := range o.items {
+		if isVariable(i) {
+			names[i.GetString()] = true
+		}
+	}
+
+	variables := []string{}
+	for name := range names {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+
+	return variables
+}
+
+func (o rpnOperation) calculate(ctx context.Context, bindings map[string]float64, observe stepObserver) (float64, error) {
 	stack := numericStack{[]float64{}}
 
 	for _, i := range o.items {
 		switch {
+		case isIntervalOperator(i):
+			return 0.0, errors.NewCalculationError("intervals can be calculated only in interval mode")
+		case isConversion(i):
+			return 0.0, errors.NewCalculationError("units can be converted only in units mode")
+		case isTimeLiteral(i):
+			return 0.0, errors.NewCalculationError("dates, durations and time zones can be calculated only in time mode")
 		case isMathOperator(i):
 			if stack.length() < 2 {
 				return 0.0, errors.NewCalculationError("not enough operands on stack")
@@ -36,9 +101,40 @@ func (o rpnOperation) Calculate(ctx context.Context) (float64, error) {
 			}
 
 			stack.push(r)
+			stack.observe(observe, i, "calculate", r, operand1, operand2)
+		case isNegation(i):
+			if stack.length() < 1 {
+				return 0.0, errors.NewCalculationError("not enough operands on stack")
+			}
+
+			operand := stack.pop()
+			stack.push(-operand)
+			stack.observe(observe, i, "negate", -operand, operand)
+		case isFunction(i):
+			if stack.length() < 1 {
+				return 0.0, errors.NewCalculationError("not enough operands on stack")
+			}
+
+			f, ok := functions[i.GetString()]
+			if !ok {
+				return 0.0, errors.NewCalculationError(fmt.Sprintf("unknown function: %s", i.GetString()))
+			}
+
+			operand := stack.pop()
+			r := f(operand)
+			stack.push(r)
+			stack.observe(observe, i, "call", r, operand)
 		case isNumber(i):
 			r := i.(numericItem)
 			stack.push(r.GetValue())
+			stack.observe(observe, i, "push", r.GetValue())
+		case isVariable(i):
+			value, ok := bindings[i.GetString()]
+			if !ok {
+				return 0.0, errors.NewCalculationError(fmt.Sprintf("unbound variable: %s", i.GetString()))
+			}
+			stack.push(value)
+			stack.observe(observe, i, "push", value)
 		default:
 			return 0.0, errors.NewCalculationError(fmt.Sprintf("invalid item in the RPN operation: %s", i.GetString()))
 		}
@@ -68,3 +164,18 @@ func (s *numericStack) pop() float64 {
 func (s *numericStack) push(i float64) {
 	s.stack = append(s.stack, i)
 }
+
+// observe notifies observer, if any, about the step and current state of the stack
+func (s *numericStack) observe(observe stepObserver, i lexer.Item, action string, result float64, operands ...float64) {
+	if observe == nil {
+		return
+	}
+
+	observe(calculator.Step{
+		Item:     i.GetString(),
+		Action:   action,
+		Operands: operands,
+		Result:   result,
+		Stack:    append([]float64{}, s.stack...),
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	//"github.com/google/go-cmp/cmp"
 
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
 	"github.com/mateuszkrasucki/calculator/pkg/errors"
 	"github.com/mateuszkrasucki/calculator/pkg/lexer"
 )
@@ -42,6 +43,42 @@ func TestReversePolishCalculate(t *testing.T) {
 			5.0,
 			nil,
 		},
+		{
+			"Success negation and function",
+			rpnOperation{
+				[]lexer.Item{
+					numericItem{"16", 16.0},
+					lexer.NewItem(lexer.Function, "sqrt"),
+					lexer.NewItem(lexer.Negation, "-"),
+					numericItem{"1", 1.0},
+					lexer.NewItem(lexer.Subtraction, "-"),
+				},
+			},
+			-5.0,
+			nil,
+		},
+		{
+			"Error unbound variable",
+			rpnOperation{
+				[]lexer.Item{
+					numericItem{"1", 1.0},
+					lexer.NewItem(lexer.Variable, "x"),
+					lexer.NewItem(lexer.Addition, "+"),
+				},
+			},
+			0.0,
+			errors.NewCalculationError("unbound variable: x"),
+		},
+		{
+			"Error no operands on stack for function",
+			rpnOperation{
+				[]lexer.Item{
+					lexer.NewItem(lexer.Function, "sqrt"),
+				},
+			},
+			0.0,
+			errors.NewCalculationError("not enough operands on stack"),
+		},
 		{
 			"Error no operands on stack",
 			rpnOperation{
@@ -100,3 +137,37 @@ func TestReversePolishCalculate(t *testing.T) {
 		})
 	}
 }
+
+func TestNonFinite(t *testing.T) {
+	tests := []struct {
+		operation             string
+		expectedSubexpression string
+	}{
+		{"2 * (1 / 0) + 3", "1 / 0"},
+		{"(0 / 0) * 2", "0 / 0"},
+		{"-(1 / (2 - 2))", "1 / (2 - 2)"},
+		{"1 + sqrt(-1)", "sqrt(-1)"},
+		{"log(0) - 1", "log(0)"},
+		{"0 ^ -1", "0 ^ -1"},
+		{"1 / (1 / 0)", "1 / 0"},
+		{"2 + 2", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.operation)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			subexpression, err := operation.(calculator.NonFiniteOperation).NonFinite(context.Background())
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			if subexpression != tt.expectedSubexpression {
+				t.Errorf("expected sub-expression to be %v, got %v", tt.expectedSubexpression, subexpression)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package reversepolish
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// ColumnBatchSize is the number of rows every instruction is executed for at once, stack of a batch
+// should fit in the CPU cache, so streamed rows are best evaluated in batches of this size
+const ColumnBatchSize = 1024
+
+// CompileColumns parses infix operation and compiles it for evaluation over columns
+func CompileColumns(ctx context.Context, input string) (calculator.ColumnProgram, error) {
+	operation, err := ParseInfix(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return compileItems(operation.(*rpnOperation).items)
+}
+
+// EvalColumns runs the program for every row of columns, all columns have to be of the same length,
+// rows with results which are not finite numbers are returned as row errors
+func (b *bytecode) EvalColumns(ctx context.Context, columns map[string][]float64) ([]float64, []calculator.RowError, error) {
+	rows, err := countRows(columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([][]float64, len(b.variables))
+	for k, name := range b.variables {
+		column, ok := columns[name]
+		if !ok {
+			return nil, nil, errors.NewInputError(fmt.Sprintf("Missing column: %s", name))
+		}
+		values[k] = column
+	}
+
+	results := make([]float64, rows)
+	buffer := make([]float64, b.stackSize*ColumnBatchSize)
+	stack := make([][]float64, b.stackSize)
+	for k := range stack {
+		stack[k] = buffer[k*ColumnBatchSize : (k+1)*ColumnBatchSize]
+	}
+
+	for start := 0; start < rows; start += ColumnBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, errors.NewCalculationErrorWrap(err, "Evaluation of columns interrupted")
+		}
+
+		end := start + ColumnBatchSize
+		if end > rows {
+			end = rows
+		}
+		b.runBatch(values, start, end, stack, results[start:end])
+	}
+
+	rowErrors := []calculator.RowError{}
+	for k, r := range results {
+		if math.IsNaN(r) || math.IsInf(r, 0) {
+			rowErrors = append(rowErrors, calculator.RowError{
+				Row: k,
+				Err: errors.NewCalculationError(fmt.Sprintf("result is not a finite number: %v", r)),
+			})
+		}
+	}
+
+	return results, rowErrors, nil
+}
+
+// countRows returns length of columns, or error if they differ
+func countRows(columns map[string][]float64) (int, error) {
+	names := []string{}
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := 0
+	for k, name := range names {
+		if k > 0 && len(columns[name]) != rows {
+			return 0, errors.NewInputError(fmt.Sprintf("Column %s has %d rows, column %s has %d", name, len(columns[name]), names[0], rows))
+		}
+		rows = len(columns[name])
+	}
+
+	return rows, nil
+}
+
+// runBatch executes every instruction for rows from start to end of values, stack holds one column of batch per level
+func (b *bytecode) runBatch(values [][]float64, start int, end int, stack [][]float64, results []float64) {
+	n := end - start
+	sp := 0
+
+	for _, in := range b.code {
+		switch in.op {
+		case opConstant:
+			x, c := stack[sp][:n], b.constants[in.arg]
+			for k := range x {
+				x[k] = c
+			}
+			sp++
+		case opLoad:
+			copy(stack[sp][:n], values[in.arg][start:end])
+			sp++
+		case opNegate:
+			x := stack[sp-1][:n]
+			for k := range x {
+				x[k] = -x[k]
+			}
+		case opSquare:
+			x := stack[sp-1][:n]
+			for k := range x {
+				x[k] *= x[k]
+			}
+		case opCall:
+			x, f := stack[sp-1][:n], b.functions[in.arg]
+			for k := range x {
+				x[k] = f(x[k])
+			}
+		default:
+			sp--
+			runBinary(in.op, stack[sp-1][:n], stack[sp][:n])
+		}
+	}
+
+	copy(results, stack[0][:n])
+}
+
+// runBinary replaces values of x with results of binary operation on x and y
+func runBinary(op opcode, x []float64, y []float64) {
+	switch op {
+	case opAdd:
+		for k := range x {
+			x[k] += y[k]
+		}
+	case opSubtract:
+		for k := range x {
+			x[k] -= y[k]
+		}
+	case opMultiply:
+		for k := range x {
+			x[k] *= y[k]
+		}
+	case opDivide:
+		for k := range x {
+			x[k] /= y[k]
+		}
+	case opPower:
+		for k := range x {
+			x[k] = math.Pow(x[k], y[k])
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package reversepolish
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestEvalColumns(t *testing.T) {
+	program, err := CompileColumns(context.Background(), "a * x ^ 2 + b / x")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		columns       map[string][]float64
+		expected      []float64
+		expectedRows  []int
+		expectedError error
+	}{
+		{
+			"Rows",
+			map[string][]float64{"a": {1, 2, 0}, "b": {4, 0, 1}, "x": {2, 3, 0.5}},
+			[]float64{6, 18, 2},
+			[]int{},
+			nil,
+		},
+		{
+			"Extra columns are ignored",
+			map[string][]float64{"a": {1}, "b": {2}, "x": {1}, "y": {5}},
+			[]float64{3},
+			[]int{},
+			nil,
+		},
+		{
+			"Rows with results which are not finite",
+			map[string][]float64{"a": {1, 1, 0}, "b": {1, 1, 0}, "x": {0, 1, 0}},
+			[]float64{math.Inf(1), 2, math.NaN()},
+			[]int{0, 2},
+			nil,
+		},
+		{
+			"No rows",
+			map[string][]float64{"a": {}, "b": {}, "x": {}},
+			[]float64{},
+			[]int{},
+			nil,
+		},
+		{
+			"Missing column",
+			map[string][]float64{"a": {1}, "x": {1}},
+			nil,
+			nil,
+			errors.NewInputError("Missing column: b"),
+		},
+		{
+			"Columns of different lengths",
+			map[string][]float64{"a": {1, 2}, "b": {1}, "x": {1, 2}},
+			nil,
+			nil,
+			errors.NewInputError("Column b has 1 rows, column a has 2"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, rowErrors, err := program.EvalColumns(context.Background(), tt.columns)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if !cmp.Equal(tt.expected, results, cmp.Comparer(func(x, y float64) bool {
+				return x == y || math.IsNaN(x) && math.IsNaN(y)
+			})) {
+				t.Errorf("expected results to be %v, got %v", tt.expected, results)
+			}
+
+			rows := []int{}
+			for _, e := range rowErrors {
+				rows = append(rows, e.Row)
+			}
+			if tt.expectedError == nil && !cmp.Equal(tt.expectedRows, rows) {
+				t.Errorf("expected errors in rows %v, got %v", tt.expectedRows, rows)
+			}
+		})
+	}
+}
+
+func TestEvalColumnsBatches(t *testing.T) {
+	program, err := CompileColumns(context.Background(), benchmarkOperation)
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	columns := benchmarkColumns(2*ColumnBatchSize + 7)
+	results, rowErrors, err := program.EvalColumns(context.Background(), columns)
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	if len(rowErrors) != 0 {
+		t.Errorf("expected no row errors, got %v", rowErrors)
+	}
+
+	for k, r := range results {
+		expected, err := program.Eval(context.Background(), map[string]float64{"x": columns["x"][k], "y": columns["y"][k]})
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %v", err)
+		}
+
+		if r != expected {
+			t.Fatalf("expected result of row %d to be %v, got %v", k, expected, r)
+		}
+	}
+}
+
+func TestEvalColumnsCancelled(t *testing.T) {
+	program, err := CompileColumns(context.Background(), "x + 1")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err = program.EvalColumns(ctx, map[string][]float64{"x": {1, 2}})
+	if err == nil || !strings.Contains(err.Error(), "Evaluation of columns interrupted") {
+		t.Errorf("expected interruption error, got %v", err)
+	}
+}
+
+func benchmarkColumns(rows int) map[string][]float64 {
+	columns := map[string][]float64{"x": make([]float64, rows), "y": make([]float64, rows)}
+	for k := 0; k < rows; k++ {
+		columns["x"][k] = float64(k%100) / 10
+		columns["y"][k] = float64(k % 7)
+	}
+
+	return columns
+}
+
+func BenchmarkEvalRows(b *testing.B) {
+	program, err := CompileColumns(context.Background(), benchmarkOperation)
+	if err != nil {
+		b.Fatal(err)
+	}
+	columns := benchmarkColumns(100000)
+	bindings := map[string]float64{}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for k := range columns["x"] {
+			bindings["x"], bindings["y"] = columns["x"][k], columns["y"][k]
+			program.Eval(context.Background(), bindings)
+		}
+	}
+}
+
+func BenchmarkEvalColumns(b *testing.B) {
+	program, err := CompileColumns(context.Background(), benchmarkOperation)
+	if err != nil {
+		b.Fatal(err)
+	}
+	columns := benchmarkColumns(100000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		program.EvalColumns(context.Background(), columns)
+	}
+}
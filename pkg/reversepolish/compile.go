@@ -0,0 +1,52 @@
+package reversepolish
+
+import (
+	"context"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+)
+
+type compiler struct {
+	lexers Lexers
+}
+
+// NewCompiler returns compiler of operations lexed by provided lexers
+func NewCompiler(lexers Lexers) calculator.Compiler {
+	return compiler{lexers: lexers}
+}
+
+// Compile lexes and parses operation written in notation once, so it can be evaluated many times
+func (c compiler) Compile(ctx context.Context, notation string, input string) (calculator.Program, error) {
+	lex, err := c.lexers.lexer(notation)
+	if err != nil {
+		return nil, err
+	}
+
+	operation, err := ParseItems(ctx, lex(input))
+	if err != nil {
+		return nil, err
+	}
+
+	return program(operation.(*rpnOperation)), nil
+}
+
+// Compile parses infix operation once, returned program is never modified so it can be evaluated concurrently
+func Compile(input string) (calculator.Program, error) {
+	operation, err := ParseInfix(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	return program(operation.(*rpnOperation)), nil
+}
+
+// program compiles operation to bytecode, operations with items not supported by bytecode, i.e. intervals,
+// are evaluated item by item
+func program(operation *rpnOperation) calculator.Program {
+	compiled, err := compileItems(operation.items)
+	if err != nil {
+		return operation
+	}
+
+	return compiled
+}
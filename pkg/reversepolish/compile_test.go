@@ -0,0 +1,99 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+func TestCompile(t *testing.T) {
+	program, err := Compile("a * x ^ 2 + b * x + pi * 0")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	if expected := []string{"a", "b", "x"}; !cmp.Equal(expected, program.Variables()) {
+		t.Errorf("expected variables to be %v, got %v", expected, program.Variables())
+	}
+
+	tests := []struct {
+		name          string
+		bindings      map[string]float64
+		expected      float64
+		expectedError error
+	}{
+		{"All variables bound", map[string]float64{"a": 1, "b": 2, "x": 3}, 15, nil},
+		{"Extra bindings are ignored", map[string]float64{"a": 2, "b": 0, "x": 2, "y": 5}, 8, nil},
+		{"Unbound variable", map[string]float64{"a": 1, "x": 3}, 0, errors.NewCalculationError("unbound variable: b")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := program.Eval(context.Background(), tt.bindings)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("expected result to be %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCompileToBytecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		bytecode bool
+	}{
+		{"Arithmetic and functions", "sqrt(x ^ 2 + y ^ 2) * 2", true},
+		{"Intervals", "[1, 2] * x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := Compile(tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			if _, ok := program.(*bytecode); ok != tt.bytecode {
+				t.Errorf("expected program to be compiled to bytecode: %v, got %T", tt.bytecode, program)
+			}
+		})
+	}
+}
+
+func TestCompileConcurrentEval(t *testing.T) {
+	program, err := NewCompiler(Lexers{calculator.InfixNotation: lexer.Lex}).Compile(context.Background(), "", "2 * x + 1")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for k := 0; k < 50; k++ {
+		wg.Add(1)
+		go func(x float64) {
+			defer wg.Done()
+
+			result, err := program.Eval(context.Background(), map[string]float64{"x": x})
+			if err != nil || result != 2*x+1 {
+				t.Errorf("expected result to be %v, got %v, %v", 2*x+1, result, err)
+			}
+		}(float64(k))
+	}
+	wg.Wait()
+}
@@ -0,0 +1,206 @@
+package reversepolish
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strconv"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// complexFunctions are functions of complex numbers, rounding functions round both parts of numbers
+var complexFunctions = map[string]func(complex128) complex128{
+	"sqrt":  cmplx.Sqrt,
+	"abs":   func(c complex128) complex128 { return complex(cmplx.Abs(c), 0) },
+	"exp":   cmplx.Exp,
+	"ln":    cmplx.Log,
+	"log":   cmplx.Log10,
+	"sin":   cmplx.Sin,
+	"cos":   cmplx.Cos,
+	"tan":   cmplx.Tan,
+	"asin":  cmplx.Asin,
+	"acos":  cmplx.Acos,
+	"atan":  cmplx.Atan,
+	"sinh":  cmplx.Sinh,
+	"cosh":  cmplx.Cosh,
+	"tanh":  cmplx.Tanh,
+	"floor": func(c complex128) complex128 { return complex(math.Floor(real(c)), math.Floor(imag(c))) },
+	"ceil":  func(c complex128) complex128 { return complex(math.Ceil(real(c)), math.Ceil(imag(c))) },
+	"re":    func(c complex128) complex128 { return complex(real(c), 0) },
+	"im":    func(c complex128) complex128 { return complex(imag(c), 0) },
+	"conj":  cmplx.Conj,
+	"arg":   func(c complex128) complex128 { return complex(cmplx.Phase(c), 0) },
+}
+
+// imaginaryUnits are names of the imaginary unit, i is used by mathematicians and j by engineers
+var imaginaryUnits = map[string]bool{"i": true, "j": true}
+
+// complexArithmetic calculates with complex128 numbers, numbers followed by i or j are imaginary, e.g. 4i is 4 * i
+type complexArithmetic struct{}
+
+type complexValue complex128
+
+func newComplexArithmetic(calculator.Mode) (arithmetic, error) {
+	return complexArithmetic{}, nil
+}
+
+// finite returns calculation error if any part of the number is infinite or not a number
+func finite(c complex128, description string) (interface{}, error) {
+	if cmplx.IsInf(c) || cmplx.IsNaN(c) {
+		return nil, errors.NewCalculationError(fmt.Sprintf("result of %s is not a finite number", description))
+	}
+
+	return c, nil
+}
+
+func (a complexArithmetic) number(item lexer.Item) (interface{}, error) {
+	if value, ok := constants[item.GetString()]; ok {
+		return complex(value, 0), nil
+	}
+
+	value, err := strconv.ParseFloat(item.GetString(), 64)
+	if err != nil {
+		return nil, errors.NewParsingErrorWrap(err, fmt.Sprintf("could not parse %s as a number", item.GetString()))
+	}
+
+	return complex(value, 0), nil
+}
+
+func (a complexArithmetic) name(name string) (interface{}, bool) {
+	if imaginaryUnits[name] {
+		return complex(0, 1), true
+	}
+
+	return nil, false
+}
+
+func (a complexArithmetic) operator(operator string, x, y interface{}) (interface{}, error) {
+	c, d := x.(complex128), y.(complex128)
+	description := fmt.Sprintf("%s %s %s", formatComplex(c), operator, formatComplex(d))
+
+	switch operator {
+	case "+":
+		return finite(c+d, description)
+	case "-":
+		return finite(c-d, description)
+	case "*":
+		return finite(c*d, description)
+	case "/":
+		if d == 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+		return finite(divide(c, d), description)
+	case "^":
+		if c == 0 && real(d) < 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+		if n := real(d); imag(d) == 0 && n == math.Trunc(n) && math.Abs(n) <= math.MaxInt32 {
+			return finite(integerComplexPower(c, int64(n)), description)
+		}
+		return finite(cmplx.Pow(c, d), description)
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown operator: %s", operator))
+	}
+}
+
+// divide multiplies dividend by conjugate of divisor, so quotients of Gaussian integers are exact,
+// divisors which squared would overflow are divided by Go division
+func divide(c, d complex128) complex128 {
+	norm := real(d)*real(d) + imag(d)*imag(d)
+	if norm == 0 || math.IsInf(norm, 0) {
+		return c / d
+	}
+
+	product := c * cmplx.Conj(d)
+
+	return complex(real(product)/norm, imag(product)/norm)
+}
+
+// integerComplexPower raises number to integer exponent by repeated squaring, so powers of i are exact
+func integerComplexPower(c complex128, n int64) complex128 {
+	result := complex(1, 0)
+	for e := n; e != 0; e /= 2 {
+		if e%2 != 0 {
+			result *= c
+		}
+		c *= c
+	}
+
+	if n < 0 {
+		return divide(1, result)
+	}
+
+	return result
+}
+
+// negate changes signs of parts of the number, parts equal to zero stay positive, so -4 is not below
+// the branch cut of square root and logarithm
+func (a complexArithmetic) negate(x interface{}) (interface{}, error) {
+	c := x.(complex128)
+
+	return complex(0-real(c), 0-imag(c)), nil
+}
+
+func (a complexArithmetic) function(name string, x interface{}) (interface{}, error) {
+	f, ok := complexFunctions[name]
+	if !ok {
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown function: %s", name))
+	}
+
+	c := x.(complex128)
+	if c == 0 && (name == "ln" || name == "log") {
+		return nil, errors.NewCalculationError("logarithm of zero")
+	}
+
+	return finite(f(c), fmt.Sprintf("%s(%s)", name, formatComplex(c)))
+}
+
+func (a complexArithmetic) value(x interface{}) (calculator.Value, error) {
+	return complexValue(x.(complex128)), nil
+}
+
+// Float64 returns real part of the number
+func (v complexValue) Float64() float64 {
+	return real(v)
+}
+
+func (v complexValue) Complex() complex128 {
+	return complex128(v)
+}
+
+func (v complexValue) String() string {
+	return formatComplex(complex128(v))
+}
+
+// formatComplex writes number as sum of real and imaginary parts, parts equal to zero are omitted, e.g. 3-4i, 2i, -i
+func formatComplex(c complex128) string {
+	re, im := real(c), imag(c)
+	format := func(x float64) string {
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	}
+
+	if im == 0 {
+		return format(re + 0)
+	}
+
+	imaginary := format(im) + "i"
+	switch im {
+	case 1:
+		imaginary = "i"
+	case -1:
+		imaginary = "-i"
+	}
+
+	if re == 0 {
+		return imaginary
+	}
+
+	if im > 0 {
+		return format(re) + "+" + imaginary
+	}
+
+	return format(re) + imaginary
+}
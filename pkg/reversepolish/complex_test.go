@@ -0,0 +1,65 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestCalculateComplex(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      string
+		expectedError error
+	}{
+		{"Square root of negative number", "sqrt(-4)", "2i", nil},
+		{"Complex literal", "3+4i", "3+4i", nil},
+		{"Engineering notation", "3-4j", "3-4i", nil},
+		{"Imaginary unit", "-i", "-i", nil},
+		{"Multiplication", "(1+2i) * (3-i)", "5+5i", nil},
+		{"Division", "(5+5i) / (3-i)", "1+2i", nil},
+		{"Square of imaginary unit", "i ^ 2", "-1", nil},
+		{"Impedance of series circuit", "10 + 1 / (2i * pi * 50 * 0.001)", "10-3.183098861837907i", nil},
+		{"Absolute value", "abs(3+4i)", "5", nil},
+		{"Argument", "arg(2i)", "1.5707963267948966", nil},
+		{"Conjugate", "conj(3+4i)", "3-4i", nil},
+		{"Real and imaginary parts", "re(3+4i) * 10 + im(3+4i)", "34", nil},
+		{"Exponential function", "exp(i * pi / 2)", "6.123233995736757e-17+i", nil},
+		{"Logarithm of negative number", "ln(-1)", "3.141592653589793i", nil},
+		{"Floor", "floor(2.5-1.5i)", "2-2i", nil},
+		{"Division by zero", "1 / (i - i)", "", errors.NewCalculationError("division by zero")},
+		{"Logarithm of zero", "ln(0i)", "", errors.NewCalculationError("logarithm of zero")},
+		{"Infinite result", "exp(1000)", "", errors.NewCalculationError("result of exp(1000) is not a finite number")},
+		{"Unbound variable", "x + i", "", errors.NewCalculationError("unbound variable: x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), calculator.Mode{Name: calculator.ComplexMode})
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if value.String() != tt.expected {
+				t.Errorf("expected result to be %s, got %s", tt.expected, value.String())
+			}
+		})
+	}
+}
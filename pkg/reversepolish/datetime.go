@@ -0,0 +1,682 @@
+package reversepolish
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	// time zones are known also on systems without tz database
+	_ "time/tzdata"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// timeNow returns current time of now and today
+var timeNow = time.Now
+
+// averageMonth is the length of average month of Gregorian calendar, it measures durations of months
+const averageMonth = 2629746 * time.Second
+
+// timeFormats are layouts of dates and timestamps of named time formats, other formats are layouts of package time
+var timeFormats = map[string][2]string{
+	"":        {"2006-01-02", time.RFC3339},
+	"iso":     {"2006-01-02", time.RFC3339},
+	"rfc1123": {"Mon, 02 Jan 2006", time.RFC1123Z},
+}
+
+// unixFormat writes dates and timestamps as numbers of seconds since 1970-01-01 UTC
+const unixFormat = "unix"
+
+// timestampLayouts are layouts of timestamps written in operations, timestamps without offset are in zone of the mode
+var timestampLayouts = []string{"2006-01-02T15:04:05Z07:00", "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// durationComponent matches components of duration literals, e.g. 20m of 3h 20m
+var durationComponent = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|min|w|d|h|m|s)`)
+
+// durationNames maps names of units of durations to units results are written in
+var durationNames = map[string]string{
+	"ms": "ms", "millisecond": "ms", "milliseconds": "ms",
+	"s": "s", "sec": "s", "second": "s", "seconds": "s",
+	"m": "min", "min": "min", "minute": "min", "minutes": "min",
+	"h": "h", "hour": "h", "hours": "h",
+	"d": "days", "day": "days", "days": "days",
+	"w": "weeks", "week": "weeks", "weeks": "weeks",
+	"month": "months", "months": "months",
+	"year": "years", "years": "years",
+	"workday": "workdays", "workdays": "workdays",
+}
+
+// durationUnits are spans of one unit of durations
+var durationUnits = map[string]span{
+	"ms":       {clock: time.Millisecond},
+	"s":        {clock: time.Second},
+	"min":      {clock: time.Minute},
+	"h":        {clock: time.Hour},
+	"days":     {days: 1},
+	"weeks":    {days: 7},
+	"months":   {months: 1},
+	"years":    {months: 12},
+	"workdays": {workdays: 1},
+}
+
+// singularNames are names of units written after amounts of one, e.g. 1 day
+var singularNames = map[string]string{"days": "day", "weeks": "week", "months": "month", "years": "year", "workdays": "workday"}
+
+// timeArithmetic calculates with dates, timestamps and durations, durations are spans of calendar months, days
+// and workdays and of clock time, so a month added to a date keeps day of month, and numbers, e.g. ratios
+// of durations, time zones are only targets of conversions, e.g. now in Asia/Tokyo
+type timeArithmetic struct {
+	location *time.Location
+	format   string
+}
+
+// instant is a timestamp or a date, dates are midnights in UTC of days without time of day
+type instant struct {
+	t    time.Time
+	date bool
+}
+
+// span is a duration, unit is the unit the span is written in after conversion, e.g. 66 days, and differences
+// of dates keep the dates, so they can be converted to workdays
+type span struct {
+	months   int
+	days     int
+	workdays int
+	clock    time.Duration
+	unit     string
+	between  *[2]time.Time
+}
+
+// zone is a time zone timestamps are converted to
+type zone struct {
+	location *time.Location
+}
+
+type timeValue struct {
+	value  interface{}
+	format string
+}
+
+func newTimeArithmetic(mode calculator.Mode) (arithmetic, error) {
+	location := time.Local
+	if mode.Zone != "" {
+		var err error
+		if location, err = time.LoadLocation(mode.Zone); err != nil {
+			return nil, errors.NewInputErrorWrap(err, fmt.Sprintf("Unknown time zone: %s", mode.Zone))
+		}
+	}
+
+	if _, ok := timeFormats[mode.TimeFormat]; !ok && mode.TimeFormat != unixFormat && !strings.ContainsAny(mode.TimeFormat, "0123456789") {
+		return nil, errors.NewInputError(fmt.Sprintf("Unknown time format: %s", mode.TimeFormat))
+	}
+
+	return timeArithmetic{location: location, format: mode.TimeFormat}, nil
+}
+
+func (a timeArithmetic) number(item lexer.Item) (interface{}, error) {
+	if value, ok := constants[item.GetString()]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.ParseFloat(item.GetString(), 64)
+	if err != nil {
+		return nil, errors.NewParsingErrorWrap(err, fmt.Sprintf("could not parse %s as a number", item.GetString()))
+	}
+
+	return value, nil
+}
+
+// literal returns date, duration or time zone written in operation
+func (a timeArithmetic) literal(item lexer.Item) (interface{}, error) {
+	switch literal := item.GetString(); item.GetType() {
+	case lexer.Date:
+		return a.date(literal)
+	case lexer.Duration:
+		return parseSpan(literal), nil
+	default:
+		location, err := time.LoadLocation(literal)
+		if err != nil {
+			return nil, errors.NewCalculationErrorWrap(err, fmt.Sprintf("unknown time zone: %s", literal))
+		}
+		return zone{location}, nil
+	}
+}
+
+// date parses date or timestamp, timestamps without offset are in zone of the mode
+func (a timeArithmetic) date(literal string) (interface{}, error) {
+	if !strings.Contains(literal, "T") {
+		t, err := time.Parse("2006-01-02", literal)
+		if err != nil {
+			return nil, errors.NewParsingErrorWrap(err, fmt.Sprintf("could not parse %s as a date", literal))
+		}
+		return instant{t: t, date: true}, nil
+	}
+
+	var err error
+	for _, layout := range timestampLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, literal, a.location); err == nil {
+			return instant{t: t}, nil
+		}
+	}
+
+	return nil, errors.NewParsingErrorWrap(err, fmt.Sprintf("could not parse %s as a timestamp", literal))
+}
+
+// parseSpan returns duration of components of literal, fractions of days and weeks are clock time
+func parseSpan(literal string) span {
+	s := span{}
+	for _, component := range durationComponent.FindAllStringSubmatch(literal, -1) {
+		value, _ := strconv.ParseFloat(component[1], 64)
+
+		switch unit := durationNames[component[2]]; {
+		case unit == "days" || unit == "weeks":
+			days := value * float64(durationUnits[unit].days)
+			if days == math.Trunc(days) {
+				s.days += int(days)
+			} else {
+				s.clock += time.Duration(days * float64(24*time.Hour))
+			}
+		default:
+			s.clock += time.Duration(value * float64(durationUnits[unit].clock))
+		}
+	}
+
+	return s
+}
+
+func (a timeArithmetic) name(name string) (interface{}, bool) {
+	today := func(days int) instant {
+		year, month, day := timeNow().In(a.location).Date()
+		return instant{t: time.Date(year, month, day+days, 0, 0, 0, 0, time.UTC), date: true}
+	}
+
+	switch name {
+	case "now":
+		return instant{t: timeNow().In(a.location)}, true
+	case "today":
+		return today(0), true
+	case "tomorrow":
+		return today(1), true
+	case "yesterday":
+		return today(-1), true
+	case "UTC":
+		return zone{time.UTC}, true
+	}
+
+	unit, ok := durationNames[name]
+	if !ok {
+		return nil, false
+	}
+
+	s := durationUnits[unit]
+	s.unit = unit
+
+	return s, true
+}
+
+// moment returns time of instant, dates are midnights in zone of the mode, so they can be compared with timestamps
+func (a timeArithmetic) moment(i instant) time.Time {
+	if !i.date {
+		return i.t
+	}
+
+	year, month, day := i.t.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, a.location)
+}
+
+// kind returns name of kind of value used in errors
+func kind(x interface{}) string {
+	switch x := x.(type) {
+	case instant:
+		if x.date {
+			return "date"
+		}
+		return "timestamp"
+	case span:
+		return "duration"
+	case zone:
+		return "time zone"
+	default:
+		return "number"
+	}
+}
+
+func (a timeArithmetic) operator(operator string, x, y interface{}) (interface{}, error) {
+	switch f := x.(type) {
+	case float64:
+		switch g := y.(type) {
+		case float64:
+			return numberOperator(operator, f, g)
+		case span:
+			if operator == "*" {
+				return g.scale(f)
+			}
+		}
+	case instant:
+		switch g := y.(type) {
+		case span:
+			if operator == "+" {
+				return a.add(f, g), nil
+			}
+			if operator == "-" {
+				return a.add(f, g.negated()), nil
+			}
+		case instant:
+			if operator == "-" {
+				return a.difference(f, g), nil
+			}
+		}
+	case span:
+		switch g := y.(type) {
+		case span:
+			return f.operator(operator, g)
+		case instant:
+			if operator == "+" {
+				return a.add(g, f), nil
+			}
+		case float64:
+			if operator == "*" {
+				return f.scale(g)
+			}
+			if operator == "/" && g == 0 {
+				return nil, errors.NewCalculationError("division by zero")
+			}
+			if operator == "/" {
+				return f.scale(1 / g)
+			}
+		}
+	}
+
+	return nil, errors.NewCalculationError(fmt.Sprintf("cannot calculate %s %s %s", kind(x), operator, kind(y)))
+}
+
+func numberOperator(operator string, f, g float64) (interface{}, error) {
+	switch operator {
+	case "+":
+		return f + g, nil
+	case "-":
+		return f - g, nil
+	case "*":
+		return f * g, nil
+	case "/":
+		if g == 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+		return f / g, nil
+	case "^":
+		return math.Pow(f, g), nil
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown operator: %s", operator))
+	}
+}
+
+// add adds months first, then days, workdays and clock time, dates with clock time added are timestamps
+func (a timeArithmetic) add(i instant, s span) instant {
+	t := i.t
+	if i.date && s.clock != 0 {
+		t = a.moment(i)
+	}
+
+	t = addWorkdays(addMonths(t, s.months).AddDate(0, 0, s.days), s.workdays)
+
+	return instant{t: t.Add(s.clock), date: i.date && s.clock == 0}
+}
+
+// addMonths adds months keeping day of month, days which do not exist in the month are its last day,
+// e.g. 2026-01-31 + 1 month is 2026-02-28
+func addMonths(t time.Time, months int) time.Time {
+	if months == 0 {
+		return t
+	}
+
+	year, month, day := t.Date()
+	last := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	return time.Date(year, month+time.Month(months), min(day, last), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// addWorkdays adds days from Monday to Friday, e.g. Friday + 1 workday is Monday
+func addWorkdays(t time.Time, workdays int) time.Time {
+	step := 1
+	if workdays < 0 {
+		step, workdays = -1, -workdays
+	}
+
+	for workdays > 0 {
+		t = t.AddDate(0, 0, step)
+		if isWorkday(t) {
+			workdays--
+		}
+	}
+
+	return t
+}
+
+func isWorkday(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// countWorkdays returns number of workdays after from until to, it is negative if to is before from
+func countWorkdays(from, to time.Time) int {
+	if to.Before(from) {
+		return -countWorkdays(to, from)
+	}
+
+	count := 0
+	for t := from.AddDate(0, 0, 1); !t.After(to); t = t.AddDate(0, 0, 1) {
+		if isWorkday(t) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// difference returns days between dates and clock time between timestamps
+func (a timeArithmetic) difference(f, g instant) span {
+	if f.date && g.date {
+		return span{days: int(math.Round(f.t.Sub(g.t).Hours() / 24)), between: &[2]time.Time{g.t, f.t}}
+	}
+
+	return span{clock: a.moment(f).Sub(a.moment(g))}
+}
+
+func (s span) operator(operator string, g span) (interface{}, error) {
+	switch operator {
+	case "+":
+		return span{months: s.months + g.months, days: s.days + g.days, workdays: s.workdays + g.workdays, clock: s.clock + g.clock}, nil
+	case "-":
+		return s.operator("+", g.negated())
+	case "/":
+		f, err := s.seconds()
+		if err != nil {
+			return nil, err
+		}
+		d, err := g.seconds()
+		if err != nil {
+			return nil, err
+		}
+		if d == 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+		return f / d, nil
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("cannot calculate duration %s duration", operator))
+	}
+}
+
+func (s span) negated() span {
+	return span{months: -s.months, days: -s.days, workdays: -s.workdays, clock: -s.clock}
+}
+
+// scale multiplies span, spans of months and workdays are multiplied only by integers and fractions of days
+// are clock time
+func (s span) scale(f float64) (interface{}, error) {
+	if f == math.Trunc(f) {
+		n := int(f)
+		return span{months: s.months * n, days: s.days * n, workdays: s.workdays * n, clock: time.Duration(float64(s.clock) * f)}, nil
+	}
+
+	if s.months != 0 || s.workdays != 0 {
+		return nil, errors.NewCalculationError("durations of months and workdays can be multiplied only by integers")
+	}
+
+	return span{clock: time.Duration(float64(time.Duration(s.days)*24*time.Hour+s.clock) * f)}, nil
+}
+
+// seconds returns length of span, months are average months and workdays have no fixed length
+func (s span) seconds() (float64, error) {
+	if s.workdays != 0 {
+		return 0, errors.NewCalculationError("durations of workdays have no fixed length")
+	}
+
+	return (time.Duration(s.months)*averageMonth + time.Duration(s.days)*24*time.Hour + s.clock).Seconds(), nil
+}
+
+// amount returns span written in unit, months and workdays can be converted only to themselves,
+// differences of dates can be converted to workdays
+func (s span) amount(unit string) (float64, error) {
+	switch {
+	case unit == "workdays" && s.between != nil:
+		return float64(countWorkdays(s.between[0], s.between[1])), nil
+	case unit == "workdays" && s.months == 0 && s.days == 0 && s.clock == 0:
+		return float64(s.workdays), nil
+	case unit == "workdays":
+		return 0, errors.NewCalculationError("only differences of dates and workdays can be converted to workdays")
+	case unit == "months" || unit == "years":
+		if s.days != 0 || s.workdays != 0 || s.clock != 0 {
+			return 0, errors.NewCalculationError(fmt.Sprintf("only months and years can be converted to %s", unit))
+		}
+		return float64(s.months) / float64(durationUnits[unit].months), nil
+	default:
+		if s.months != 0 || s.workdays != 0 {
+			return 0, errors.NewCalculationError(fmt.Sprintf("months and workdays cannot be converted to %s, they have no fixed length", unit))
+		}
+		length := time.Duration(durationUnits[unit].days)*24*time.Hour + durationUnits[unit].clock
+		return float64(time.Duration(s.days)*24*time.Hour+s.clock) / float64(length), nil
+	}
+}
+
+// isUnit returns true for spans of one unit of durations, e.g. days
+func (s span) isUnit() bool {
+	u, ok := durationUnits[s.unit]
+
+	return ok && s.between == nil && s.months == u.months && s.days == u.days && s.workdays == u.workdays && s.clock == u.clock
+}
+
+// String writes span in its unit or as calendar components followed by clock time, e.g. 1 month 3 days 2h 30m
+func (s span) String() string {
+	if s.unit != "" {
+		amount, _ := s.amount(s.unit)
+		written := strconv.FormatFloat(amount, 'g', 15, 64)
+		if singular, ok := singularNames[s.unit]; ok && amount == 1 {
+			return written + " " + singular
+		}
+		return written + " " + s.unit
+	}
+
+	parts := []string{}
+	for _, component := range []struct {
+		amount int
+		unit   string
+	}{{s.months / 12, "years"}, {s.months % 12, "months"}, {s.days, "days"}, {s.workdays, "workdays"}} {
+		if component.amount == 1 {
+			parts = append(parts, "1 "+singularNames[component.unit])
+		} else if component.amount != 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", component.amount, component.unit))
+		}
+	}
+
+	if s.clock != 0 || len(parts) == 0 {
+		parts = append(parts, formatClock(s.clock))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// formatClock writes clock time in hours, minutes and seconds, e.g. 13h 20m or 1.5s
+func formatClock(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	parts := []string{}
+	if hours := d / time.Hour; hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes := d % time.Hour / time.Minute; minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	if seconds := d % time.Minute; seconds > 0 || len(parts) == 0 {
+		parts = append(parts, strconv.FormatFloat(seconds.Seconds(), 'f', -1, 64)+"s")
+	}
+
+	return sign + strings.Join(parts, " ")
+}
+
+// iso writes span as ISO 8601 duration, e.g. P1M3DT2H30M, it is empty for spans of workdays and spans
+// with components of different signs
+func (s span) iso() string {
+	negative := s.months < 0 || s.days < 0 || s.clock < 0
+	if s.workdays != 0 || negative && (s.months > 0 || s.days > 0 || s.clock > 0) {
+		return ""
+	}
+
+	sign := ""
+	if negative {
+		sign, s = "-", s.negated()
+	}
+
+	date := ""
+	for _, component := range []struct {
+		amount     int
+		designator string
+	}{{s.months / 12, "Y"}, {s.months % 12, "M"}, {s.days, "D"}} {
+		if component.amount != 0 {
+			date += fmt.Sprintf("%d%s", component.amount, component.designator)
+		}
+	}
+
+	clock := ""
+	if hours := s.clock / time.Hour; hours > 0 {
+		clock += fmt.Sprintf("%dH", hours)
+	}
+	if minutes := s.clock % time.Hour / time.Minute; minutes > 0 {
+		clock += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds := s.clock % time.Minute; seconds > 0 || date == "" && clock == "" {
+		clock += strconv.FormatFloat(seconds.Seconds(), 'f', -1, 64) + "S"
+	}
+	if clock != "" {
+		clock = "T" + clock
+	}
+
+	return sign + "P" + date + clock
+}
+
+func (a timeArithmetic) negate(x interface{}) (interface{}, error) {
+	switch f := x.(type) {
+	case float64:
+		return -f, nil
+	case span:
+		return f.negated(), nil
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("cannot negate %s", kind(x)))
+	}
+}
+
+// function calculates functions of numbers only
+func (a timeArithmetic) function(name string, x interface{}) (interface{}, error) {
+	function, ok := functions[name]
+	if !ok {
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown function: %s", name))
+	}
+
+	f, ok := x.(float64)
+	if !ok {
+		return nil, errors.NewCalculationError(fmt.Sprintf("function %s requires a number, got %s", name, kind(x)))
+	}
+
+	return function(f), nil
+}
+
+// convert converts timestamps to time zones and durations to units of durations, e.g. 2026-12-24 - today in workdays
+func (a timeArithmetic) convert(x, y interface{}) (interface{}, error) {
+	switch g := y.(type) {
+	case zone:
+		f, ok := x.(instant)
+		if !ok || f.date {
+			return nil, errors.NewCalculationError(fmt.Sprintf("only timestamps can be converted to time zones, got %s", kind(x)))
+		}
+		return instant{t: f.t.In(g.location)}, nil
+	case span:
+		f, ok := x.(span)
+		if !ok {
+			return nil, errors.NewCalculationError(fmt.Sprintf("only durations can be converted to units of durations, got %s", kind(x)))
+		}
+		if !g.isUnit() {
+			return nil, errors.NewCalculationError(fmt.Sprintf("%s is not a unit, durations can be converted only to units", g))
+		}
+		if _, err := f.amount(g.unit); err != nil {
+			return nil, err
+		}
+		f.unit = g.unit
+		return f, nil
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("%s is not a unit of durations nor a time zone", kind(y)))
+	}
+}
+
+func (a timeArithmetic) value(x interface{}) (calculator.Value, error) {
+	if z, ok := x.(zone); ok {
+		return nil, errors.NewCalculationError(fmt.Sprintf("time zone %s is not a value, timestamps are converted to time zones with in or to", z.location))
+	}
+
+	return timeValue{value: x, format: a.format}, nil
+}
+
+// Float64 returns seconds since 1970-01-01 UTC of dates and timestamps, durations in their units or in seconds
+func (v timeValue) Float64() float64 {
+	switch x := v.value.(type) {
+	case instant:
+		return float64(x.t.Unix()) + float64(x.t.Nanosecond())/1e9
+	case span:
+		if x.unit != "" {
+			amount, _ := x.amount(x.unit)
+			return amount
+		}
+		seconds, _ := x.seconds()
+		return seconds
+	default:
+		return x.(float64)
+	}
+}
+
+// String writes dates and timestamps in time format of the mode, e.g. 2026-12-01, and durations
+// in calendar components and clock time, e.g. 45 days 3h 20m
+func (v timeValue) String() string {
+	switch x := v.value.(type) {
+	case instant:
+		if v.format == unixFormat {
+			return strconv.FormatInt(x.t.Unix(), 10)
+		}
+		layouts, ok := timeFormats[v.format]
+		if !ok {
+			return x.t.Format(v.format)
+		}
+		if x.date {
+			return x.t.Format(layouts[0])
+		}
+		return x.t.Format(layouts[1])
+	case span:
+		return x.String()
+	default:
+		return strconv.FormatFloat(x.(float64), 'g', -1, 64)
+	}
+}
+
+// Time returns kind and representations of dates, timestamps and durations, it is nil for numbers
+func (v timeValue) Time() *calculator.Time {
+	switch x := v.value.(type) {
+	case instant:
+		t := &calculator.Time{Kind: kind(x), Formatted: v.String(), ISO: x.t.Format(time.RFC3339), Unix: x.t.Unix(), Weekday: x.t.Weekday().String()}
+		if x.date {
+			t.ISO = x.t.Format("2006-01-02")
+		} else if t.Zone = x.t.Location().String(); t.Zone == "Local" {
+			t.Zone = x.t.Format("MST")
+		}
+		return t
+	case span:
+		seconds, _ := x.seconds()
+		return &calculator.Time{Kind: kind(x), Formatted: v.String(), ISO: x.iso(), Seconds: seconds}
+	default:
+		return nil
+	}
+}
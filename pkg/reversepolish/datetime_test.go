@@ -0,0 +1,130 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestCalculateTime(t *testing.T) {
+	defer func(now func() time.Time) { timeNow = now }(timeNow)
+	timeNow = func() time.Time { return time.Date(2026, 10, 19, 7, 41, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name          string
+		input         string
+		mode          calculator.Mode
+		expected      string
+		expectedTime  *calculator.Time
+		expectedError error
+	}{
+		{"Date plus days", "2026-10-17 + 45 days", calculator.Mode{}, "2026-12-01", &calculator.Time{Kind: "date", Formatted: "2026-12-01", ISO: "2026-12-01", Unix: 1796083200, Weekday: "Tuesday"}, nil},
+		{"Days until date", "2026-12-24 - today", calculator.Mode{}, "66 days", &calculator.Time{Kind: "duration", Formatted: "66 days", ISO: "P66D", Seconds: 5702400}, nil},
+		{"Workdays until date", "2026-12-24 - today in workdays", calculator.Mode{}, "48 workdays", &calculator.Time{Kind: "duration", Formatted: "48 workdays", ISO: "P66D", Seconds: 5702400}, nil},
+		{"Multiplied duration", "3h 20m * 4", calculator.Mode{}, "13h 20m", &calculator.Time{Kind: "duration", Formatted: "13h 20m", ISO: "PT13H20M", Seconds: 48000}, nil},
+		{"Now in time zone", "now in Europe/Warsaw", calculator.Mode{}, "2026-10-19T09:41:00+02:00", &calculator.Time{Kind: "timestamp", Formatted: "2026-10-19T09:41:00+02:00", ISO: "2026-10-19T09:41:00+02:00", Unix: 1792395660, Weekday: "Monday", Zone: "Europe/Warsaw"}, nil},
+		{"Workdays skip weekend", "2026-10-16 + 3 workdays", calculator.Mode{}, "2026-10-21", nil, nil},
+		{"Workdays backwards", "2026-10-19 - 1 workday", calculator.Mode{}, "2026-10-16", nil, nil},
+		{"Month keeps day of month", "2026-01-31 + 1 month", calculator.Mode{}, "2026-02-28", nil, nil},
+		{"Date plus clock time is timestamp", "2026-10-17 + 36h", calculator.Mode{Zone: "Europe/Warsaw"}, "2026-10-18T12:00:00+02:00", nil, nil},
+		{"Day keeps time of day across change of time", "2026-10-24T12:00:00 + 1 day", calculator.Mode{Zone: "Europe/Warsaw"}, "2026-10-25T12:00:00+01:00", nil, nil},
+		{"Timestamp without offset in zone of mode", "2026-10-17T14:30 in UTC", calculator.Mode{Zone: "Europe/Warsaw"}, "2026-10-17T12:30:00Z", nil, nil},
+		{"Difference of timestamps", "2026-10-17T18:00:00+02:00 - 2026-10-17T14:30:00Z", calculator.Mode{}, "1h 30m", nil, nil},
+		{"Today in zone of mode", "today", calculator.Mode{Zone: "Pacific/Auckland"}, "2026-10-19", nil, nil},
+		{"Calendar components", "2 years + 3 months + 1 day + 90 min", calculator.Mode{}, "2 years 3 months 1 day 1h 30m", &calculator.Time{Kind: "duration", Formatted: "2 years 3 months 1 day 1h 30m", ISO: "P2Y3M1DT1H30M", Seconds: 71094942}, nil},
+		{"Negative duration", "today - 2026-12-24", calculator.Mode{}, "-66 days", &calculator.Time{Kind: "duration", Formatted: "-66 days", ISO: "-P66D", Seconds: -5702400}, nil},
+		{"Conversion of duration", "3h 20m in min", calculator.Mode{}, "200 min", &calculator.Time{Kind: "duration", Formatted: "200 min", ISO: "PT3H20M", Seconds: 12000}, nil},
+		{"Fraction of days", "1.5 * 3 days", calculator.Mode{}, "108h", nil, nil},
+		{"Ratio of durations", "(3h 20m) / (40 min)", calculator.Mode{}, "5", nil, nil},
+		{"Unix time format", "2026-10-17 + 45 days", calculator.Mode{TimeFormat: "unix"}, "1796083200", nil, nil},
+		{"Layout time format", "2026-10-17T14:30:00Z + 1 week", calculator.Mode{TimeFormat: "02.01.2006 15:04"}, "24.10.2026 14:30", nil, nil},
+		{"Sum of dates", "2026-10-17 + 2026-10-18", calculator.Mode{}, "", nil, errors.NewCalculationError("cannot calculate date + date")},
+		{"Date in time zone", "2026-10-17 in UTC", calculator.Mode{}, "", nil, errors.NewCalculationError("only timestamps can be converted to time zones, got date")},
+		{"Months converted to days", "1 month in days", calculator.Mode{}, "", nil, errors.NewCalculationError("months and workdays cannot be converted to days, they have no fixed length")},
+		{"Fraction of months", "1.5 * 1 month", calculator.Mode{}, "", nil, errors.NewCalculationError("durations of months and workdays can be multiplied only by integers")},
+		{"Workdays of timestamps", "now - 2026-10-01T00:00:00Z in workdays", calculator.Mode{}, "", nil, errors.NewCalculationError("only differences of dates and workdays can be converted to workdays")},
+		{"Time zone as result", "Europe/Warsaw", calculator.Mode{}, "", nil, errors.NewCalculationError("time zone Europe/Warsaw is not a value")},
+		{"Unknown time zone", "now in Europe/Gotham", calculator.Mode{}, "", nil, errors.NewCalculationError("unknown time zone: Europe/Gotham")},
+		{"Invalid date", "2026-02-30 + 1 day", calculator.Mode{}, "", nil, errors.NewParsingError("could not parse 2026-02-30 as a date")},
+		{"Unknown zone of mode", "today", calculator.Mode{Zone: "Mars/Olympus"}, "", nil, errors.NewInputError("Unknown time zone: Mars/Olympus")},
+		{"Unknown time format", "today", calculator.Mode{TimeFormat: "long"}, "", nil, errors.NewInputError("Unknown time format: long")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseTime(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			tt.mode.Name = calculator.TimeMode
+			if tt.mode.Zone == "" {
+				tt.mode.Zone = "UTC"
+			}
+
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), tt.mode)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if value.String() != tt.expected {
+				t.Errorf("expected result to be %s, got %s", tt.expected, value.String())
+			}
+
+			if tt.expectedTime == nil {
+				return
+			}
+
+			if result := value.(calculator.TimeValue).Time(); !cmp.Equal(tt.expectedTime, result) {
+				t.Errorf("expected time to be %v, got %v", tt.expectedTime, result)
+			}
+		})
+	}
+}
+
+func TestCalculateTimeOutsideTimeMode(t *testing.T) {
+	operation, err := ParseTime(context.Background(), "2026-10-17 + 3h 20m")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	expectedError := errors.NewCalculationError("dates, durations and time zones can be calculated only in time mode")
+
+	if _, err := operation.Calculate(context.Background()); err == nil || !strings.Contains(err.Error(), expectedError.Error()) {
+		t.Errorf("expected error to be %v, got %v", expectedError, err)
+	}
+
+	if _, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), calculator.Mode{Name: calculator.RationalMode}); err == nil || !strings.Contains(err.Error(), expectedError.Error()) {
+		t.Errorf("expected error to be %v, got %v", expectedError, err)
+	}
+}
+
+func TestCalculateDatesAsInfix(t *testing.T) {
+	operation, err := ParseInfix(context.Background(), "2026-10-17")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	result, err := operation.Calculate(context.Background())
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	if result != 1999 {
+		t.Errorf("expected result to be 1999, got %v", result)
+	}
+}
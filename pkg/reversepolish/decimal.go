@@ -0,0 +1,228 @@
+package reversepolish
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// defaultDecimalScale is the scale of decimal numbers if mode does not set it
+const defaultDecimalScale = 2
+
+// maxDecimalScale is the highest scale of decimal numbers
+const maxDecimalScale = 1000
+
+// decimalArithmetic calculates with *big.Int numbers of scale fractional digits, i.e. value of n is n / 10^scale,
+// results of operations are calculated exactly and rounded to scale using rounding mode
+type decimalArithmetic struct {
+	scale    int
+	one      *big.Int
+	rounding big.RoundingMode
+}
+
+type decimalValue struct {
+	value *big.Int
+	scale int
+}
+
+func newDecimalArithmetic(mode calculator.Mode) (arithmetic, error) {
+	rounding, err := roundingMode(mode.Rounding)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := defaultDecimalScale
+	if mode.Scale != nil {
+		scale = int(*mode.Scale)
+	}
+
+	if scale > maxDecimalScale {
+		return nil, errors.NewInputError(fmt.Sprintf("Scale has to be at most %d", maxDecimalScale))
+	}
+
+	return decimalArithmetic{scale: scale, one: pow10(scale), rounding: rounding}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// round returns rational number rounded to scale
+func (a decimalArithmetic) round(r *big.Rat) *big.Int {
+	num := new(big.Int).Mul(r.Num(), a.one)
+	quotient, remainder := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	// quotient is truncated towards zero, it is moved away from zero if rounding requires it
+	awayFromZero := false
+	twice := new(big.Int).Abs(remainder)
+	half := twice.Lsh(twice, 1).Cmp(r.Denom())
+
+	switch a.rounding {
+	case big.ToNearestEven:
+		awayFromZero = half > 0 || half == 0 && quotient.Bit(0) == 1
+	case big.ToNearestAway:
+		awayFromZero = half >= 0
+	case big.AwayFromZero:
+		awayFromZero = true
+	case big.ToNegativeInf:
+		awayFromZero = num.Sign() < 0
+	case big.ToPositiveInf:
+		awayFromZero = num.Sign() > 0
+	}
+
+	if awayFromZero {
+		quotient.Add(quotient, big.NewInt(int64(num.Sign())))
+	}
+
+	return quotient
+}
+
+func (a decimalArithmetic) rat(x interface{}) *big.Rat {
+	return new(big.Rat).SetFrac(x.(*big.Int), a.one)
+}
+
+func (a decimalArithmetic) number(item lexer.Item) (interface{}, error) {
+	r, err := a.exact(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.round(r), nil
+}
+
+func (a decimalArithmetic) negativeNumber(item lexer.Item) (interface{}, error) {
+	r, err := a.exact(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.round(r.Neg(r)), nil
+}
+
+// exact returns number written in operation before rounding, constants are calculated with float arithmetic
+func (a decimalArithmetic) exact(item lexer.Item) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(item.GetString())
+	if ok {
+		return r, nil
+	}
+
+	if _, ok := constants[item.GetString()]; !ok {
+		return nil, errors.NewParsingError(fmt.Sprintf("could not parse %s as a number", item.GetString()))
+	}
+
+	f, err := a.float().number(item)
+	if err != nil {
+		return nil, err
+	}
+	r, _ = f.(*big.Float).Rat(nil)
+
+	return r, nil
+}
+
+// float returns float arithmetic precise enough for decimals of scale
+func (a decimalArithmetic) float() bigFloatArithmetic {
+	return bigFloatArithmetic{precision: uint(math.Ceil(float64(a.scale+40)*math.Log2(10))) + guardBits}
+}
+
+// approximate rounds result of float arithmetic to scale
+func (a decimalArithmetic) approximate(f interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	r, _ := f.(*big.Float).Rat(nil)
+
+	return a.round(r), nil
+}
+
+func (a decimalArithmetic) operator(operator string, x, y interface{}) (interface{}, error) {
+	m, n := x.(*big.Int), y.(*big.Int)
+
+	switch operator {
+	case "+":
+		return new(big.Int).Add(m, n), nil
+	case "-":
+		return new(big.Int).Sub(m, n), nil
+	case "*":
+		return a.round(new(big.Rat).SetFrac(new(big.Int).Mul(m, n), new(big.Int).Mul(a.one, a.one))), nil
+	case "/":
+		if n.Sign() == 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+		return a.round(new(big.Rat).SetFrac(m, n)), nil
+	case "^":
+		exponent := a.rat(n)
+		if exponent.IsInt() {
+			power, err := integerPower(a.rat(m), exponent.Num())
+			if err != nil {
+				return nil, err
+			}
+			return a.round(power), nil
+		}
+
+		f := a.float()
+		return a.approximate(f.operator("^", f.newFloat().SetRat(a.rat(m)), f.newFloat().SetRat(exponent)))
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown operator: %s", operator))
+	}
+}
+
+func (a decimalArithmetic) negate(x interface{}) (interface{}, error) {
+	return new(big.Int).Neg(x.(*big.Int)), nil
+}
+
+func (a decimalArithmetic) function(name string, x interface{}) (interface{}, error) {
+	n := x.(*big.Int)
+
+	switch name {
+	case "abs":
+		return new(big.Int).Abs(n), nil
+	case "floor", "ceil":
+		quotient, remainder := new(big.Int).DivMod(n, a.one, new(big.Int))
+		if name == "ceil" && remainder.Sign() != 0 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+		return quotient.Mul(quotient, a.one), nil
+	}
+
+	f := a.float()
+
+	return a.approximate(f.function(name, f.newFloat().SetRat(a.rat(n))))
+}
+
+func (a decimalArithmetic) value(x interface{}) (calculator.Value, error) {
+	return decimalValue{x.(*big.Int), a.scale}, nil
+}
+
+func (v decimalValue) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(v.value, pow10(v.scale)).Float64()
+
+	return f
+}
+
+// String writes value with all fractional digits of scale, e.g. 12.50
+func (v decimalValue) String() string {
+	digits := new(big.Int).Abs(v.value).String()
+	if len(digits) <= v.scale {
+		digits = strings.Repeat("0", v.scale-len(digits)+1) + digits
+	}
+
+	sign := ""
+	if v.value.Sign() < 0 {
+		sign = "-"
+	}
+
+	if v.scale == 0 {
+		return sign + digits
+	}
+
+	return sign + digits[:len(digits)-v.scale] + "." + digits[len(digits)-v.scale:]
+}
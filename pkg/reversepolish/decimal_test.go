@@ -0,0 +1,81 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestCalculateDecimal(t *testing.T) {
+	scale := func(s uint) *uint {
+		return &s
+	}
+
+	tests := []struct {
+		name            string
+		input           string
+		mode            calculator.Mode
+		expectedDecimal string
+		expectedError   error
+	}{
+		{"Default scale", "0.1 + 0.2", calculator.Mode{}, "0.30", nil},
+		{"Scale", "10 / 4", calculator.Mode{Scale: scale(4)}, "2.5000", nil},
+		{"Zero scale", "7 / 2", calculator.Mode{Scale: scale(0)}, "4", nil},
+		{"Half even rounds to even digit", "0.125 + 0.01 - 0.01", calculator.Mode{}, "0.12", nil},
+		{"Half even rounds half of odd digit up", "0.135 * 1", calculator.Mode{}, "0.14", nil},
+		{"Half up", "0.125 * 1", calculator.Mode{Rounding: "half-up"}, "0.13", nil},
+		{"Half up of negative number", "-0.125 * 1", calculator.Mode{Rounding: "half-up"}, "-0.13", nil},
+		{"Down", "2 / 3", calculator.Mode{Rounding: "down"}, "0.66", nil},
+		{"Up", "1 / 3", calculator.Mode{Rounding: "up"}, "0.34", nil},
+		{"Floor", "-1 / 3", calculator.Mode{Rounding: "floor"}, "-0.34", nil},
+		{"Ceiling", "-2 / 3", calculator.Mode{Rounding: "ceiling"}, "-0.66", nil},
+		{"Half even of negative literal", "-2.345", calculator.Mode{}, "-2.34", nil},
+		{"Half up of negative literal", "-2.345", calculator.Mode{Rounding: "half-up"}, "-2.35", nil},
+		{"Down of negative literal", "-2.349", calculator.Mode{Rounding: "down"}, "-2.34", nil},
+		{"Up of negative literal", "-2.341", calculator.Mode{Rounding: "up"}, "-2.35", nil},
+		{"Floor of negative literal", "-2.341", calculator.Mode{Rounding: "floor"}, "-2.35", nil},
+		{"Ceiling of negative literal", "-2.341", calculator.Mode{Rounding: "ceiling"}, "-2.34", nil},
+		{"Ceiling of negated literal in operation", "1 - -2.341", calculator.Mode{Rounding: "ceiling"}, "3.34", nil},
+		{"Floor of negative constant", "-pi", calculator.Mode{Rounding: "floor"}, "-3.15", nil},
+		{"Rounding of every operation", "1 / 3 * 3", calculator.Mode{}, "0.99", nil},
+		{"Small negative numbers", "-1 / 100", calculator.Mode{}, "-0.01", nil},
+		{"Interest", "1000 * (1 + 0.05 / 12) ^ 12", calculator.Mode{Scale: scale(4)}, "1051.6000", nil},
+		{"Large numbers", "12345678901234567890.12 * 100", calculator.Mode{}, "1234567890123456789012.00", nil},
+		{"Square root", "sqrt(2)", calculator.Mode{Scale: scale(6)}, "1.414214", nil},
+		{"Constants", "pi", calculator.Mode{Scale: scale(4)}, "3.1416", nil},
+		{"Floor and ceiling", "floor(-2.5) + ceil(2.25) + abs(-0.5)", calculator.Mode{}, "0.50", nil},
+		{"Division by literal rounded to zero", "1 / 0.001", calculator.Mode{}, "", errors.NewCalculationError("division by zero")},
+		{"Scale too high", "1", calculator.Mode{Scale: scale(5000)}, "", errors.NewInputError("Scale has to be at most 1000")},
+		{"Unknown rounding mode", "1", calculator.Mode{Rounding: "bankers"}, "", errors.NewInputError("Unknown rounding mode: bankers")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			tt.mode.Name = calculator.DecimalMode
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), tt.mode)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if value.String() != tt.expectedDecimal {
+				t.Errorf("expected decimal result to be %s, got %s", tt.expectedDecimal, value.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package reversepolish
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// derivativeFunction is replaced with derivative of its first argument with respect to the second one while parsing
+const derivativeFunction = "diff"
+
+// derivatives of functions with respect to their argument u, chain rule is applied when deriving
+var derivatives = map[string]string{
+	"sqrt":  "1 / (2 * sqrt(u))",
+	"abs":   "u / abs(u)",
+	"exp":   "exp(u)",
+	"ln":    "1 / u",
+	"log":   "1 / (u * ln(10))",
+	"sin":   "cos(u)",
+	"cos":   "-sin(u)",
+	"tan":   "1 / cos(u) ^ 2",
+	"asin":  "1 / sqrt(1 - u ^ 2)",
+	"acos":  "-1 / sqrt(1 - u ^ 2)",
+	"atan":  "1 / (1 + u ^ 2)",
+	"sinh":  "cosh(u)",
+	"cosh":  "sinh(u)",
+	"tanh":  "1 / cosh(u) ^ 2",
+	"floor": "0",
+	"ceil":  "0",
+	"re":    "1",
+	"im":    "0",
+	"conj":  "1",
+	"arg":   "0",
+}
+
+type deriver struct {
+	lexers Lexers
+}
+
+// NewDeriver returns deriver of operations lexed by provided lexers
+func NewDeriver(lexers Lexers) calculator.Deriver {
+	return deriver{lexers: lexers}
+}
+
+// Derive lexes operation written in notation, parses it and derives with respect to variable,
+// derivative is calculated at the point if any is provided
+func (d deriver) Derive(ctx context.Context, notation string, input string, variable string, at map[string]float64) (calculator.Derivative, error) {
+	if variable == "" {
+		return calculator.Derivative{}, errors.NewInputError("Variable cannot be empty")
+	}
+
+	lex, err := d.lexers.lexer(notation)
+	if err != nil {
+		return calculator.Derivative{}, err
+	}
+
+	operation, err := ParseItems(ctx, lex(input))
+	if err != nil {
+		return calculator.Derivative{}, err
+	}
+
+	derived, err := Derive(operation, variable)
+	if err != nil {
+		return calculator.Derivative{}, err
+	}
+
+	tree, err := operationTree(derived)
+	if err != nil {
+		return calculator.Derivative{}, err
+	}
+
+	derivative := calculator.Derivative{Derivative: tree.format(), Tree: tree.text()}
+
+	if len(at) > 0 {
+		value, err := derived.(*rpnOperation).Eval(ctx, at)
+		if err != nil {
+			return calculator.Derivative{}, err
+		}
+		derivative.Value = &value
+	}
+
+	return derivative, nil
+}
+
+// Derive returns simplified derivative of operation with respect to variable
+func Derive(operation calculator.OperationInterface, variable string) (calculator.OperationInterface, error) {
+	tree, err := operationTree(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	derivative, err := tree.derivative(variable)
+	if err != nil {
+		return nil, err
+	}
+
+	simplified, err := derivative.simplify()
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpnOperation{simplified.items()}, nil
+}
+
+// expandDerivatives replaces derivative functions in items with derivatives of their arguments
+func expandDerivatives(items []lexer.Item) (calculator.OperationInterface, error) {
+	found := false
+	for _, i := range items {
+		found = found || isDerivative(i)
+	}
+
+	if !found {
+		return &rpnOperation{items}, nil
+	}
+
+	tree, err := buildTree(items)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := tree.expandDerivatives()
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpnOperation{expanded.items()}, nil
+}
+
+func (n *node) expandDerivatives() (*node, error) {
+	expanded := &node{item: n.item, children: []*node{}}
+	for _, c := range n.children {
+		child, err := c.expandDerivatives()
+		if err != nil {
+			return nil, err
+		}
+		expanded.children = append(expanded.children, child)
+	}
+
+	if !isDerivative(n.item) {
+		return expanded, nil
+	}
+
+	if !isVariable(expanded.children[1].item) {
+		return nil, errors.NewParsingError(fmt.Sprintf("second argument of %s has to be a variable", derivativeFunction))
+	}
+
+	derivative, err := expanded.children[0].derivative(expanded.children[1].item.GetString())
+	if err != nil {
+		return nil, err
+	}
+
+	return derivative.simplify()
+}
+
+// derivative returns unsimplified derivative of node with respect to variable
+func (n *node) derivative(variable string) (*node, error) {
+	if !n.dependsOn(variable) {
+		return numberNode(new(big.Rat)), nil
+	}
+
+	if isVariable(n.item) {
+		return numberNode(big.NewRat(1, 1)), nil
+	}
+
+	if isFunction(n.item) {
+		return n.functionDerivative(variable)
+	}
+
+	derivatives := []*node{}
+	for _, c := range n.children {
+		d, err := c.derivative(variable)
+		if err != nil {
+			return nil, err
+		}
+		derivatives = append(derivatives, d)
+	}
+
+	if isNegation(n.item) {
+		return negationNode(derivatives[0]), nil
+	}
+
+	a, b := n.children[0], n.children[1]
+	da, db := derivatives[0], derivatives[1]
+
+	switch n.item.GetType() {
+	case lexer.Addition, lexer.Subtraction:
+		return operatorNode(n.item.GetType(), da, db), nil
+	case lexer.Multiplication:
+		return operatorNode(lexer.Addition, operatorNode(lexer.Multiplication, da, b), operatorNode(lexer.Multiplication, a, db)), nil
+	case lexer.Division:
+		numerator := operatorNode(lexer.Subtraction, operatorNode(lexer.Multiplication, da, b), operatorNode(lexer.Multiplication, a, db))
+		return operatorNode(lexer.Division, numerator, operatorNode(lexer.Exponent, b, numberNode(big.NewRat(2, 1)))), nil
+	case lexer.Exponent:
+		if !b.dependsOn(variable) {
+			power := operatorNode(lexer.Exponent, a, operatorNode(lexer.Subtraction, b, numberNode(big.NewRat(1, 1))))
+			return operatorNode(lexer.Multiplication, operatorNode(lexer.Multiplication, b, power), da), nil
+		}
+
+		// d(a^b) = a^b * (b' * ln(a) + b * a' / a)
+		logarithm := operatorNode(lexer.Multiplication, db, functionNode("ln", a))
+		base := operatorNode(lexer.Division, operatorNode(lexer.Multiplication, b, da), a)
+		return operatorNode(lexer.Multiplication, n, operatorNode(lexer.Addition, logarithm, base)), nil
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("cannot derive: %s", n.item.GetString()))
+	}
+}
+
+// functionDerivative applies chain rule to derivative of function from derivatives table
+func (n *node) functionDerivative(variable string) (*node, error) {
+	template, ok := derivatives[n.item.GetString()]
+	if !ok {
+		return nil, errors.NewCalculationError(fmt.Sprintf("cannot derive function: %s", n.item.GetString()))
+	}
+
+	operation, err := ParseInfix(context.Background(), template)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := operationTree(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	inner, err := n.children[0].derivative(variable)
+	if err != nil {
+		return nil, err
+	}
+
+	return operatorNode(lexer.Multiplication, tree.substitute("u", n.children[0]), inner), nil
+}
+
+// dependsOn returns true if variable occurs in the tree
+func (n *node) dependsOn(variable string) bool {
+	if isVariable(n.item) {
+		return n.item.GetString() == variable
+	}
+
+	for _, c := range n.children {
+		if c.dependsOn(variable) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// substitute returns tree with variable replaced by the replacement tree
+func (n *node) substitute(variable string, replacement *node) *node {
+	if isVariable(n.item) && n.item.GetString() == variable {
+		return replacement
+	}
+
+	substituted := &node{item: n.item, children: []*node{}}
+	for _, c := range n.children {
+		substituted.children = append(substituted.children, c.substitute(variable, replacement))
+	}
+
+	return substituted
+}
+
+func operatorNode(typ lexer.ItemType, left *node, right *node) *node {
+	operators := map[lexer.ItemType]string{
+		lexer.Addition:       "+",
+		lexer.Subtraction:    "-",
+		lexer.Multiplication: "*",
+		lexer.Division:       "/",
+		lexer.Exponent:       "^",
+	}
+
+	return &node{item: lexer.NewItem(typ, operators[typ]), children: []*node{left, right}}
+}
+
+func negationNode(operand *node) *node {
+	return &node{item: lexer.NewItem(lexer.Negation, "-"), children: []*node{operand}}
+}
+
+func functionNode(name string, argument *node) *node {
+	return &node{item: lexer.NewItem(lexer.Function, name), children: []*node{argument}}
+}
+
+func isDerivative(item lexer.Item) bool {
+	return isFunction(item) && item.GetString() == derivativeFunction
+}
@@ -0,0 +1,98 @@
+package reversepolish
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+func TestDerive(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		variable      string
+		expected      string
+		expectedError error
+	}{
+		{"Constant", "2 * y + pi", "x", "0", nil},
+		{"Power rule", "x ^ 3 + 2 * x", "x", "3 * x ^ 2 + 2", nil},
+		{"Product rule", "x * y * x", "x", "2 * x * y", nil},
+		{"Quotient rule", "1 / x", "x", "-1 / x ^ 2", nil},
+		{"Chain rule", "sin(x ^ 2)", "x", "2 * cos(x ^ 2) * x", nil},
+		{"Exponential", "e ^ x", "x", "e ^ x", nil},
+		{"Logarithm", "ln(2 * x)", "x", "1 / x", nil},
+		{"Variable exponent", "2 ^ x", "x", "2 ^ x * ln(2)", nil},
+		{"Square root", "sqrt(x)", "x", "0.5 / sqrt(x)", nil},
+		{"Nested diff", "diff(x ^ 3, x)", "x", "6 * x", nil},
+		{"Second argument not variable", "diff(x ^ 2, 2)", "x", "", errors.NewParsingError("second argument of diff has to be a variable")},
+		{"Wrong number of arguments", "diff(x ^ 2)", "x", "", errors.NewParsingError("function diff takes 2 arguments")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result string
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err == nil {
+				operation, err = Derive(operation, tt.variable)
+			}
+			if err == nil {
+				result, err = Format(operation)
+			}
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("expected result to be %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDeriver(t *testing.T) {
+	d := NewDeriver(Lexers{calculator.InfixNotation: lexer.Lex})
+
+	derivative, err := d.Derive(context.Background(), "", "x ^ 2 * y", "x", map[string]float64{"x": 3, "y": 2})
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	if derivative.Derivative != "2 * x * y" {
+		t.Errorf("expected derivative to be 2 * x * y, got %v", derivative.Derivative)
+	}
+
+	if derivative.Value == nil || math.Abs(*derivative.Value-12) > 1e-9 {
+		t.Errorf("expected value to be 12, got %v", derivative.Value)
+	}
+
+	_, err = d.Derive(context.Background(), "", "x ^ 2", "", nil)
+	if err == nil || !strings.Contains(err.Error(), "Variable cannot be empty") {
+		t.Errorf("expected empty variable error, got %v", err)
+	}
+}
+
+func TestCalculateDerivative(t *testing.T) {
+	operation, err := ParseInfix(context.Background(), "diff(sin(x) * 2, x) + 1")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	result, err := operation.(*rpnOperation).Eval(context.Background(), map[string]float64{"x": 0})
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	if result != 3 {
+		t.Errorf("expected result to be 3, got %v", result)
+	}
+}
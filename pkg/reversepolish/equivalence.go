@@ -0,0 +1,125 @@
+package reversepolish
+
+import (
+	"context"
+	"math"
+	"math/rand"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+const (
+	// SymbolicMethod means expressions were compared by simplifying their difference
+	SymbolicMethod = "symbolic"
+	// NumericMethod means expressions were compared by calculating them for random values of variables
+	NumericMethod = "numeric"
+)
+
+// equivalenceSamples is a number of random assignments of variables tested when comparing numerically
+const equivalenceSamples = 100
+
+// equivalenceTolerance is the relative difference of values still considered equal
+const equivalenceTolerance = 1e-9
+
+// equivalenceRange bounds random values of variables
+const equivalenceRange = 10.0
+
+type equivalenceChecker struct {
+	lexers Lexers
+}
+
+// NewEquivalenceChecker returns equivalence checker of operations lexed by provided lexers
+func NewEquivalenceChecker(lexers Lexers) calculator.EquivalenceChecker {
+	return equivalenceChecker{lexers: lexers}
+}
+
+// Equivalent lexes operations written in notation, parses them and checks if they are equivalent
+func (c equivalenceChecker) Equivalent(ctx context.Context, notation string, first string, second string) (calculator.Equivalence, error) {
+	lex, err := c.lexers.lexer(notation)
+	if err != nil {
+		return calculator.Equivalence{}, err
+	}
+
+	operations := []calculator.OperationInterface{}
+	for _, input := range []string{first, second} {
+		operation, err := ParseItems(ctx, lex(input))
+		if err != nil {
+			return calculator.Equivalence{}, err
+		}
+		operations = append(operations, operation)
+	}
+
+	return Equivalent(ctx, operations[0], operations[1])
+}
+
+// Equivalent checks if difference of operations simplifies to zero, if it does not operations are calculated
+// for random values of variables and a counterexample is returned if values differ
+func Equivalent(ctx context.Context, first calculator.OperationInterface, second calculator.OperationInterface) (calculator.Equivalence, error) {
+	firstTree, err := operationTree(first)
+	if err != nil {
+		return calculator.Equivalence{}, err
+	}
+
+	secondTree, err := operationTree(second)
+	if err != nil {
+		return calculator.Equivalence{}, err
+	}
+
+	// simplification fails i.e. on division by zero, numeric comparison decides then
+	difference, err := operatorNode(lexer.Subtraction, firstTree, secondTree).simplify()
+	if err == nil && isNumber(difference.item) && getNumericValue(difference.item) == 0 {
+		return calculator.Equivalence{Equivalent: true, Method: SymbolicMethod}, nil
+	}
+
+	return equivalentNumerically(ctx, first.(*rpnOperation), second.(*rpnOperation))
+}
+
+func equivalentNumerically(ctx context.Context, first *rpnOperation, second *rpnOperation) (calculator.Equivalence, error) {
+	variables := rpnOperation{append(append([]lexer.Item{}, first.items...), second.items...)}.Variables()
+	random := rand.New(rand.NewSource(1))
+
+	tested := 0
+	for k := 0; k < equivalenceSamples; k++ {
+		bindings := map[string]float64{}
+		for _, v := range variables {
+			bindings[v] = (random.Float64()*2 - 1) * equivalenceRange
+		}
+
+		firstValue, firstErr := first.Eval(ctx, bindings)
+		secondValue, secondErr := second.Eval(ctx, bindings)
+
+		// points outside of domain of any of operations are skipped
+		if firstErr != nil || secondErr != nil || !isFinite(firstValue) || !isFinite(secondValue) {
+			continue
+		}
+		tested++
+
+		if !almostEqual(firstValue, secondValue) {
+			return calculator.Equivalence{
+				Equivalent:     false,
+				Method:         NumericMethod,
+				Counterexample: &calculator.Counterexample{Bindings: bindings, First: firstValue, Second: secondValue},
+			}, nil
+		}
+
+		if len(variables) == 0 {
+			break
+		}
+	}
+
+	if tested == 0 {
+		return calculator.Equivalence{}, errors.NewCalculationError("could not find values of variables for which both operations are defined")
+	}
+
+	return calculator.Equivalence{Equivalent: true, Method: NumericMethod}, nil
+}
+
+func almostEqual(a float64, b float64) bool {
+	return math.Abs(a-b) <= equivalenceTolerance*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
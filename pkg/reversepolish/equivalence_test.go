@@ -0,0 +1,56 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+func TestEquivalent(t *testing.T) {
+	c := NewEquivalenceChecker(Lexers{calculator.InfixNotation: lexer.Lex})
+
+	tests := []struct {
+		name           string
+		first          string
+		second         string
+		expected       bool
+		expectedMethod string
+		expectedError  error
+	}{
+		{"Symbolic implicit multiplication", "2(x+1)", "2x+2", true, SymbolicMethod, nil},
+		{"Symbolic expanded square", "(x + y)^2", "x^2 + 2*x*y + y^2", true, SymbolicMethod, nil},
+		{"Numeric trigonometric identity", "sin(x)^2 + cos(x)^2", "1", true, NumericMethod, nil},
+		{"Numeric logarithm of product", "ln(x * x)", "2 * ln(abs(x))", true, NumericMethod, nil},
+		{"Different", "2(x+1)", "2x+1", false, NumericMethod, nil},
+		{"Different constants", "0.1 + 0.2", "0.31", false, NumericMethod, nil},
+		{"Power above int64 is not symbolically equivalent", "(x+1)^18446744073709551616", "x+1", false, NumericMethod, nil},
+		{"Parsing error", "2(x+1", "2x+2", false, "", errors.NewParsingError("mismatched parantheses")},
+		{"Never defined", "sqrt(-1 - x^2)", "0", false, "", errors.NewCalculationError("could not find values of variables")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equivalence, err := c.Equivalent(context.Background(), "", tt.first, tt.second)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if equivalence.Equivalent != tt.expected || equivalence.Method != tt.expectedMethod {
+				t.Errorf("expected %v by %v, got %v by %v", tt.expected, tt.expectedMethod, equivalence.Equivalent, equivalence.Method)
+			}
+
+			if !tt.expected && tt.expectedError == nil && equivalence.Counterexample == nil {
+				t.Errorf("expected counterexample, got nil")
+			}
+		})
+	}
+}
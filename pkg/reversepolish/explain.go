@@ -0,0 +1,168 @@
+package reversepolish
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// Lexers maps notation names to functions returning lexers of operations written in them
+type Lexers map[string]func(string) lexer.Lexer
+
+// lexer returns lexer of notation, empty notation means infix
+func (l Lexers) lexer(notation string) (func(string) lexer.Lexer, error) {
+	if notation == "" {
+		notation = calculator.InfixNotation
+	}
+
+	lex, ok := l[notation]
+	if !ok {
+		return nil, errors.NewInputError(fmt.Sprintf("Unknown notation: %s", notation))
+	}
+
+	return lex, nil
+}
+
+type explainer struct {
+	lexers Lexers
+}
+
+// recordingLexer passes items returned by lexer and records them
+type recordingLexer struct {
+	next  lexer.Lexer
+	items []lexer.Item
+}
+
+// NewExplainer returns explainer of operations lexed by provided lexers
+func NewExplainer(lexers Lexers) calculator.Explainer {
+	return explainer{lexers: lexers}
+}
+
+// Explain lexes operation written in notation, parses and calculates it recording every step
+func (e explainer) Explain(ctx context.Context, notation string, input string) (calculator.Explanation, error) {
+	lex, err := e.lexers.lexer(notation)
+	if err != nil {
+		return calculator.Explanation{}, err
+	}
+
+	return Explain(ctx, lex(input))
+}
+
+// Explain parses infix items returned by lexer and calculates operation recording every step
+func Explain(ctx context.Context, l lexer.Lexer) (calculator.Explanation, error) {
+	recorder := &recordingLexer{next: l}
+
+	operation, err := ParseItems(ctx, recorder)
+	if err != nil {
+		return calculator.Explanation{}, err
+	}
+
+	o := operation.(*rpnOperation)
+	tree, err := buildTree(o.items)
+	if err != nil {
+		return calculator.Explanation{}, err
+	}
+
+	explanation := calculator.Explanation{
+		Tokens:  []calculator.Token{},
+		Postfix: []string{},
+		Tree:    tree.text(),
+		Dot:     tree.dot(),
+		Steps:   []calculator.Step{},
+	}
+
+	for _, i := range recorder.items {
+		explanation.Tokens = append(explanation.Tokens, calculator.Token{Type: i.GetType().String(), Value: i.GetString()})
+	}
+
+	for _, i := range o.items {
+		explanation.Postfix = append(explanation.Postfix, itemLabel(i))
+	}
+
+	explanation.Result, err = o.calculate(ctx, nil, func(step calculator.Step) {
+		explanation.Steps = append(explanation.Steps, step)
+	})
+	if err != nil {
+		return calculator.Explanation{}, err
+	}
+
+	return explanation, nil
+}
+
+func (l *recordingLexer) NextItem() lexer.Item {
+	i := l.next.NextItem()
+	if i.GetType() != lexer.Empty {
+		l.items = append(l.items, i)
+	}
+
+	return i
+}
+
+// text returns tree drawn with box-drawing characters, one node per line
+func (n *node) text() string {
+	var b bytes.Buffer
+	b.WriteString(n.label())
+	b.WriteString("\n")
+	n.writeChildren(&b, "")
+
+	return b.String()
+}
+
+func (n *node) writeChildren(b *bytes.Buffer, indent string) {
+	for k, c := range n.children {
+		branch, childIndent := "├── ", "│   "
+		if k == len(n.children)-1 {
+			branch, childIndent = "└── ", "    "
+		}
+
+		b.WriteString(indent + branch + c.label() + "\n")
+		c.writeChildren(b, indent+childIndent)
+	}
+}
+
+// dot returns tree in Graphviz DOT format
+func (n *node) dot() string {
+	var b bytes.Buffer
+	b.WriteString("digraph operation {\n")
+
+	counter := 0
+	n.writeDot(&b, &counter)
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (n *node) writeDot(b *bytes.Buffer, counter *int) int {
+	id := *counter
+	*counter++
+
+	fmt.Fprintf(b, "\tn%d [label=\"%s\"];\n", id, strings.Replace(n.label(), "\"", "\\\"", -1))
+	for _, c := range n.children {
+		childID := c.writeDot(b, counter)
+		fmt.Fprintf(b, "\tn%d -> n%d;\n", id, childID)
+	}
+
+	return id
+}
+
+func (n *node) label() string {
+	if isFunction(n.item) {
+		return n.item.GetString() + "()"
+	}
+
+	return itemLabel(n.item)
+}
+
+// itemLabel distinguishes negation from subtraction, which are both written as -
+func itemLabel(i lexer.Item) string {
+	if isNegation(i) {
+		return "neg"
+	}
+
+	return i.GetString()
+}
@@ -0,0 +1,89 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+func TestExplain(t *testing.T) {
+	e := NewExplainer(Lexers{calculator.InfixNotation: lexer.Lex})
+
+	explanation, err := e.Explain(context.Background(), "", "3+4*-2")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	expected := calculator.Explanation{
+		Tokens: []calculator.Token{
+			{Type: "Number", Value: "3"},
+			{Type: "Addition", Value: "+"},
+			{Type: "Number", Value: "4"},
+			{Type: "Multiplication", Value: "*"},
+			{Type: "Subtraction", Value: "-"},
+			{Type: "Number", Value: "2"},
+		},
+		Postfix: []string{"3", "4", "2", "neg", "*", "+"},
+		Tree:    "+\n├── 3\n└── *\n    ├── 4\n    └── neg\n        └── 2\n",
+		Dot:     "digraph operation {\n\tn0 [label=\"+\"];\n\tn1 [label=\"3\"];\n\tn0 -> n1;\n\tn2 [label=\"*\"];\n\tn3 [label=\"4\"];\n\tn2 -> n3;\n\tn4 [label=\"neg\"];\n\tn5 [label=\"2\"];\n\tn4 -> n5;\n\tn2 -> n4;\n\tn0 -> n2;\n}\n",
+		Steps: []calculator.Step{
+			{Item: "3", Action: "push", Result: 3, Stack: []float64{3}},
+			{Item: "4", Action: "push", Result: 4, Stack: []float64{3, 4}},
+			{Item: "2", Action: "push", Result: 2, Stack: []float64{3, 4, 2}},
+			{Item: "-", Action: "negate", Operands: []float64{2}, Result: -2, Stack: []float64{3, 4, -2}},
+			{Item: "*", Action: "calculate", Operands: []float64{4, -2}, Result: -8, Stack: []float64{3, -8}},
+			{Item: "+", Action: "calculate", Operands: []float64{3, -8}, Result: -5, Stack: []float64{-5}},
+		},
+		Result: -5,
+	}
+
+	if !cmp.Equal(expected, explanation) {
+		t.Errorf("expected explanation to be %v, got %v", expected, explanation)
+	}
+}
+
+func TestExplainErrors(t *testing.T) {
+	e := NewExplainer(Lexers{calculator.InfixNotation: lexer.Lex})
+
+	tests := []struct {
+		name          string
+		notation      string
+		input         string
+		expectedError error
+	}{
+		{
+			"Unknown notation",
+			"latex",
+			"1+2",
+			errors.NewInputError("Unknown notation: latex"),
+		},
+		{
+			"Parsing error",
+			calculator.InfixNotation,
+			"(1+2",
+			errors.NewParsingError("mismatched parantheses"),
+		},
+		{
+			"Calculation error",
+			calculator.InfixNotation,
+			"1 2",
+			errors.NewParsingError("operation has to consist of exactly one expression"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := e.Explain(context.Background(), tt.notation, tt.input)
+
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package reversepolish
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// Format returns canonical infix representation of operation, with single spaces around binary operators
+// and only the parentheses required by operators precedence and associativity
+func Format(operation calculator.OperationInterface) (string, error) {
+	tree, err := operationTree(operation)
+	if err != nil {
+		return "", err
+	}
+
+	return tree.format(), nil
+}
+
+func (n *node) format() string {
+	switch {
+	case isMathOperator(n.item) || isConversion(n.item) || n.item.GetType() == lexer.PlusMinus:
+		left := n.children[0].format()
+		if needsParentheses(n.item, n.children[0], true) {
+			left = "(" + left + ")"
+		}
+
+		right := n.children[1].format()
+		if needsParentheses(n.item, n.children[1], false) {
+			right = "(" + right + ")"
+		}
+
+		return fmt.Sprintf("%s %s %s", left, n.item.GetString(), right)
+	case isNegation(n.item):
+		operand := n.children[0].format()
+		if needsParentheses(n.item, n.children[0], false) {
+			operand = "(" + operand + ")"
+		}
+
+		return "-" + operand
+	case n.item.GetType() == lexer.Interval:
+		return fmt.Sprintf("[%s, %s]", n.children[0].format(), n.children[1].format())
+	case isFunction(n.item):
+		arguments := []string{}
+		for _, c := range n.children {
+			arguments = append(arguments, c.format())
+		}
+		return fmt.Sprintf("%s(%s)", n.item.GetString(), strings.Join(arguments, ", "))
+	default:
+		return strings.TrimSpace(n.item.GetString())
+	}
+}
+
+// needsParentheses decides if child of the operator has to be parenthesized to keep the same operation tree when parsed again
+func needsParentheses(operator lexer.Item, child *node, isLeft bool) bool {
+	if !isMathOperator(child.item) && !isNegation(child.item) && !isConversion(child.item) && child.item.GetType() != lexer.PlusMinus {
+		return false
+	}
+
+	// prefix operator on the right side binds to the operand following it anyway, i.e. 2 ^ -1
+	if isNegation(child.item) && !isLeft {
+		return false
+	}
+
+	switch {
+	case getPrecedenceLevel(child.item) < getPrecedenceLevel(operator):
+		return true
+	case getPrecedenceLevel(child.item) > getPrecedenceLevel(operator):
+		return false
+	case isLeft:
+		return !isLeftAssociative(operator)
+	default:
+		return isLeftAssociative(operator)
+	}
+}
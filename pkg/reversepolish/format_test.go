@@ -0,0 +1,106 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      string
+		expectedError error
+	}{
+		{
+			"Intervals",
+			"2*[1.9,2.1]+(5±0.2)*3",
+			"2 * [1.9, 2.1] + (5 ± 0.2) * 3",
+			nil,
+		},
+		{
+			"Conversion of units",
+			"60mph to km/h",
+			"60 * mph to km / h",
+			nil,
+		},
+		{
+			"Redundant parantheses removed",
+			"((1+2))*3",
+			"(1 + 2) * 3",
+			nil,
+		},
+		{
+			"Precedence",
+			"1+(2*3)-(4/2)",
+			"1 + 2 * 3 - 4 / 2",
+			nil,
+		},
+		{
+			"Left associativity",
+			"(1-2)-(3-4)",
+			"1 - 2 - (3 - 4)",
+			nil,
+		},
+		{
+			"Right associativity",
+			"(2^3)^2+2^(3^2)",
+			"(2 ^ 3) ^ 2 + 2 ^ 3 ^ 2",
+			nil,
+		},
+		{
+			"Negation",
+			"(-2)^2*-(1+2)+-2^2-(-3)",
+			"(-2) ^ 2 * -(1 + 2) + -2 ^ 2 - -3",
+			nil,
+		},
+		{
+			"Negative exponent",
+			"2^(-1)",
+			"2 ^ -1",
+			nil,
+		},
+		{
+			"Mismatched parantheses",
+			"(1+2",
+			"",
+			errors.NewParsingError("mismatched parantheses"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result string
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err == nil {
+				result, err = Format(operation)
+			}
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && err != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expected != result {
+				t.Errorf("expected result to be %v, got %v", tt.expected, result)
+			}
+
+			if tt.expectedError == nil {
+				reparsed, err := ParseInfix(context.Background(), result)
+				if err != nil {
+					t.Fatalf("expected formatted operation to be parsed, got %v", err)
+				}
+
+				if formatted, _ := Format(reparsed); formatted != result {
+					t.Errorf("expected formatting to be stable, got %v", formatted)
+				}
+			}
+		})
+	}
+}
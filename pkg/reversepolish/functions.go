@@ -0,0 +1,36 @@
+package reversepolish
+
+import (
+	"math"
+)
+
+// functions available in operations, all of them accept a single argument, functions of complex numbers
+// are defined for real numbers too
+var functions = map[string]func(float64) float64{
+	"sqrt":  math.Sqrt,
+	"abs":   math.Abs,
+	"exp":   math.Exp,
+	"ln":    math.Log,
+	"log":   math.Log10,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+	"asin":  math.Asin,
+	"acos":  math.Acos,
+	"atan":  math.Atan,
+	"sinh":  math.Sinh,
+	"cosh":  math.Cosh,
+	"tanh":  math.Tanh,
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"re":    func(x float64) float64 { return x },
+	"im":    func(float64) float64 { return 0 },
+	"conj":  func(x float64) float64 { return x },
+	"arg":   func(x float64) float64 { return math.Atan2(0, x) },
+}
+
+// constants available in operations
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
@@ -0,0 +1,515 @@
+package reversepolish
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/bigmath"
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// defaultIntervalDigits is the number of significant decimal digits of bounds if mode does not set precision
+const defaultIntervalDigits = 15
+
+// intervalOperator creates interval from its bounds, square brackets are replaced with it while parsing
+const intervalOperator = "[,]"
+
+// plusMinusOperator creates interval from value and its tolerance
+const plusMinusOperator = "±"
+
+// intervalArithmetic calculates with closed intervals, lower bounds of results are rounded toward negative
+// infinity and upper bounds toward positive infinity, so results enclose all possible values of operations
+type intervalArithmetic struct {
+	float bigFloatArithmetic
+}
+
+type interval struct {
+	lower *big.Float
+	upper *big.Float
+}
+
+type intervalValue struct {
+	interval
+	digits int
+}
+
+// monotonicFunctions are increasing, or decreasing if they are mapped to false, on their whole domain,
+// so their results are bounded by results for bounds of intervals
+var monotonicFunctions = map[string]bool{
+	"exp": true, "ln": true, "log": true, "asin": true, "atan": true, "sinh": true, "tanh": true,
+	"acos": false,
+}
+
+func newIntervalArithmetic(mode calculator.Mode) (arithmetic, error) {
+	if mode.Rounding != "" {
+		return nil, errors.NewInputError("Rounding of interval mode is always outward")
+	}
+
+	if mode.Precision == 0 && mode.Digits == 0 {
+		mode.Digits = defaultIntervalDigits
+	}
+
+	float, err := newBigFloatArithmetic(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return intervalArithmetic{float.(bigFloatArithmetic)}, nil
+}
+
+// down returns float rounding results of operations toward negative infinity
+func (a intervalArithmetic) down() *big.Float {
+	return new(big.Float).SetPrec(a.float.precision).SetMode(big.ToNegativeInf)
+}
+
+// up returns float rounding results of operations toward positive infinity
+func (a intervalArithmetic) up() *big.Float {
+	return new(big.Float).SetPrec(a.float.precision).SetMode(big.ToPositiveInf)
+}
+
+// precise returns arithmetic calculating functions with guard bits, which are enclosed in intervals
+func (a intervalArithmetic) precise() bigFloatArithmetic {
+	return bigFloatArithmetic{precision: a.float.precision + guardBits, digits: a.float.digits, rounding: big.ToNearestEven}
+}
+
+// checked returns interval with finite bounds
+func (a intervalArithmetic) checked(lower, upper *big.Float) (interface{}, error) {
+	if lower.IsInf() || upper.IsInf() {
+		return nil, errors.NewCalculationError("result is too large")
+	}
+
+	return interval{lower, upper}, nil
+}
+
+// enclose returns interval enclosing result of function calculated with guard bits, its error is assumed
+// to be lower than half of the guard bits
+func (a intervalArithmetic) enclose(f *big.Float) (interval, error) {
+	if f.IsInf() {
+		return interval{}, errors.NewCalculationError("result is too large")
+	}
+
+	if f.Sign() == 0 {
+		return interval{a.down(), a.up()}, nil
+	}
+
+	e := new(big.Float).SetMantExp(big.NewFloat(1), f.MantExp(nil)-int(a.float.precision)-guardBits/2)
+
+	return interval{a.down().Sub(f, e), a.up().Add(f, e)}, nil
+}
+
+// evaluate returns interval enclosing result of function for a single number
+func (a intervalArithmetic) evaluate(name string, x *big.Float) (interval, error) {
+	r, err := a.precise().function(name, x)
+	if err != nil {
+		return interval{}, err
+	}
+
+	return a.enclose(r.(*big.Float))
+}
+
+func (a intervalArithmetic) number(item lexer.Item) (interface{}, error) {
+	switch item.GetString() {
+	case "pi", "e":
+		f, err := a.precise().number(item)
+		if err != nil {
+			return nil, err
+		}
+		return a.enclose(f.(*big.Float))
+	}
+
+	lower, ok := a.down().SetString(item.GetString())
+	if !ok {
+		return nil, errors.NewParsingError(fmt.Sprintf("could not parse %s as a number", item.GetString()))
+	}
+
+	upper, _ := a.up().SetString(item.GetString())
+
+	return interval{lower, upper}, nil
+}
+
+func (a intervalArithmetic) operator(operator string, x, y interface{}) (interface{}, error) {
+	f, g := x.(interval), y.(interval)
+
+	switch operator {
+	case intervalOperator:
+		if f.lower.Cmp(g.upper) > 0 {
+			return nil, errors.NewCalculationError("lower bound of interval is greater than its upper bound")
+		}
+		return interval{f.lower, g.upper}, nil
+	case plusMinusOperator:
+		if g.lower.Sign() < 0 {
+			return nil, errors.NewCalculationError("tolerance has to be non-negative")
+		}
+		return a.checked(a.down().Sub(f.lower, g.upper), a.up().Add(f.upper, g.upper))
+	case "+":
+		return a.checked(a.down().Add(f.lower, g.lower), a.up().Add(f.upper, g.upper))
+	case "-":
+		return a.checked(a.down().Sub(f.lower, g.upper), a.up().Sub(f.upper, g.lower))
+	case "*":
+		return a.extremes(f, g, (*big.Float).Mul)
+	case "/":
+		if g.lower.Sign() <= 0 && g.upper.Sign() >= 0 {
+			return nil, errors.NewCalculationError(fmt.Sprintf("division by interval %s containing zero", a.format(g)))
+		}
+		return a.extremes(f, g, (*big.Float).Quo)
+	case "^":
+		return a.power(f, g)
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown operator: %s", operator))
+	}
+}
+
+// extremes returns interval of results of operation for all combinations of bounds, it encloses results
+// of multiplication and division which are monotonic in both operands
+func (a intervalArithmetic) extremes(f, g interval, operation func(z, x, y *big.Float) *big.Float) (interface{}, error) {
+	var lower, upper *big.Float
+
+	for _, x := range []*big.Float{f.lower, f.upper} {
+		for _, y := range []*big.Float{g.lower, g.upper} {
+			if l := operation(a.down(), x, y); lower == nil || l.Cmp(lower) < 0 {
+				lower = l
+			}
+			if u := operation(a.up(), x, y); upper == nil || u.Cmp(upper) > 0 {
+				upper = u
+			}
+		}
+	}
+
+	return a.checked(lower, upper)
+}
+
+// power calculates integer powers by repeated multiplication, other powers are calculated as exp(y ln x)
+func (a intervalArithmetic) power(f, g interval) (interface{}, error) {
+	if g.lower.Cmp(g.upper) == 0 && g.lower.IsInt() {
+		n, accuracy := g.lower.Int64()
+		if accuracy != big.Exact {
+			return nil, errors.NewCalculationError("result is too large")
+		}
+		return a.integerPower(f, n)
+	}
+
+	if f.lower.Sign() <= 0 {
+		return nil, errors.NewCalculationError("non-integer power of interval containing non-positive numbers")
+	}
+
+	logarithm, err := a.function("ln", f)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := a.operator("*", g, logarithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.function("exp", exponent)
+}
+
+func (a intervalArithmetic) integerPower(f interval, n int64) (interface{}, error) {
+	switch {
+	case n == 0:
+		return interval{a.down().SetInt64(1), a.up().SetInt64(1)}, nil
+	case n < 0:
+		power, err := a.integerPower(f, -n)
+		if err != nil {
+			return nil, err
+		}
+		return a.operator("/", interval{a.down().SetInt64(1), a.up().SetInt64(1)}, power)
+	case n%2 != 0 || f.lower.Sign() >= 0:
+		return a.checked(a.powerBound(f.lower, n, false), a.powerBound(f.upper, n, true))
+	case f.upper.Sign() <= 0:
+		return a.checked(a.powerBound(f.upper, n, false), a.powerBound(f.lower, n, true))
+	default:
+		upper := a.powerBound(f.lower, n, true)
+		if u := a.powerBound(f.upper, n, true); u.Cmp(upper) > 0 {
+			upper = u
+		}
+		return a.checked(a.down(), upper)
+	}
+}
+
+// powerBound returns x^n rounded toward positive infinity if up is true, otherwise toward negative infinity,
+// powers of non-negative numbers are calculated by repeated squaring rounded in the same direction
+func (a intervalArithmetic) powerBound(x *big.Float, n int64, up bool) *big.Float {
+	if x.Sign() < 0 {
+		odd := n%2 != 0
+		bound := a.powerBound(new(big.Float).Neg(x), n, up != odd)
+		if odd {
+			bound.Neg(bound)
+		}
+		return bound
+	}
+
+	newFloat := a.down
+	if up {
+		newFloat = a.up
+	}
+
+	result := newFloat().SetInt64(1)
+	base := newFloat().Set(x)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result.Mul(result, base)
+		}
+		if n > 1 {
+			base.Mul(base, base)
+		}
+	}
+
+	return result
+}
+
+func (a intervalArithmetic) negate(x interface{}) (interface{}, error) {
+	f := x.(interval)
+
+	return interval{new(big.Float).Neg(f.upper), new(big.Float).Neg(f.lower)}, nil
+}
+
+func (a intervalArithmetic) function(name string, x interface{}) (interface{}, error) {
+	f := x.(interval)
+
+	if increasing, ok := monotonicFunctions[name]; ok {
+		lower, err := a.evaluate(name, f.lower)
+		if err != nil {
+			return nil, err
+		}
+
+		upper, err := a.evaluate(name, f.upper)
+		if err != nil {
+			return nil, err
+		}
+
+		if !increasing {
+			lower, upper = upper, lower
+		}
+		return a.checked(lower.lower, upper.upper)
+	}
+
+	switch name {
+	case "sqrt":
+		if f.lower.Sign() < 0 {
+			return nil, errors.NewCalculationError("square root of negative number")
+		}
+		return interval{a.down().Sqrt(f.lower), a.up().Sqrt(f.upper)}, nil
+	case "re", "conj":
+		return f, nil
+	case "im":
+		return interval{a.down(), a.up()}, nil
+	case "floor", "ceil":
+		lower, _ := a.float.function(name, f.lower)
+		upper, _ := a.float.function(name, f.upper)
+		return interval{lower.(*big.Float), upper.(*big.Float)}, nil
+	case "abs":
+		switch {
+		case f.lower.Sign() >= 0:
+			return f, nil
+		case f.upper.Sign() <= 0:
+			return a.negate(f)
+		}
+		upper := new(big.Float).Neg(f.lower)
+		if f.upper.Cmp(upper) > 0 {
+			upper = f.upper
+		}
+		return interval{a.down(), upper}, nil
+	case "arg":
+		switch {
+		case f.lower.Sign() >= 0:
+			return interval{a.down(), a.up()}, nil
+		case f.upper.Sign() < 0:
+			return a.number(lexer.NewItem(lexer.Constant, "pi"))
+		}
+		pi, _ := a.enclose(bigmath.Pi(a.float.precision + guardBits))
+		return interval{a.down(), pi.upper}, nil
+	case "cosh":
+		return a.cosh(f)
+	case "sin", "cos":
+		return a.periodic(name, f)
+	case "tan":
+		return a.tangent(f)
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown function: %s", name))
+	}
+}
+
+// cosh is decreasing for negative and increasing for positive numbers, its minimum is cosh(0) = 1
+func (a intervalArithmetic) cosh(f interval) (interface{}, error) {
+	lower, err := a.evaluate("cosh", f.lower)
+	if err != nil {
+		return nil, err
+	}
+
+	upper, err := a.evaluate("cosh", f.upper)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case f.lower.Sign() >= 0:
+		return a.checked(lower.lower, upper.upper)
+	case f.upper.Sign() <= 0:
+		return a.checked(upper.lower, lower.upper)
+	}
+
+	if lower.upper.Cmp(upper.upper) > 0 {
+		upper = lower
+	}
+	return a.checked(a.down().SetInt64(1), upper.upper)
+}
+
+// quadrants returns indices of multiples of pi / 2 not greater than bounds of interval, they are not
+// found for bounds too large for precision
+func (a intervalArithmetic) quadrants(f interval) (int64, int64, bool) {
+	precise := a.precise()
+	halfPi := bigmath.Pi(precise.precision)
+	halfPi.Quo(halfPi, big.NewFloat(2))
+
+	indices := []int64{}
+	for _, x := range []*big.Float{f.lower, f.upper} {
+		floor, err := precise.function("floor", new(big.Float).SetPrec(precise.precision).Quo(x, halfPi))
+		if err != nil {
+			return 0, 0, false
+		}
+
+		index, accuracy := floor.(*big.Float).Int64()
+		if accuracy != big.Exact || index > 1<<40 || index < -(1<<40) {
+			return 0, 0, false
+		}
+		indices = append(indices, index)
+	}
+
+	return indices[0], indices[1], true
+}
+
+// periodic returns interval of sine or cosine, it is bounded by results for bounds of interval
+// and by extremes at multiples of pi / 2 inside interval
+func (a intervalArithmetic) periodic(name string, f interval) (interface{}, error) {
+	minusOne, one := a.down().SetInt64(-1), a.up().SetInt64(1)
+
+	first, last, ok := a.quadrants(f)
+	if !ok || last-first >= 4 {
+		return interval{minusOne, one}, nil
+	}
+
+	lower, err := a.evaluate(name, f.lower)
+	if err != nil {
+		return nil, err
+	}
+
+	upper, err := a.evaluate(name, f.upper)
+	if err != nil {
+		return nil, err
+	}
+
+	result := interval{lower.lower, upper.upper}
+	if upper.lower.Cmp(result.lower) < 0 {
+		result.lower = upper.lower
+	}
+	if lower.upper.Cmp(result.upper) > 0 {
+		result.upper = lower.upper
+	}
+
+	// sine has maximum at pi / 2 and minimum at 3 pi / 2, cosine at 0 and pi
+	maximum := int64(1)
+	if name == "cos" {
+		maximum = 0
+	}
+
+	for k := first + 1; k <= last; k++ {
+		switch (k%4 + 4) % 4 {
+		case maximum:
+			result.upper = one
+		case (maximum + 2) % 4:
+			result.lower = minusOne
+		}
+	}
+
+	if result.lower.Cmp(minusOne) < 0 {
+		result.lower = minusOne
+	}
+	if result.upper.Cmp(one) > 0 {
+		result.upper = one
+	}
+
+	return result, nil
+}
+
+// tangent is increasing between its poles at odd multiples of pi / 2
+func (a intervalArithmetic) tangent(f interval) (interface{}, error) {
+	first, last, ok := a.quadrants(f)
+	if !ok || last-first >= 2 || (last > first && last%2 != 0) {
+		return nil, errors.NewCalculationError("tangent of interval containing its pole is undefined")
+	}
+
+	lower, err := a.evaluate("tan", f.lower)
+	if err != nil {
+		return nil, err
+	}
+
+	upper, err := a.evaluate("tan", f.upper)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.checked(lower.lower, upper.upper)
+}
+
+func (a intervalArithmetic) value(x interface{}) (calculator.Value, error) {
+	return intervalValue{x.(interval), a.float.digits}, nil
+}
+
+func (a intervalArithmetic) format(f interval) string {
+	return intervalValue{f, a.float.digits}.String()
+}
+
+// Float64 returns midpoint of interval
+func (v intervalValue) Float64() float64 {
+	lower, upper := v.Bounds()
+
+	return lower/2 + upper/2
+}
+
+// Bounds returns bounds of interval rounded outward to float64
+func (v intervalValue) Bounds() (float64, float64) {
+	lower, _ := new(big.Float).SetPrec(53).SetMode(big.ToNegativeInf).Set(v.lower).Float64()
+	upper, _ := new(big.Float).SetPrec(53).SetMode(big.ToPositiveInf).Set(v.upper).Float64()
+
+	return lower, upper
+}
+
+// String writes interval with bounds rounded outward to significant digits, e.g. [1.9, 2.1]
+func (v intervalValue) String() string {
+	return fmt.Sprintf("[%s, %s]", formatBound(v.lower, v.digits, false), formatBound(v.upper, v.digits, true))
+}
+
+// formatBound writes bound rounded to significant digits toward positive infinity if up is true, otherwise
+// toward negative infinity, so written interval encloses the calculated one
+func formatBound(x *big.Float, digits int, up bool) string {
+	scientific := x.Text('e', digits-1)
+
+	written, _ := new(big.Rat).SetString(scientific)
+	exact, _ := x.Rat(nil)
+	if c := written.Cmp(exact); (up && c < 0) || (!up && c > 0) {
+		_, exponentText, _ := strings.Cut(scientific, "e")
+		exponent, _ := strconv.Atoi(exponentText)
+
+		// last written digit is moved away from the bound
+		scale := exponent - digits + 1
+		unit := new(big.Rat).SetInt(pow10(max(scale, 0)))
+		if scale < 0 {
+			unit.SetFrac(big.NewInt(1), pow10(-scale))
+		}
+
+		if up {
+			written.Add(written, unit)
+		} else {
+			written.Sub(written, unit)
+		}
+		scientific = new(big.Float).SetPrec(uint(digits)*4+64).SetRat(written).Text('e', digits-1)
+	}
+
+	return formatDecimal(scientific, digits)
+}
@@ -0,0 +1,116 @@
+package reversepolish
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestCalculateInterval(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		mode          calculator.Mode
+		expected      string
+		expectedError error
+	}{
+		{"Tolerance stack-up", "[1.9, 2.1] * 3", calculator.Mode{}, "[5.69999999999999, 6.30000000000001]", nil},
+		{"Plus-minus", "5 ± 0.2", calculator.Mode{Digits: 5}, "[4.7999, 5.2001]", nil},
+		{"Plus-minus has precedence of addition", "2 * 5 ± 0.1 - 1", calculator.Mode{Digits: 5}, "[8.8999, 9.1001]", nil},
+		{"Subtraction of dependent intervals", "[1, 2] - [1, 2]", calculator.Mode{}, "[-1, 1]", nil},
+		{"Multiplication of intervals with mixed signs", "[-1, 2] * [-3, 4]", calculator.Mode{}, "[-6, 8]", nil},
+		{"Division", "1 / [2, 4]", calculator.Mode{}, "[0.25, 0.5]", nil},
+		{"Ratio of toleranced values", "(10 ± 0.1) / (5 ± 0.1)", calculator.Mode{Digits: 6}, "[1.94117, 2.06123]", nil},
+		{"Even power of interval containing zero", "[-2, 3] ^ 2", calculator.Mode{}, "[0, 9]", nil},
+		{"Odd power", "[-2, 3] ^ 3", calculator.Mode{}, "[-8, 27]", nil},
+		{"Negative power", "[1, 2] ^ -1", calculator.Mode{}, "[0.5, 1]", nil},
+		{"Square root", "sqrt([4, 9])", calculator.Mode{}, "[2, 3]", nil},
+		{"Absolute value", "abs([-3, 2])", calculator.Mode{}, "[0, 3]", nil},
+		{"Maximum of cosine", "cos([0, 2])", calculator.Mode{Digits: 6}, "[-0.416147, 1]", nil},
+		{"Sine over half period", "sin([1, 3])", calculator.Mode{Digits: 6}, "[0.14112, 1]", nil},
+		{"Tangent", "tan([0, 1])", calculator.Mode{Digits: 6}, "[0, 1.55741]", nil},
+		{"Decreasing function", "acos([0, 1])", calculator.Mode{Digits: 6}, "[0, 1.5708]", nil},
+		{"Implied multiplication", "2[1, 2]", calculator.Mode{}, "[2, 4]", nil},
+		{"Division by interval containing zero", "1 / [-1, 1]", calculator.Mode{}, "", errors.NewCalculationError("division by interval [-1, 1] containing zero")},
+		{"Tangent over pole", "tan([1, 2])", calculator.Mode{}, "", errors.NewCalculationError("tangent of interval containing its pole is undefined")},
+		{"Square root of negative numbers", "sqrt([-1, 1])", calculator.Mode{}, "", errors.NewCalculationError("square root of negative number")},
+		{"Reversed bounds", "[2, 1]", calculator.Mode{}, "", errors.NewCalculationError("lower bound of interval is greater than its upper bound")},
+		{"Negative tolerance", "5 ± -1", calculator.Mode{}, "", errors.NewCalculationError("tolerance has to be non-negative")},
+		{"Rounding", "[1, 2]", calculator.Mode{Rounding: "floor"}, "", errors.NewInputError("Rounding of interval mode is always outward")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			tt.mode.Name = calculator.IntervalMode
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), tt.mode)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if value.String() != tt.expected {
+				t.Errorf("expected result to be %s, got %s", tt.expected, value.String())
+			}
+		})
+	}
+}
+
+func TestIntervalBounds(t *testing.T) {
+	operation, err := ParseInfix(context.Background(), "0.1 + 0.2")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), calculator.Mode{Name: calculator.IntervalMode})
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	lower, upper := value.(calculator.IntervalValue).Bounds()
+	exact := big.NewRat(3, 10)
+	if new(big.Rat).SetFloat64(lower).Cmp(exact) > 0 || new(big.Rat).SetFloat64(upper).Cmp(exact) < 0 {
+		t.Errorf("expected bounds to enclose 0.3, got [%v, %v]", lower, upper)
+	}
+}
+
+func TestFormatBound(t *testing.T) {
+	tests := []struct {
+		value    string
+		up       bool
+		expected string
+	}{
+		{"1.23456", false, "1.234"},
+		{"1.23456", true, "1.235"},
+		{"1.2341", true, "1.235"},
+		{"-1.23456", false, "-1.235"},
+		{"9.9999", true, "10"},
+		{"0.000123456", false, "0.0001234"},
+		{"1.5", false, "1.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			x, _ := new(big.Float).SetPrec(200).SetString(tt.value)
+
+			if result := formatBound(x, 4, tt.up); result != tt.expected {
+				t.Errorf("expected bound to be %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
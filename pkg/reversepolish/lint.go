@@ -0,0 +1,232 @@
+package reversepolish
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// Codes of warnings returned by linter. There is no warning about exact equality of floats,
+// operations have no comparison operators so floats are never compared.
+const (
+	NegativePowerWarning        = "negative-power"
+	AmbiguousDivisionWarning    = "ambiguous-division"
+	RedundantParenthesesWarning = "redundant-parentheses"
+	DivisionByZeroWarning       = "division-by-zero"
+	HugeExponentWarning         = "huge-exponent"
+)
+
+// hugeExponent is the lowest absolute value of literal exponent which is likely to overflow or underflow
+const hugeExponent = 1000
+
+type linter struct {
+	lexers Lexers
+}
+
+// NewLinter returns linter of operations lexed by provided lexers, notations other than infix
+// are generated by lexers so only risky constructs, not the way they are written, are linted
+func NewLinter(lexers Lexers) calculator.Linter {
+	return linter{lexers: lexers}
+}
+
+// Lint lexes operation written in notation, parses it and returns warnings about it
+func (l linter) Lint(ctx context.Context, notation string, input string) ([]calculator.Warning, error) {
+	lex, err := l.lexers.lexer(notation)
+	if err != nil {
+		return nil, err
+	}
+
+	if notation == "" || notation == calculator.InfixNotation {
+		return Lint(ctx, lex(input))
+	}
+
+	items, err := parseItems(lex(input))
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := buildTree(items)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.lint(), nil
+}
+
+// Lint parses infix items returned by lexer and returns warnings about ambiguous or risky constructs
+func Lint(_ context.Context, l lexer.Lexer) ([]calculator.Warning, error) {
+	recorder := &recordingLexer{next: l}
+
+	items, groups, err := parseGroupedItems(recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := buildTree(items)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := lintNegativePowers(recorder.items)
+	warnings = append(warnings, lintAmbiguousDivisions(recorder.items)...)
+	warnings = append(warnings, tree.lintRedundantParentheses(groups)...)
+	warnings = append(warnings, tree.lint()...)
+
+	return warnings, nil
+}
+
+// lintNegativePowers warns about -2^2 which is calculated as -(2^2)
+func lintNegativePowers(items []lexer.Item) []calculator.Warning {
+	warnings := []calculator.Warning{}
+	previous := lexer.NewEmptyItem()
+
+	for k, i := range items {
+		if k+2 < len(items) && i.GetType() == lexer.Subtraction && expectsOperand(previous) && isNumber(items[k+1]) && items[k+2].GetType() == lexer.Exponent {
+			warnings = append(warnings, calculator.Warning{
+				Code:    NegativePowerWarning,
+				Message: fmt.Sprintf("-%s ^ ... is calculated as -(%s ^ ...), use parentheses to make it explicit", items[k+1].GetString(), items[k+1].GetString()),
+			})
+		}
+		previous = i
+	}
+
+	return warnings
+}
+
+// lintAmbiguousDivisions warns about a/b*c, which is calculated as (a/b)*c, but is often meant as a/(b*c)
+func lintAmbiguousDivisions(items []lexer.Item) []calculator.Warning {
+	warnings := []calculator.Warning{}
+	previous := lexer.NewEmptyItem()
+	lastOperators := []lexer.Item{lexer.NewEmptyItem()} // last operator of precedence lower than exponent for every parentheses depth
+
+	for _, i := range items {
+		if impliesMultiplication(previous, i) {
+			i := lexer.NewItem(lexer.Multiplication, "*")
+			warnings = append(warnings, lintMultiplication(lastOperators[len(lastOperators)-1], i)...)
+			lastOperators[len(lastOperators)-1] = i
+		}
+
+		binary := isMathOperator(i) && !(isSign(i) && expectsOperand(previous))
+
+		switch {
+		case isLeftBracket(i):
+			lastOperators = append(lastOperators, lexer.NewEmptyItem())
+		case isRightBracket(i) && len(lastOperators) > 1:
+			lastOperators = lastOperators[:len(lastOperators)-1]
+		case binary && i.GetType() != lexer.Exponent:
+			warnings = append(warnings, lintMultiplication(lastOperators[len(lastOperators)-1], i)...)
+			lastOperators[len(lastOperators)-1] = i
+		}
+
+		previous = i
+	}
+
+	return warnings
+}
+
+func lintMultiplication(lastOperator lexer.Item, i lexer.Item) []calculator.Warning {
+	if lastOperator.GetType() != lexer.Division || i.GetType() != lexer.Multiplication {
+		return nil
+	}
+
+	return []calculator.Warning{{
+		Code:    AmbiguousDivisionWarning,
+		Message: "a / b * c is calculated as (a / b) * c, use parentheses to make it explicit",
+	}}
+}
+
+// lintRedundantParentheses warns about parentheses which are not required by precedence and associativity
+// of operators, groups are positions of nodes in postfix order of the tree which were parenthesized
+func (n *node) lintRedundantParentheses(groups []int) []calculator.Warning {
+	warnings := []calculator.Warning{}
+	nodes := n.postfix()
+	parents := n.parents()
+	parenthesized := map[int]bool{}
+
+	for _, g := range groups {
+		child := nodes[g]
+		parent, ok := parents[child]
+
+		// parentheses around function arguments, interval bounds or the whole operation are never required
+		redundant := parenthesized[g] || !ok || isFunction(parent.item) || parent.item.GetType() == lexer.Interval
+		if !redundant {
+			redundant = !needsParentheses(parent.item, child, parent.children[0] == child && !isNegation(parent.item))
+		}
+		parenthesized[g] = true
+
+		if redundant {
+			warnings = append(warnings, calculator.Warning{
+				Code:    RedundantParenthesesWarning,
+				Message: fmt.Sprintf("parentheses around %s are redundant", child.format()),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// postfix returns nodes of the tree in postfix order, the same order as items the tree was built from
+func (n *node) postfix() []*node {
+	nodes := []*node{}
+	for _, c := range n.children {
+		nodes = append(nodes, c.postfix()...)
+	}
+
+	return append(nodes, n)
+}
+
+// parents maps nodes of the tree to their parents
+func (n *node) parents() map[*node]*node {
+	parents := map[*node]*node{}
+	for _, p := range n.postfix() {
+		for _, c := range p.children {
+			parents[c] = p
+		}
+	}
+
+	return parents
+}
+
+// lint returns warnings about risky constructs in the tree
+func (n *node) lint() []calculator.Warning {
+	warnings := []calculator.Warning{}
+
+	switch {
+	case n.item.GetType() == lexer.Division && n.children[1].isLiteral() && n.children[1].literalValue() == 0:
+		warnings = append(warnings, calculator.Warning{
+			Code:    DivisionByZeroWarning,
+			Message: fmt.Sprintf("division by zero in %s", n.format()),
+		})
+	case n.item.GetType() == lexer.Exponent && n.children[1].isLiteral() && math.Abs(n.children[1].literalValue()) >= hugeExponent:
+		warnings = append(warnings, calculator.Warning{
+			Code:    HugeExponentWarning,
+			Message: fmt.Sprintf("exponent of %s is huge, result may overflow or underflow", n.format()),
+		})
+	}
+
+	for _, c := range n.children {
+		warnings = append(warnings, c.lint()...)
+	}
+
+	return warnings
+}
+
+// isLiteral returns true for numbers, possibly negated
+func (n *node) isLiteral() bool {
+	if isNegation(n.item) {
+		return n.children[0].isLiteral()
+	}
+
+	return isNumber(n.item)
+}
+
+func (n *node) literalValue() float64 {
+	if isNegation(n.item) {
+		return -n.children[0].literalValue()
+	}
+
+	return getNumericValue(n.item)
+}
@@ -0,0 +1,78 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expectedCodes []string
+		expectedError error
+	}{
+		{"No warnings", "(1 + 2) * 3 - 2 ^ -1 + 2 * (3 / 4)", []string{}, nil},
+		{"Negative power", "-2^2 + (-2)^2 + 1 - 2^2", []string{NegativePowerWarning}, nil},
+		{"Ambiguous division", "1 / 2 * 3", []string{AmbiguousDivisionWarning}, nil},
+		{"Ambiguous division implicit multiplication", "1 / 2x", []string{AmbiguousDivisionWarning}, nil},
+		{"Ambiguous division after power", "1 / 2 ^ 2 * 3", []string{AmbiguousDivisionWarning}, nil},
+		{"Division separated by addition", "1 / 2 + 3 * 4", []string{}, nil},
+		{"Redundant parentheses", "((1 + 2)) + (3 * 4)", []string{RedundantParenthesesWarning, RedundantParenthesesWarning, RedundantParenthesesWarning}, nil},
+		{"Required parentheses", "-(x + 1) + 2(x + 1) + (x - 1)y + [(1), 2] * (2 - 1) ^ 2", []string{RedundantParenthesesWarning}, nil},
+		{"Redundant parentheses of function argument and negation", "sin((x)) - (2 ^ 2)", []string{RedundantParenthesesWarning, RedundantParenthesesWarning}, nil},
+		{"Division by zero", "1 / -0 + 1 / (0)", []string{RedundantParenthesesWarning, DivisionByZeroWarning, DivisionByZeroWarning}, nil},
+		{"Huge exponent", "10 ^ 5000", []string{HugeExponentWarning}, nil},
+		{"Parsing error", "(1 + 2", nil, errors.NewParsingError("mismatched parantheses")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := Lint(context.Background(), lexer.Lex(tt.input))
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			var codes []string
+			if warnings != nil {
+				codes = []string{}
+			}
+			for _, w := range warnings {
+				codes = append(codes, w.Code)
+			}
+
+			if !cmp.Equal(tt.expectedCodes, codes) {
+				t.Errorf("expected warnings to be %v, got %v", tt.expectedCodes, warnings)
+			}
+		})
+	}
+}
+
+func TestLinter(t *testing.T) {
+	l := NewLinter(Lexers{calculator.InfixNotation: lexer.Lex})
+
+	warnings, err := l.Lint(context.Background(), "", "(1 / 0)")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	expected := []calculator.Warning{
+		{Code: RedundantParenthesesWarning, Message: "parentheses around 1 / 0 are redundant"},
+		{Code: DivisionByZeroWarning, Message: "division by zero in 1 / 0"},
+	}
+	if !cmp.Equal(expected, warnings) {
+		t.Errorf("expected warnings to be %v, got %v", expected, warnings)
+	}
+}
@@ -0,0 +1,167 @@
+package reversepolish
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// arithmetic calculates operations on numbers of a mode, numbers are values of types known only to the arithmetic
+type arithmetic interface {
+	number(item lexer.Item) (interface{}, error)
+	operator(operator string, a, b interface{}) (interface{}, error)
+	negate(a interface{}) (interface{}, error)
+	function(name string, a interface{}) (interface{}, error)
+	value(a interface{}) (calculator.Value, error)
+}
+
+// namedArithmetic resolves names which are not constants nor variables in the mode, e.g. imaginary unit
+type namedArithmetic interface {
+	name(name string) (interface{}, bool)
+}
+
+// convertingArithmetic converts values to units of other values, e.g. 5 km in m
+type convertingArithmetic interface {
+	convert(a, b interface{}) (interface{}, error)
+}
+
+// negatingArithmetic rounds negated numbers written in operations, e.g. -2.341, as a whole, numbers rounded
+// before negation would be rounded in the wrong direction by directed rounding modes
+type negatingArithmetic interface {
+	negativeNumber(item lexer.Item) (interface{}, error)
+}
+
+// literalArithmetic calculates with dates, durations and time zones written in operations, e.g. 2026-10-17
+type literalArithmetic interface {
+	literal(item lexer.Item) (interface{}, error)
+}
+
+// arithmetics creates arithmetics of modes supported by operations
+var arithmetics = map[string]func(calculator.Mode) (arithmetic, error){
+	calculator.BigFloatMode:           newBigFloatArithmetic,
+	calculator.RationalMode:           newRationalArithmetic,
+	calculator.DecimalMode:            newDecimalArithmetic,
+	calculator.ComplexMode:            newComplexArithmetic,
+	calculator.IntervalMode:           newIntervalArithmetic,
+	calculator.SignificantFiguresMode: newSignificantArithmetic,
+	calculator.UnitsMode:              newUnitsArithmetic,
+	calculator.TimeMode:               newTimeArithmetic,
+}
+
+// roundingModes maps names of rounding modes accepted in modes to rounding of big.Float
+var roundingModes = map[string]big.RoundingMode{
+	"":          big.ToNearestEven,
+	"half-even": big.ToNearestEven,
+	"half-up":   big.ToNearestAway,
+	"down":      big.ToZero,
+	"up":        big.AwayFromZero,
+	"floor":     big.ToNegativeInf,
+	"ceiling":   big.ToPositiveInf,
+}
+
+func roundingMode(name string) (big.RoundingMode, error) {
+	rounding, ok := roundingModes[name]
+	if !ok {
+		return 0, errors.NewInputError(fmt.Sprintf("Unknown rounding mode: %s", name))
+	}
+
+	return rounding, nil
+}
+
+// CalculateMode calculates operation with numbers of the mode instead of float64
+func (o rpnOperation) CalculateMode(_ context.Context, mode calculator.Mode) (calculator.Value, error) {
+	newArithmetic, ok := arithmetics[mode.Name]
+	if !ok {
+		return nil, errors.NewInputError(fmt.Sprintf("Unknown mode: %s", mode.Name))
+	}
+
+	a, err := newArithmetic(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	stack := []interface{}{}
+	pop := func() interface{} {
+		operand := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return operand
+	}
+
+	for k := 0; k < len(o.items); k++ {
+		i := o.items[k]
+		var r interface{}
+
+		switch {
+		case isMathOperator(i), isIntervalOperator(i), isConversion(i):
+			if len(stack) < 2 {
+				return nil, errors.NewCalculationError("not enough operands on stack")
+			}
+
+			operand2 := pop()
+			operand1 := pop()
+
+			if isConversion(i) {
+				converting, ok := a.(convertingArithmetic)
+				if !ok {
+					return nil, errors.NewCalculationError("units can be converted only in units mode")
+				}
+				r, err = converting.convert(operand1, operand2)
+				break
+			}
+
+			r, err = a.operator(i.GetString(), operand1, operand2)
+		case isNegation(i), isFunction(i):
+			if len(stack) < 1 {
+				return nil, errors.NewCalculationError("not enough operands on stack")
+			}
+
+			if isNegation(i) {
+				r, err = a.negate(pop())
+				break
+			}
+
+			r, err = a.function(i.GetString(), pop())
+		case isNumber(i):
+			negating, ok := a.(negatingArithmetic)
+			if !ok || k+1 == len(o.items) || !isNegation(o.items[k+1]) {
+				r, err = a.number(i)
+				break
+			}
+
+			r, err = negating.negativeNumber(i)
+			k++
+		case isTimeLiteral(i):
+			literal, ok := a.(literalArithmetic)
+			if !ok {
+				return nil, errors.NewCalculationError("dates, durations and time zones can be calculated only in time mode")
+			}
+			r, err = literal.literal(i)
+		case isVariable(i):
+			named, ok := a.(namedArithmetic)
+			if !ok {
+				return nil, errors.NewCalculationError(fmt.Sprintf("unbound variable: %s", i.GetString()))
+			}
+
+			if r, ok = named.name(i.GetString()); !ok {
+				return nil, errors.NewCalculationError(fmt.Sprintf("unbound variable: %s", i.GetString()))
+			}
+		default:
+			return nil, errors.NewCalculationError(fmt.Sprintf("invalid item in the RPN operation: %s", i.GetString()))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, r)
+	}
+
+	if len(stack) != 1 {
+		return nil, errors.NewCalculationError("too many operands on the stack at the end of calculation")
+	}
+
+	return a.value(stack[0])
+}
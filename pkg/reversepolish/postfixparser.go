@@ -28,67 +28,195 @@ type operatorsStack struct {
 
 type precedenceLevel int // higher the number higher the precedence
 
+// impliedMultiplication is multiplication implied by number followed by name, e.g. 10 km, it binds tighter
+// than multiplication and division written explicitly, so 10 km / 2 h is (10 km) / (2 h)
+type impliedMultiplication struct {
+	lexer.Item
+}
+
 // ParseInfix provides parsing of infix mathematical operations for postif calculator
-func ParseInfix(_ context.Context, input string) (calculator.OperationInterface, error) {
-	l := lexer.Lex(input)
+func ParseInfix(ctx context.Context, input string) (calculator.OperationInterface, error) {
+	return ParseItems(ctx, lexer.Lex(input))
+}
+
+// ParseTime provides parsing of infix operations of time mode, which may contain dates, durations and time zones
+func ParseTime(ctx context.Context, input string) (calculator.OperationInterface, error) {
+	return ParseItems(ctx, lexer.LexTime(input))
+}
+
+// ParseItems provides parsing of infix items returned by any lexer for postfix calculator
+func ParseItems(_ context.Context, l lexer.Lexer) (calculator.OperationInterface, error) {
+	items, err := parseItems(l)
+	if err != nil {
+		return nil, err
+	}
 
+	return expandDerivatives(items)
+}
+
+// parseItems returns infix items in postfix order using shunting-yard algorithm
+func parseItems(l lexer.Lexer) ([]lexer.Item, error) {
+	items, _, err := parseGroupedItems(l)
+
+	return items, err
+}
+
+// parseGroupedItems returns infix items in postfix order and, for every pair of parentheses, position of the item
+// calculated last between them. Parentheses of function calls and parentheses implying multiplication are skipped
+func parseGroupedItems(l lexer.Lexer) ([]lexer.Item, []int, error) {
 	items := []lexer.Item{}
 	opStack := &operatorsStack{stack: []lexer.Item{}}
+	previous := lexer.NewEmptyItem()
+	arguments := []int{} // separators counted for every open parenthesis
+	starts := []int{}    // count of output items at every open parenthesis, -1 if it is skipped
+	groups := []int{}
+	grouped := false // previous item closed recorded group
+
+	for i := l.NextItem(); !isEmpty(i); previous, i = i, l.NextItem() {
+		if isFunction(previous) && i.GetType() != lexer.LeftParenthesis {
+			return nil, nil, errors.NewParsingError(fmt.Sprintf("function %s has to be followed by parantheses", previous.GetString()))
+		}
+
+		implied := impliesMultiplication(previous, i)
+		if implied {
+			var operator lexer.Item = lexer.NewItem(lexer.Multiplication, "*")
+			if isNumber(previous) && isIdentifier(i) {
+				operator = impliedMultiplication{operator}
+			}
+			items = opStack.pushOperator(items, operator)
+			if grouped {
+				groups = groups[:len(groups)-1]
+			}
+		}
+		grouped = false
 
-	for i := l.NextItem(); !isEmpty(i); i = l.NextItem() {
 		switch {
 		case isError(i):
-			return nil, errors.NewParsingError(i.GetString())
+			return nil, nil, errors.NewParsingError(i.GetString())
 		case isNumber(i):
 			numItem, err := parseNumber(i)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			items = append(items, numItem)
-		case isMathOperator(i):
-			for {
-				pop := true
-				topItem := opStack.peek()
-
-				switch {
-				case isMathOperator(topItem) && (getPrecedenceLevel(topItem) > getPrecedenceLevel(i)):
-					//do nothing; pop = true
-				case isMathOperator(topItem) && (getPrecedenceLevel(topItem) == getPrecedenceLevel(i)) && isLeftAssociative(i):
-					//do nothing; pop = true
-				default:
-					pop = false
-				}
-
-				if pop == true {
-					opStack.pop()
-					items = append(items, topItem)
-				}
-
-				break
+		case isConstant(i):
+			numItem, err := parseConstant(i)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, numItem)
+		case isIdentifier(i):
+			items = append(items, parseIdentifier(i))
+		case isTimeLiteral(i):
+			items = append(items, i)
+		case isSign(i) && expectsOperand(previous):
+			if i.GetType() == lexer.Subtraction {
+				opStack.push(lexer.NewItem(lexer.Negation, i.GetString()))
+			}
+		case isNegation(i):
+			opStack.push(i)
+		case isFunction(i):
+			if !isKnownFunction(i) {
+				return nil, nil, errors.NewParsingError(fmt.Sprintf("unknown function: %s", i.GetString()))
 			}
 			opStack.push(i)
+		case isMathOperator(i) || isIntervalOperator(i) || isConversion(i):
+			items = opStack.pushOperator(items, i)
 		case isLeftBracket(i):
 			opStack.push(i)
+			arguments = append(arguments, 0)
+			if implied || isFunction(previous) || isSquareBracket(i) {
+				starts = append(starts, -1)
+			} else {
+				starts = append(starts, len(items))
+			}
+		case isSeparator(i):
+			for topItem := opStack.peek(); !isLeftBracket(topItem); topItem = opStack.peek() {
+				if isEmpty(topItem) {
+					return nil, nil, errors.NewParsingError("misplaced separator")
+				}
+				items = append(items, opStack.pop())
+			}
+			arguments[len(arguments)-1]++
 		case isRightBracket(i):
-			for poppedItem := opStack.pop(); !isLeftBracket(poppedItem); poppedItem = opStack.pop() {
+			poppedItem := opStack.pop()
+			for ; !isLeftBracket(poppedItem); poppedItem = opStack.pop() {
 				if isEmpty(poppedItem) {
-					return nil, errors.NewParsingError("mismatched parantheses")
+					return nil, nil, errors.NewParsingError("mismatched parantheses")
 				}
 				items = append(items, poppedItem)
 			}
+
+			if isSquareBracket(poppedItem) != isSquareBracket(i) {
+				return nil, nil, errors.NewParsingError("mismatched parantheses")
+			}
+
+			separators := arguments[len(arguments)-1]
+			arguments = arguments[:len(arguments)-1]
+			start := starts[len(starts)-1]
+			starts = starts[:len(starts)-1]
+
+			switch {
+			case isSquareBracket(i) && separators != 1:
+				return nil, nil, errors.NewParsingError("interval has to consist of lower and upper bound")
+			case isSquareBracket(i):
+				items = append(items, lexer.NewItem(lexer.Interval, intervalOperator))
+			case isFunction(opStack.peek()) && separators+1 != getArity(opStack.peek()):
+				return nil, nil, errors.NewParsingError(fmt.Sprintf("function %s takes %d arguments", opStack.peek().GetString(), getArity(opStack.peek())))
+			case isFunction(opStack.peek()):
+				items = append(items, opStack.pop())
+			case separators > 0:
+				return nil, nil, errors.NewParsingError("misplaced separator")
+			case start >= 0 && len(items) > start:
+				groups = append(groups, len(items)-1)
+				grouped = true
+			}
 		default:
-			return nil, errors.NewParsingError(fmt.Sprintf("invalid item returned from lexer: %s", i))
+			return nil, nil, errors.NewParsingError(fmt.Sprintf("invalid item returned from lexer: %s", i))
 		}
 	}
 
+	if isFunction(previous) {
+		return nil, nil, errors.NewParsingError(fmt.Sprintf("function %s has to be followed by parantheses", previous.GetString()))
+	}
+
 	for poppedItem := opStack.pop(); !isEmpty(poppedItem); poppedItem = opStack.pop() {
 		if isBracket(poppedItem) {
-			return nil, errors.NewParsingError("mismatched parantheses")
+			return nil, nil, errors.NewParsingError("mismatched parantheses")
 		}
 		items = append(items, poppedItem)
 	}
 
-	return &rpnOperation{items}, nil
+	return items, groups, nil
+}
+
+// shouldPopOperator decides if operator on top of operators stack has to be moved to output before pushing incoming operator
+func shouldPopOperator(topItem lexer.Item, incoming lexer.Item) bool {
+	if !isMathOperator(topItem) && !isNegation(topItem) && !isIntervalOperator(topItem) && !isConversion(topItem) {
+		return false
+	}
+
+	switch {
+	case getPrecedenceLevel(topItem) > getPrecedenceLevel(incoming):
+		return true
+	case getPrecedenceLevel(topItem) == getPrecedenceLevel(incoming) && isLeftAssociative(incoming):
+		return true
+	default:
+		return false
+	}
+}
+
+// impliesMultiplication returns true if operands are written next to each other, i.e. 2x or 2(x + 1)
+func impliesMultiplication(previous lexer.Item, next lexer.Item) bool {
+	endsOperand := isNumber(previous) || isIdentifier(previous) || isConstant(previous) || isRightBracket(previous)
+	startsOperand := isIdentifier(next) || isConstant(next) || isFunction(next) || isLeftBracket(next)
+
+	return endsOperand && startsOperand
+}
+
+// expectsOperand returns true if item following the previous one has to be an operand, i.e. + and - are signs, not operators
+func expectsOperand(previous lexer.Item) bool {
+	return isEmpty(previous) || isLeftBracket(previous) || isSeparator(previous) || isMathOperator(previous) || isNegation(previous) || isIntervalOperator(previous) || isConversion(previous)
 }
 
 func parseNumber(item lexer.Item) (numericItem, error) {
@@ -104,6 +232,24 @@ func parseNumber(item lexer.Item) (numericItem, error) {
 	return numericItem{item.GetString(), num}, nil
 }
 
+func parseConstant(item lexer.Item) (numericItem, error) {
+	value, ok := constants[item.GetString()]
+	if !ok {
+		return numericItem{}, errors.NewParsingError(fmt.Sprintf("unknown constant: %s", item.GetString()))
+	}
+
+	return numericItem{item.GetString(), value}, nil
+}
+
+// parseIdentifier resolves identifier to a constant if it is known, any other name is a variable
+func parseIdentifier(item lexer.Item) lexer.Item {
+	if value, ok := constants[item.GetString()]; ok {
+		return numericItem{item.GetString(), value}
+	}
+
+	return lexer.NewItem(lexer.Variable, item.GetString())
+}
+
 func (i numericItem) GetType() lexer.ItemType {
 	return lexer.Number
 }
@@ -131,6 +277,41 @@ func getNumericValue(item lexer.Item) float64 {
 	return 0.0
 }
 
+func isConstant(item lexer.Item) bool {
+	return item.GetType() == lexer.Constant
+}
+
+func isIdentifier(item lexer.Item) bool {
+	return item.GetType() == lexer.Identifier
+}
+
+func isVariable(item lexer.Item) bool {
+	return item.GetType() == lexer.Variable
+}
+
+func isNegation(item lexer.Item) bool {
+	return item.GetType() == lexer.Negation
+}
+
+func isFunction(item lexer.Item) bool {
+	return item.GetType() == lexer.Function
+}
+
+// isKnownFunction returns true for calculated functions and functions transformed while parsing
+func isKnownFunction(item lexer.Item) bool {
+	_, ok := functions[item.GetString()]
+
+	return ok || item.GetString() == derivativeFunction
+}
+
+func isSeparator(item lexer.Item) bool {
+	return item.GetType() == lexer.Separator
+}
+
+func isSign(item lexer.Item) bool {
+	return item.GetType() == lexer.Addition || item.GetType() == lexer.Subtraction
+}
+
 func isError(item lexer.Item) bool {
 	if item.GetType() == lexer.Error {
 		return true
@@ -157,7 +338,7 @@ func isBracket(item lexer.Item) bool {
 
 func isLeftBracket(item lexer.Item) bool {
 	switch typ := item.GetType(); {
-	case typ == lexer.LeftParenthesis:
+	case typ == lexer.LeftParenthesis || typ == lexer.LeftSquareBracket:
 		return true
 	default:
 		return false
@@ -166,16 +347,38 @@ func isLeftBracket(item lexer.Item) bool {
 
 func isRightBracket(item lexer.Item) bool {
 	switch typ := item.GetType(); {
-	case typ == lexer.RightParenthesis:
+	case typ == lexer.RightParenthesis || typ == lexer.RightSquareBracket:
 		return true
 	default:
 		return false
 	}
 }
 
+// isSquareBracket returns true for brackets of intervals, e.g. [1.9, 2.1]
+func isSquareBracket(item lexer.Item) bool {
+	return item.GetType() == lexer.LeftSquareBracket || item.GetType() == lexer.RightSquareBracket
+}
+
+// isIntervalOperator returns true for operators creating intervals from bounds or from value and tolerance
+func isIntervalOperator(item lexer.Item) bool {
+	return item.GetType() == lexer.Interval || item.GetType() == lexer.PlusMinus
+}
+
+// isConversion returns true for operators converting quantities to units, e.g. 5 km in m
+func isConversion(item lexer.Item) bool {
+	return item.GetType() == lexer.Conversion
+}
+
+// isTimeLiteral returns true for dates, durations and time zones written in operation, e.g. 2026-10-17
+func isTimeLiteral(item lexer.Item) bool {
+	return item.GetType() == lexer.Date || item.GetType() == lexer.Duration || item.GetType() == lexer.Zone
+}
+
 func isOperator(item lexer.Item) bool {
 	switch typ := item.GetType(); {
-	case typ == lexer.Number:
+	case typ == lexer.Number || typ == lexer.Date || typ == lexer.Duration || typ == lexer.Zone:
+		return false
+	case typ == lexer.Constant || typ == lexer.Identifier || typ == lexer.Variable || typ == lexer.Separator:
 		return false
 	case typ == lexer.Error:
 		return false
@@ -188,29 +391,47 @@ func isOperator(item lexer.Item) bool {
 
 func isMathOperator(item lexer.Item) bool {
 	switch typ := item.GetType(); {
-	case typ == lexer.Number:
-		return false
-	case typ == lexer.LeftParenthesis:
-		return false
-	case typ == lexer.RightParenthesis:
-		return false
-	case typ == lexer.Error:
-		return false
-	case typ == lexer.Empty:
+	case typ == lexer.Addition || typ == lexer.Subtraction:
+		return true
+	case typ == lexer.Multiplication || typ == lexer.Division:
+		return true
+	case typ == lexer.Exponent:
+		return true
+	default:
 		return false
+	}
+}
+
+// getArity returns number of operands of operator or function
+func getArity(item lexer.Item) int {
+	switch {
+	case isMathOperator(item) || isIntervalOperator(item) || isConversion(item):
+		return 2
+	case isFunction(item) && item.GetString() == derivativeFunction:
+		return 2
+	case isNegation(item) || isFunction(item):
+		return 1
 	default:
-		return true
+		return 0
 	}
 }
 
 func getPrecedenceLevel(item lexer.Item) precedenceLevel {
+	if _, ok := item.(impliedMultiplication); ok {
+		return 3
+	}
+
 	switch typ := item.GetType(); {
 	case typ == lexer.Exponent:
+		return 4
+	case typ == lexer.Negation:
 		return 3
 	case typ == lexer.Multiplication || typ == lexer.Division:
 		return 2
-	case typ == lexer.Addition || typ == lexer.Subtraction:
+	case typ == lexer.Addition || typ == lexer.Subtraction || typ == lexer.PlusMinus:
 		return 1
+	case typ == lexer.Conversion:
+		return 0
 	default:
 		return 0
 	}
@@ -224,11 +445,23 @@ func isLeftAssociative(item lexer.Item) bool {
 	switch typ := item.GetType(); {
 	case typ == lexer.Exponent:
 		return false
+	case typ == lexer.Negation:
+		return false
 	default:
 		return true
 	}
 }
 
+// pushOperator moves operators of higher precedence to output before pushing incoming operator
+func (s *operatorsStack) pushOperator(items []lexer.Item, i lexer.Item) []lexer.Item {
+	for topItem := s.peek(); shouldPopOperator(topItem, i); topItem = s.peek() {
+		items = append(items, s.pop())
+	}
+	s.push(i)
+
+	return items
+}
+
 func (s *operatorsStack) pop() lexer.Item {
 	if len(s.stack) == 0 {
 		return lexer.NewEmptyItem()
@@ -236,6 +469,11 @@ func (s *operatorsStack) pop() lexer.Item {
 	i := s.stack[len(s.stack)-1]
 	s.stack = s.stack[:len(s.stack)-1]
 
+	// implied multiplications are written to output as ordinary ones
+	if implied, ok := i.(impliedMultiplication); ok {
+		return implied.Item
+	}
+
 	return i
 }
 
@@ -2,22 +2,26 @@ package reversepolish
 
 import (
 	"context"
+	"math"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
 	"github.com/mateuszkrasucki/calculator/pkg/errors"
 	"github.com/mateuszkrasucki/calculator/pkg/lexer"
 )
 
+type parseTestCase struct {
+	name          string
+	input         string
+	expectedError error
+	expected      []lexer.Item
+}
+
 func TestParseInfix(t *testing.T) {
-	tests := []struct {
-		name          string
-		input         string
-		expectedError error
-		expected      []lexer.Item
-	}{
+	tests := []parseTestCase{
 		{
 			"Success example from wiki",
 			"3 + 4 * 2 / ( 1 - 5 ) ^  2 ^ 3",
@@ -38,12 +42,100 @@ func TestParseInfix(t *testing.T) {
 				lexer.NewItem(lexer.Addition, "+"),
 			},
 		},
+		{
+			"Success operators of the same precedence are all popped",
+			"1 - 2 * 3 * 4 - 5",
+			nil,
+			[]lexer.Item{
+				numericItem{"1", 1.0},
+				numericItem{"2", 2.0},
+				numericItem{"3", 3.0},
+				lexer.NewItem(lexer.Multiplication, "*"),
+				numericItem{"4", 4.0},
+				lexer.NewItem(lexer.Multiplication, "*"),
+				lexer.NewItem(lexer.Subtraction, "-"),
+				numericItem{"5", 5.0},
+				lexer.NewItem(lexer.Subtraction, "-"),
+			},
+		},
+		{
+			"Success unary minus and plus",
+			"-2^2 * +3 - -1",
+			nil,
+			[]lexer.Item{
+				numericItem{"2", 2.0},
+				numericItem{"2", 2.0},
+				lexer.NewItem(lexer.Exponent, "^"),
+				lexer.NewItem(lexer.Negation, "-"),
+				numericItem{"3", 3.0},
+				lexer.NewItem(lexer.Multiplication, "*"),
+				numericItem{"1", 1.0},
+				lexer.NewItem(lexer.Negation, "-"),
+				lexer.NewItem(lexer.Subtraction, "-"),
+			},
+		},
+		{
+			"Success variables and constants",
+			"2 * x + pi",
+			nil,
+			[]lexer.Item{
+				numericItem{"2", 2.0},
+				lexer.NewItem(lexer.Variable, "x"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+				numericItem{"pi", math.Pi},
+				lexer.NewItem(lexer.Addition, "+"),
+			},
+		},
 		{
 			"Error from lexer",
 			"2+2..2",
 			errors.NewParsingError("invalid rune at: 4; could not lex: 2.."),
 			nil,
 		},
+		{
+			"Success implicit multiplication",
+			"2(x + 1)y",
+			nil,
+			[]lexer.Item{
+				numericItem{"2", 2.0},
+				lexer.NewItem(lexer.Variable, "x"),
+				numericItem{"1", 1.0},
+				lexer.NewItem(lexer.Addition, "+"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+				lexer.NewItem(lexer.Variable, "y"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+			},
+		},
+		{
+			"Success implied multiplication of number and name binds tighter than division",
+			"10 km / 2 h * 2(x)",
+			nil,
+			[]lexer.Item{
+				numericItem{"10", 10.0},
+				lexer.NewItem(lexer.Variable, "km"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+				numericItem{"2", 2.0},
+				lexer.NewItem(lexer.Variable, "h"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+				lexer.NewItem(lexer.Division, "/"),
+				numericItem{"2", 2.0},
+				lexer.NewItem(lexer.Multiplication, "*"),
+				lexer.NewItem(lexer.Variable, "x"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+			},
+		},
+		{
+			"Misplaced separator",
+			"(1, 2)",
+			errors.NewParsingError("misplaced separator"),
+			nil,
+		},
+		{
+			"Function with too many arguments",
+			"sqrt(1, 2)",
+			errors.NewParsingError("function sqrt takes 1 arguments"),
+			nil,
+		},
 		{
 			"Mismatched parantheses #1",
 			"2+(2*3*5",
@@ -56,11 +148,156 @@ func TestParseInfix(t *testing.T) {
 			errors.NewParsingError("mismatched parantheses"),
 			nil,
 		},
+		{
+			"Success intervals",
+			"2 * [1.9, 2.1] ± 0.1",
+			nil,
+			[]lexer.Item{
+				numericItem{"2", 2.0},
+				numericItem{"1.9", 1.9},
+				numericItem{"2.1", 2.1},
+				lexer.NewItem(lexer.Interval, intervalOperator),
+				lexer.NewItem(lexer.Multiplication, "*"),
+				numericItem{"0.1", 0.1},
+				lexer.NewItem(lexer.PlusMinus, "±"),
+			},
+		},
+		{
+			"Success conversion of units",
+			"5 km + 300 m in m",
+			nil,
+			[]lexer.Item{
+				numericItem{"5", 5.0},
+				lexer.NewItem(lexer.Variable, "km"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+				numericItem{"300", 300.0},
+				lexer.NewItem(lexer.Variable, "m"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+				lexer.NewItem(lexer.Addition, "+"),
+				lexer.NewItem(lexer.Variable, "m"),
+				lexer.NewItem(lexer.Conversion, "in"),
+			},
+		},
+		{
+			"Success dates are numbers",
+			"2026-10-17",
+			nil,
+			[]lexer.Item{
+				numericItem{"2026", 2026.0},
+				numericItem{"10", 10.0},
+				lexer.NewItem(lexer.Subtraction, "-"),
+				numericItem{"17", 17.0},
+				lexer.NewItem(lexer.Subtraction, "-"),
+			},
+		},
+		{
+			"Mismatched brackets of interval",
+			"(1, 2]",
+			errors.NewParsingError("mismatched parantheses"),
+			nil,
+		},
+		{
+			"Interval without upper bound",
+			"[1]",
+			errors.NewParsingError("interval has to consist of lower and upper bound"),
+			nil,
+		},
 	}
 
+	runParseTests(t, tests, func(input string) (calculator.OperationInterface, error) {
+		return ParseInfix(context.Background(), input)
+	})
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []parseTestCase{
+		{
+			"Success dates and durations",
+			"2026-10-17 + 3h 20m * 4 in Europe/Warsaw",
+			nil,
+			[]lexer.Item{
+				lexer.NewItem(lexer.Date, "2026-10-17"),
+				lexer.NewItem(lexer.Duration, "3h 20m"),
+				numericItem{"4", 4.0},
+				lexer.NewItem(lexer.Multiplication, "*"),
+				lexer.NewItem(lexer.Addition, "+"),
+				lexer.NewItem(lexer.Zone, "Europe/Warsaw"),
+				lexer.NewItem(lexer.Conversion, "in"),
+			},
+		},
+	}
+
+	runParseTests(t, tests, func(input string) (calculator.OperationInterface, error) {
+		return ParseTime(context.Background(), input)
+	})
+}
+
+func TestParseItems(t *testing.T) {
+	tests := []parseTestCase{
+		{
+			"Success functions and constants",
+			"sqrt(pi*2)",
+			nil,
+			[]lexer.Item{
+				numericItem{"pi", math.Pi},
+				numericItem{"2", 2.0},
+				lexer.NewItem(lexer.Multiplication, "*"),
+				lexer.NewItem(lexer.Function, "sqrt"),
+			},
+		},
+		{
+			"Error unknown function",
+			"foo(2)",
+			errors.NewParsingError("unknown function: foo"),
+			nil,
+		},
+		{
+			"Error unknown constant",
+			"tau",
+			errors.NewParsingError("unknown constant: tau"),
+			nil,
+		},
+		{
+			"Error function without parantheses",
+			"sqrt2",
+			errors.NewParsingError("function sqrt has to be followed by parantheses"),
+			nil,
+		},
+	}
+
+	items := map[string][]lexer.Item{
+		"sqrt(pi*2)": {
+			lexer.NewItem(lexer.Function, "sqrt"),
+			lexer.NewItem(lexer.LeftParenthesis, "("),
+			lexer.NewItem(lexer.Constant, "pi"),
+			lexer.NewItem(lexer.Multiplication, "*"),
+			lexer.NewItem(lexer.Number, "2"),
+			lexer.NewItem(lexer.RightParenthesis, ")"),
+		},
+		"foo(2)": {
+			lexer.NewItem(lexer.Function, "foo"),
+			lexer.NewItem(lexer.LeftParenthesis, "("),
+			lexer.NewItem(lexer.Number, "2"),
+			lexer.NewItem(lexer.RightParenthesis, ")"),
+		},
+		"tau": {
+			lexer.NewItem(lexer.Constant, "tau"),
+		},
+		"sqrt2": {
+			lexer.NewItem(lexer.Function, "sqrt"),
+			lexer.NewItem(lexer.Number, "2"),
+		},
+	}
+
+	runParseTests(t, tests, func(input string) (calculator.OperationInterface, error) {
+		return ParseItems(context.Background(), lexer.NewItemsLexer(items[input]))
+	})
+}
+
+func runParseTests(t *testing.T, tests []parseTestCase, parse func(string) (calculator.OperationInterface, error)) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			l, err := ParseInfix(context.Background(), tt.input)
+			l, err := parse(tt.input)
 
 			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
 				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
@@ -0,0 +1,302 @@
+package reversepolish
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/mateuszkrasucki/calculator/pkg/bigmath"
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// maxRationalBits is the highest size of numerator or denominator of exact powers
+const maxRationalBits = 1 << 20
+
+// maxRootDegree is the highest denominator of exponents of exact roots
+const maxRootDegree = 64
+
+// rationalArithmetic calculates with *big.Rat numbers, operations without rational results return
+// *big.Float numbers of float arithmetic, all further operations on them are approximate too
+type rationalArithmetic struct {
+	float  bigFloatArithmetic
+	strict bool
+}
+
+type rationalValue struct {
+	exact       *big.Rat
+	approximate *big.Float
+	digits      int
+}
+
+func newRationalArithmetic(mode calculator.Mode) (arithmetic, error) {
+	float, err := newBigFloatArithmetic(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return rationalArithmetic{float: float.(bigFloatArithmetic), strict: mode.Strict}, nil
+}
+
+// approximate returns result of float arithmetic for operation which cannot be exact, strict arithmetic fails
+func (a rationalArithmetic) approximate(description string, calculate func() (interface{}, error)) (interface{}, error) {
+	if a.strict {
+		return nil, errors.NewCalculationError(fmt.Sprintf("result of %s is not rational", description))
+	}
+
+	return calculate()
+}
+
+// toFloat returns number as float of float arithmetic
+func (a rationalArithmetic) toFloat(x interface{}) *big.Float {
+	if r, ok := x.(*big.Rat); ok {
+		return a.float.newFloat().SetRat(r)
+	}
+
+	return x.(*big.Float)
+}
+
+func (a rationalArithmetic) number(item lexer.Item) (interface{}, error) {
+	if _, ok := constants[item.GetString()]; ok {
+		return a.approximate(item.GetString(), func() (interface{}, error) { return a.float.number(item) })
+	}
+
+	r, ok := new(big.Rat).SetString(item.GetString())
+	if !ok {
+		return nil, errors.NewParsingError(fmt.Sprintf("could not parse %s as a number", item.GetString()))
+	}
+
+	return r, nil
+}
+
+func (a rationalArithmetic) operator(operator string, x, y interface{}) (interface{}, error) {
+	r, rOk := x.(*big.Rat)
+	q, qOk := y.(*big.Rat)
+	if !rOk || !qOk {
+		return a.float.operator(operator, a.toFloat(x), a.toFloat(y))
+	}
+
+	switch operator {
+	case "+":
+		return new(big.Rat).Add(r, q), nil
+	case "-":
+		return new(big.Rat).Sub(r, q), nil
+	case "*":
+		return new(big.Rat).Mul(r, q), nil
+	case "/":
+		if q.Sign() == 0 {
+			return nil, errors.NewCalculationError("division by zero")
+		}
+		return new(big.Rat).Quo(r, q), nil
+	case "^":
+		return a.power(r, q)
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown operator: %s", operator))
+	}
+}
+
+// power is exact for integer exponents and for roots of rationals with rational roots, e.g. 4 ^ 1.5 = 8
+func (a rationalArithmetic) power(r *big.Rat, q *big.Rat) (interface{}, error) {
+	if q.Denom().IsInt64() && q.Denom().Int64() <= maxRootDegree {
+		if root, ok := rationalRoot(r, int(q.Denom().Int64())); ok {
+			return integerPower(root, q.Num())
+		}
+	}
+
+	description := fmt.Sprintf("%s ^ %s", r.RatString(), q.RatString())
+
+	return a.approximate(description, func() (interface{}, error) {
+		return a.float.operator("^", a.toFloat(r), a.toFloat(q))
+	})
+}
+
+func integerPower(r *big.Rat, exponent *big.Int) (*big.Rat, error) {
+	if exponent.Sign() < 0 && r.Sign() == 0 {
+		return nil, errors.NewCalculationError("division by zero")
+	}
+
+	bits := max(r.Num().BitLen(), r.Denom().BitLen())
+	if bits > 1 && (!exponent.IsInt64() || int64(bits)*abs(exponent.Int64()) > maxRationalBits) {
+		return nil, errors.NewCalculationError("result is too large")
+	}
+
+	e := new(big.Int).Abs(exponent)
+	num := new(big.Int).Exp(r.Num(), e, nil)
+	denom := new(big.Int).Exp(r.Denom(), e, nil)
+	if exponent.Sign() < 0 {
+		num, denom = denom, num
+	}
+
+	return new(big.Rat).SetFrac(num, denom), nil
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
+
+// rationalRoot returns degree root of rational number if it is rational, odd roots of negative numbers are negative
+func rationalRoot(r *big.Rat, degree int) (*big.Rat, bool) {
+	if degree == 1 {
+		return r, true
+	}
+
+	if r.Sign() < 0 && degree%2 == 0 {
+		return nil, false
+	}
+
+	num, ok := integerRoot(new(big.Int).Abs(r.Num()), degree)
+	if !ok {
+		return nil, false
+	}
+
+	denom, ok := integerRoot(r.Denom(), degree)
+	if !ok {
+		return nil, false
+	}
+
+	if r.Sign() < 0 {
+		num.Neg(num)
+	}
+
+	return new(big.Rat).SetFrac(num, denom), true
+}
+
+// integerRoot finds floor of degree root of non-negative integer with Newton's method and checks if it is exact
+func integerRoot(x *big.Int, degree int) (*big.Int, bool) {
+	if x.Sign() == 0 {
+		return new(big.Int), true
+	}
+
+	n := big.NewInt(int64(degree))
+	nMinus1 := big.NewInt(int64(degree - 1))
+
+	// initial guess is a power of two higher than the root
+	root := new(big.Int).Lsh(big.NewInt(1), uint(x.BitLen()/degree+1))
+	for {
+		// next = ((n - 1) * root + x / root^(n-1)) / n
+		next := new(big.Int).Exp(root, nMinus1, nil)
+		next.Quo(x, next)
+		next.Add(next, new(big.Int).Mul(nMinus1, root))
+		next.Quo(next, n)
+
+		if next.Cmp(root) >= 0 {
+			break
+		}
+		root = next
+	}
+
+	return root, new(big.Int).Exp(root, n, nil).Cmp(x) == 0
+}
+
+func (a rationalArithmetic) negate(x interface{}) (interface{}, error) {
+	if r, ok := x.(*big.Rat); ok {
+		return new(big.Rat).Neg(r), nil
+	}
+
+	return a.float.negate(x)
+}
+
+func (a rationalArithmetic) function(name string, x interface{}) (interface{}, error) {
+	r, ok := x.(*big.Rat)
+	if !ok {
+		return a.float.function(name, x)
+	}
+
+	switch name {
+	case "abs":
+		return new(big.Rat).Abs(r), nil
+	case "re", "conj":
+		return r, nil
+	case "im":
+		return new(big.Rat), nil
+	case "arg":
+		if r.Sign() >= 0 {
+			return new(big.Rat), nil
+		}
+	case "floor", "ceil":
+		quotient, remainder := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+		if name == "ceil" && remainder.Sign() != 0 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+		return new(big.Rat).SetInt(quotient), nil
+	case "sqrt":
+		if r.Sign() < 0 {
+			return nil, errors.NewCalculationError("square root of negative number")
+		}
+		if root, ok := rationalRoot(r, 2); ok {
+			return root, nil
+		}
+	}
+
+	if _, ok := functions[name]; !ok {
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown function: %s", name))
+	}
+
+	description := fmt.Sprintf("%s(%s)", name, r.RatString())
+
+	return a.approximate(description, func() (interface{}, error) { return a.float.function(name, a.toFloat(r)) })
+}
+
+func (a rationalArithmetic) value(x interface{}) (calculator.Value, error) {
+	if r, ok := x.(*big.Rat); ok {
+		return rationalValue{exact: r, digits: a.float.digits}, nil
+	}
+
+	return rationalValue{approximate: x.(*big.Float), digits: a.float.digits}, nil
+}
+
+func (v rationalValue) Float64() float64 {
+	if v.exact == nil {
+		f, _ := v.approximate.Float64()
+		return f
+	}
+
+	f, _ := v.exact.Float64()
+
+	return f
+}
+
+// String writes terminating decimals exactly, other values are rounded to significant digits
+func (v rationalValue) String() string {
+	if v.exact == nil {
+		return bigFloatValue{value: v.approximate, digits: v.digits}.String()
+	}
+
+	if decimals, ok := bigmath.DecimalDigits(v.exact.Denom()); ok {
+		return v.exact.FloatString(decimals)
+	}
+
+	f := new(big.Float).SetPrec(uint(float64(v.digits)*3.33) + guardBits).SetRat(v.exact)
+
+	return bigFloatValue{value: f, digits: v.digits}.String()
+}
+
+func (v rationalValue) Fraction() string {
+	if v.exact == nil {
+		return ""
+	}
+
+	return v.exact.RatString()
+}
+
+// Mixed writes integer part and proper fraction, e.g. -1 1/2, integers and proper fractions are written as fractions
+func (v rationalValue) Mixed() string {
+	if v.exact == nil {
+		return ""
+	}
+
+	integer, remainder := new(big.Int).QuoRem(v.exact.Num(), v.exact.Denom(), new(big.Int))
+	if integer.Sign() == 0 || remainder.Sign() == 0 {
+		return v.exact.RatString()
+	}
+
+	return fmt.Sprintf("%s %s", integer, new(big.Rat).SetFrac(remainder.Abs(remainder), v.exact.Denom()).RatString())
+}
+
+func (v rationalValue) Approximate() bool {
+	return v.exact == nil
+}
@@ -0,0 +1,86 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestCalculateRational(t *testing.T) {
+	tests := []struct {
+		name                string
+		input               string
+		mode                calculator.Mode
+		expectedFraction    string
+		expectedMixed       string
+		expectedDecimal     string
+		expectedApproximate bool
+		expectedError       error
+	}{
+		{"Sum of fractions", "1/3 + 1/6", calculator.Mode{}, "1/2", "1/2", "0.5", false, nil},
+		{"Decimal literals", "0.1 + 0.2", calculator.Mode{}, "3/10", "3/10", "0.3", false, nil},
+		{"Improper fraction", "-7 / 2", calculator.Mode{}, "-7/2", "-3 1/2", "-3.5", false, nil},
+		{"Integer", "6 / 3", calculator.Mode{}, "2", "2", "2", false, nil},
+		{"Non terminating decimal", "2 / 3", calculator.Mode{Digits: 10}, "2/3", "2/3", "0.6666666667", false, nil},
+		{"Integer power", "(2/3) ^ -3", calculator.Mode{}, "27/8", "3 3/8", "3.375", false, nil},
+		{"Large power", "3 ^ 50", calculator.Mode{}, "717897987691852588770249", "717897987691852588770249", "717897987691852588770249", false, nil},
+		{"Rational root", "(4/9) ^ 1.5", calculator.Mode{}, "8/27", "8/27", "0.2962962962962962962962962962962962962962962962963", false, nil},
+		{"Odd root of negative number", "(-8) ^ (1/3)", calculator.Mode{}, "-2", "-2", "-2", false, nil},
+		{"Exact square root", "sqrt(2.25)", calculator.Mode{}, "3/2", "1 1/2", "1.5", false, nil},
+		{"Floor and ceiling", "floor(-7/2) + ceil(7/2) + abs(-1/2)", calculator.Mode{}, "1/2", "1/2", "0.5", false, nil},
+		{"Irrational root", "sqrt(2)", calculator.Mode{Digits: 20}, "", "", "1.4142135623730950488", true, nil},
+		{"Approximation continues", "2 ^ 0.5 * 2 ^ 0.5 + 1/3", calculator.Mode{Digits: 20}, "", "", "2.3333333333333333333", true, nil},
+		{"Transcendental function", "sin(1/2)", calculator.Mode{Digits: 10}, "", "", "0.4794255386", true, nil},
+		{"Constant", "pi", calculator.Mode{Digits: 10}, "", "", "3.141592654", true, nil},
+		{"Strict irrational root", "sqrt(2)", calculator.Mode{Strict: true}, "", "", "", false, errors.NewCalculationError("result of sqrt(2) is not rational")},
+		{"Strict power", "2 ^ (1/2)", calculator.Mode{Strict: true}, "", "", "", false, errors.NewCalculationError("result of 2 ^ 1/2 is not rational")},
+		{"Strict constant", "pi", calculator.Mode{Strict: true}, "", "", "", false, errors.NewCalculationError("result of pi is not rational")},
+		{"Division by zero", "1 / (1/2 - 0.5)", calculator.Mode{}, "", "", "", false, errors.NewCalculationError("division by zero")},
+		{"Power too large", "3 ^ 1000000", calculator.Mode{}, "", "", "", false, errors.NewCalculationError("result is too large")},
+		{"Even root of negative number", "(-4) ^ 0.5", calculator.Mode{}, "", "", "", false, errors.NewCalculationError("non-integer power of negative number")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			tt.mode.Name = calculator.RationalMode
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), tt.mode)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			rational := value.(rationalValue)
+
+			if rational.Fraction() != tt.expectedFraction {
+				t.Errorf("expected fraction to be %s, got %s", tt.expectedFraction, rational.Fraction())
+			}
+
+			if rational.Mixed() != tt.expectedMixed {
+				t.Errorf("expected mixed number to be %s, got %s", tt.expectedMixed, rational.Mixed())
+			}
+
+			if rational.String() != tt.expectedDecimal {
+				t.Errorf("expected decimal to be %s, got %s", tt.expectedDecimal, rational.String())
+			}
+
+			if rational.Approximate() != tt.expectedApproximate {
+				t.Errorf("expected approximate to be %v, got %v", tt.expectedApproximate, rational.Approximate())
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package reversepolish
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// LaTeX commands of functions, functions without a command are typeset as operator names
+var latexFunctions = map[string]string{
+	"sin":  "\\sin",
+	"cos":  "\\cos",
+	"tan":  "\\tan",
+	"asin": "\\arcsin",
+	"acos": "\\arccos",
+	"atan": "\\arctan",
+	"sinh": "\\sinh",
+	"cosh": "\\cosh",
+	"tanh": "\\tanh",
+	"exp":  "\\exp",
+	"ln":   "\\ln",
+	"log":  "\\log",
+}
+
+var latexConstants = map[string]string{
+	"pi": "\\pi",
+}
+
+var mathMLConstants = map[string]string{
+	"pi": "&#x3C0;",
+}
+
+var mathMLOperators = map[string]string{
+	"+":  "+",
+	"-":  "&#x2212;",
+	"*":  "&#x22C5;",
+	"±":  "&#xB1;",
+	"in": "in",
+	"to": "to",
+}
+
+var latexOperators = map[string]string{
+	"*":  "\\cdot",
+	"±":  "\\pm",
+	"in": "\\text{ in }",
+	"to": "\\text{ to }",
+}
+
+// RenderLaTeX returns LaTeX representation of operation, divisions are typeset as fractions, powers as superscripts
+func RenderLaTeX(operation calculator.OperationInterface) (string, error) {
+	tree, err := operationTree(operation)
+	if err != nil {
+		return "", err
+	}
+
+	return tree.latex(), nil
+}
+
+// RenderMathML returns Presentation MathML representation of operation
+func RenderMathML(operation calculator.OperationInterface) (string, error) {
+	tree, err := operationTree(operation)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("<math xmlns=\"http://www.w3.org/1998/Math/MathML\"><mrow>%s</mrow></math>", tree.mathML()), nil
+}
+
+func (n *node) latex() string {
+	switch {
+	case n.isRoot():
+		if degree := n.rootDegree(); degree != "" {
+			return fmt.Sprintf("\\sqrt[%s]{%s}", degree, n.children[0].latex())
+		}
+		return fmt.Sprintf("\\sqrt{%s}", n.children[0].latex())
+	case isFunction(n.item) && n.item.GetString() == "abs":
+		return fmt.Sprintf("\\left|%s\\right|", n.children[0].latex())
+	case isFunction(n.item) && n.item.GetString() == "floor":
+		return fmt.Sprintf("\\left\\lfloor %s\\right\\rfloor", n.children[0].latex())
+	case isFunction(n.item) && n.item.GetString() == "ceil":
+		return fmt.Sprintf("\\left\\lceil %s\\right\\rceil", n.children[0].latex())
+	case n.item.GetType() == lexer.Interval:
+		return fmt.Sprintf("\\left[%s, %s\\right]", n.children[0].latex(), n.children[1].latex())
+	case isFunction(n.item):
+		name, ok := latexFunctions[n.item.GetString()]
+		if !ok {
+			name = fmt.Sprintf("\\operatorname{%s}", n.item.GetString())
+		}
+		return fmt.Sprintf("%s\\left(%s\\right)", name, n.children[0].latex())
+	case n.item.GetType() == lexer.Division:
+		return fmt.Sprintf("\\frac{%s}{%s}", n.children[0].latex(), n.children[1].latex())
+	case n.item.GetType() == lexer.Exponent:
+		return fmt.Sprintf("{%s}^{%s}", n.renderedChild(0, (*node).latex, latexParentheses), n.children[1].latex())
+	case isNegation(n.item):
+		return "-" + n.renderedChild(0, (*node).latex, latexParentheses)
+	case isMathOperator(n.item) || isConversion(n.item) || n.item.GetType() == lexer.PlusMinus:
+		operator := n.item.GetString()
+		if command, ok := latexOperators[operator]; ok {
+			operator = command
+		}
+		return fmt.Sprintf("%s %s %s", n.renderedChild(0, (*node).latex, latexParentheses), operator, n.renderedChild(1, (*node).latex, latexParentheses))
+	default:
+		if constant, ok := latexConstants[n.item.GetString()]; ok {
+			return constant
+		}
+		if isTimeLiteral(n.item) {
+			return fmt.Sprintf("\\text{%s}", n.item.GetString())
+		}
+		return n.item.GetString()
+	}
+}
+
+func (n *node) mathML() string {
+	switch {
+	case n.isRoot():
+		if degree := n.rootDegree(); degree != "" {
+			return fmt.Sprintf("<mroot><mrow>%s</mrow><mn>%s</mn></mroot>", n.children[0].mathML(), html.EscapeString(degree))
+		}
+		return fmt.Sprintf("<msqrt>%s</msqrt>", n.children[0].mathML())
+	case isFunction(n.item) && n.item.GetString() == "abs":
+		return fmt.Sprintf("<mrow><mo>|</mo>%s<mo>|</mo></mrow>", n.children[0].mathML())
+	case isFunction(n.item) && n.item.GetString() == "floor":
+		return fmt.Sprintf("<mrow><mo>&#x230A;</mo>%s<mo>&#x230B;</mo></mrow>", n.children[0].mathML())
+	case isFunction(n.item) && n.item.GetString() == "ceil":
+		return fmt.Sprintf("<mrow><mo>&#x2308;</mo>%s<mo>&#x2309;</mo></mrow>", n.children[0].mathML())
+	case n.item.GetType() == lexer.Interval:
+		return fmt.Sprintf("<mrow><mo>[</mo>%s<mo>,</mo>%s<mo>]</mo></mrow>", n.children[0].mathML(), n.children[1].mathML())
+	case isFunction(n.item):
+		return fmt.Sprintf("<mi>%s</mi><mo>&#x2061;</mo>%s", html.EscapeString(n.item.GetString()), mathMLParentheses(n.children[0].mathML()))
+	case n.item.GetType() == lexer.Division:
+		return fmt.Sprintf("<mfrac><mrow>%s</mrow><mrow>%s</mrow></mfrac>", n.children[0].mathML(), n.children[1].mathML())
+	case n.item.GetType() == lexer.Exponent:
+		return fmt.Sprintf("<msup><mrow>%s</mrow><mrow>%s</mrow></msup>", n.renderedChild(0, (*node).mathML, mathMLParentheses), n.children[1].mathML())
+	case isNegation(n.item):
+		return "<mo>&#x2212;</mo>" + n.renderedChild(0, (*node).mathML, mathMLParentheses)
+	case isMathOperator(n.item) || isConversion(n.item) || n.item.GetType() == lexer.PlusMinus:
+		return fmt.Sprintf("%s<mo>%s</mo>%s", n.renderedChild(0, (*node).mathML, mathMLParentheses), mathMLOperators[n.item.GetString()], n.renderedChild(1, (*node).mathML, mathMLParentheses))
+	default:
+		if constant, ok := mathMLConstants[n.item.GetString()]; ok {
+			return fmt.Sprintf("<mi>%s</mi>", constant)
+		}
+		if n.item.GetString() == "e" {
+			return "<mi>e</mi>"
+		}
+		if isVariable(n.item) {
+			return fmt.Sprintf("<mi>%s</mi>", html.EscapeString(n.item.GetString()))
+		}
+		if isTimeLiteral(n.item) {
+			return fmt.Sprintf("<mtext>%s</mtext>", html.EscapeString(n.item.GetString()))
+		}
+		return fmt.Sprintf("<mn>%s</mn>", html.EscapeString(n.item.GetString()))
+	}
+}
+
+// renderedChild returns rendered child, parenthesized if needed in typeset form
+func (n *node) renderedChild(k int, render func(*node) string, parenthesize func(string) string) string {
+	rendered := render(n.children[k])
+
+	if n.childNeedsParentheses(k) {
+		return parenthesize(rendered)
+	}
+
+	return rendered
+}
+
+// childNeedsParentheses works like needsParentheses but takes into account that fractions, roots
+// and superscripts group their operands visually
+func (n *node) childNeedsParentheses(k int) bool {
+	child := n.children[k]
+
+	// bases which are operations or functions are parenthesized, numbers and names are not
+	if n.item.GetType() == lexer.Exponent && k == 0 {
+		return isMathOperator(child.item) || isNegation(child.item) || isFunction(child.item) || isIntervalOperator(child.item) || isConversion(child.item)
+	}
+
+	if child.isRoot() || (isFunction(child.item) && child.item.GetString() == "abs") || child.item.GetType() == lexer.Division {
+		return false
+	}
+
+	return needsParentheses(n.item, child, k == 0 && !isNegation(n.item))
+}
+
+// isRoot returns true for square roots and n-th roots written as powers with 1/n exponent
+func (n *node) isRoot() bool {
+	if isFunction(n.item) && n.item.GetString() == "sqrt" {
+		return true
+	}
+
+	return n.rootDegree() != ""
+}
+
+func (n *node) rootDegree() string {
+	if n.item.GetType() != lexer.Exponent {
+		return ""
+	}
+
+	exponent := n.children[1]
+	if exponent.item.GetType() != lexer.Division || !isNumber(exponent.children[0].item) || getNumericValue(exponent.children[0].item) != 1 {
+		return ""
+	}
+
+	if !isNumber(exponent.children[1].item) {
+		return ""
+	}
+
+	return exponent.children[1].item.GetString()
+}
+
+func latexParentheses(rendered string) string {
+	return fmt.Sprintf("\\left(%s\\right)", rendered)
+}
+
+func mathMLParentheses(rendered string) string {
+	return fmt.Sprintf("<mrow><mo>(</mo>%s<mo>)</mo></mrow>", rendered)
+}
@@ -0,0 +1,110 @@
+package reversepolish
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		items          []lexer.Item
+		expectedLaTeX  string
+		expectedMathML string
+	}{
+		{
+			"Fraction and power",
+			"(1+2)/4*2^(3+1)",
+			nil,
+			"\\frac{1 + 2}{4} \\cdot {2}^{3 + 1}",
+			"<mfrac><mrow><mn>1</mn><mo>+</mo><mn>2</mn></mrow><mrow><mn>4</mn></mrow></mfrac><mo>&#x22C5;</mo><msup><mrow><mn>2</mn></mrow><mrow><mn>3</mn><mo>+</mo><mn>1</mn></mrow></msup>",
+		},
+		{
+			"Intervals",
+			"[1.9, 2.1] * 5 ± 0.2",
+			nil,
+			"\\left[1.9, 2.1\\right] \\cdot 5 \\pm 0.2",
+			"<mrow><mo>[</mo><mn>1.9</mn><mo>,</mo><mn>2.1</mn><mo>]</mo></mrow><mo>&#x22C5;</mo><mn>5</mn><mo>&#xB1;</mo><mn>0.2</mn>",
+		},
+		{
+			"Conversion of units",
+			"100 degF in degC",
+			nil,
+			"100 \\cdot degF \\text{ in } degC",
+			"<mn>100</mn><mo>&#x22C5;</mo><mi>degF</mi><mo>in</mo><mi>degC</mi>",
+		},
+		{
+			"Parentheses required by precedence",
+			"(1-2)*-(3+4)",
+			nil,
+			"\\left(1 - 2\\right) \\cdot -\\left(3 + 4\\right)",
+			"<mrow><mo>(</mo><mn>1</mn><mo>&#x2212;</mo><mn>2</mn><mo>)</mo></mrow><mo>&#x22C5;</mo><mo>&#x2212;</mo><mrow><mo>(</mo><mn>3</mn><mo>+</mo><mn>4</mn><mo>)</mo></mrow>",
+		},
+		{
+			"Power base",
+			"(-2)^2+(2^3)^2",
+			nil,
+			"{\\left(-2\\right)}^{2} + {\\left({2}^{3}\\right)}^{2}",
+			"<msup><mrow><mrow><mo>(</mo><mo>&#x2212;</mo><mn>2</mn><mo>)</mo></mrow></mrow><mrow><mn>2</mn></mrow></msup><mo>+</mo><msup><mrow><mrow><mo>(</mo><msup><mrow><mn>2</mn></mrow><mrow><mn>3</mn></mrow></msup><mo>)</mo></mrow></mrow><mrow><mn>2</mn></mrow></msup>",
+		},
+		{
+			"Power of variable",
+			"x^2 + sin(x)^2",
+			nil,
+			"{x}^{2} + {\\left(\\sin\\left(x\\right)\\right)}^{2}",
+			"<msup><mrow><mi>x</mi></mrow><mrow><mn>2</mn></mrow></msup><mo>+</mo><msup><mrow><mrow><mo>(</mo><mi>sin</mi><mo>&#x2061;</mo><mrow><mo>(</mo><mi>x</mi><mo>)</mo></mrow><mo>)</mo></mrow></mrow><mrow><mn>2</mn></mrow></msup>",
+		},
+		{
+			"Roots",
+			"sqrt(16)*27^(1/3)",
+			[]lexer.Item{
+				numericItem{"16", 16.0},
+				lexer.NewItem(lexer.Function, "sqrt"),
+				numericItem{"27", 27.0},
+				numericItem{"1", 1.0},
+				numericItem{"3", 3.0},
+				lexer.NewItem(lexer.Division, "/"),
+				lexer.NewItem(lexer.Exponent, "^"),
+				lexer.NewItem(lexer.Multiplication, "*"),
+			},
+			"\\sqrt{16} \\cdot \\sqrt[3]{27}",
+			"<msqrt><mn>16</mn></msqrt><mo>&#x22C5;</mo><mroot><mrow><mn>27</mn></mrow><mn>3</mn></mroot>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operation calculator.OperationInterface = &rpnOperation{tt.items}
+			if tt.items == nil {
+				var err error
+				operation, err = ParseInfix(context.Background(), tt.input)
+				if err != nil {
+					t.Fatalf("expected error to be nil, got %v", err)
+				}
+			}
+
+			latex, err := RenderLaTeX(operation)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			if latex != tt.expectedLaTeX {
+				t.Errorf("expected LaTeX to be %v, got %v", tt.expectedLaTeX, latex)
+			}
+
+			mathML, err := RenderMathML(operation)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			expectedMathML := "<math xmlns=\"http://www.w3.org/1998/Math/MathML\"><mrow>" + tt.expectedMathML + "</mrow></math>"
+			if mathML != expectedMathML {
+				t.Errorf("expected MathML to be %v, got %v", expectedMathML, mathML)
+			}
+		})
+	}
+}
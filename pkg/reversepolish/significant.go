@@ -0,0 +1,241 @@
+package reversepolish
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// significantArithmetic tracks precision of measured numbers, results of multiplication and division keep
+// the fewest significant figures of operands and results of addition and subtraction the fewest decimal places,
+// values are calculated with big floats and rounded only when they are written
+type significantArithmetic struct {
+	float    bigFloatArithmetic
+	rounding big.RoundingMode
+}
+
+// measurement is a value with decimal exponent of its last significant digit, e.g. -2 for 12.30 and 2 for 1200,
+// constants are exact
+type measurement struct {
+	value *big.Float
+	place int
+	exact bool
+}
+
+type significantValue struct {
+	measurement
+	rounded *big.Int
+	figures int
+	digits  int
+}
+
+func newSignificantArithmetic(mode calculator.Mode) (arithmetic, error) {
+	float, err := newBigFloatArithmetic(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	a := float.(bigFloatArithmetic)
+
+	return significantArithmetic{float: a, rounding: a.rounding}, nil
+}
+
+// literalPlace returns decimal exponent of the last significant digit of number written in operation,
+// trailing zeros of integers are not significant
+func literalPlace(literal string) int {
+	if dot := strings.Index(literal, "."); dot >= 0 {
+		return -(len(literal) - dot - 1)
+	}
+
+	trimmed := strings.TrimRight(literal, "0")
+	if trimmed == "" {
+		return 0
+	}
+
+	return len(literal) - len(trimmed)
+}
+
+// round returns value rounded to place as an integer number of units of the place
+func (a significantArithmetic) round(value *big.Float, place int) *big.Int {
+	r, _ := value.Rat(nil)
+	if place > 0 {
+		r.Quo(r, new(big.Rat).SetInt(pow10(place)))
+	}
+
+	scale := max(-place, 0)
+
+	return decimalArithmetic{scale: scale, one: pow10(scale), rounding: a.rounding}.round(r)
+}
+
+// figures returns number of significant figures of measurement, zero has one significant figure
+func (a significantArithmetic) figures(m measurement) int {
+	return max(len(new(big.Int).Abs(a.round(m.value, m.place)).String()), 1)
+}
+
+// placeOf returns decimal exponent of the last of figures significant digits of value
+func (a significantArithmetic) placeOf(value *big.Float, figures int) int {
+	place := 1 - figures
+	if value.Sign() != 0 {
+		_, exponent, _ := strings.Cut(value.Text('e', 40), "e")
+		e, _ := strconv.Atoi(exponent)
+		place += e
+	}
+
+	// rounding may carry to the next digit, e.g. 9.96 rounded to two figures is 10
+	if len(new(big.Int).Abs(a.round(value, place)).String()) > figures {
+		place++
+	}
+
+	return place
+}
+
+func (a significantArithmetic) number(item lexer.Item) (interface{}, error) {
+	value, err := a.float.number(item)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := constants[item.GetString()]; ok {
+		return measurement{value: value.(*big.Float), exact: true}, nil
+	}
+
+	return measurement{value: value.(*big.Float), place: literalPlace(item.GetString())}, nil
+}
+
+func (a significantArithmetic) operator(operator string, x, y interface{}) (interface{}, error) {
+	f, g := x.(measurement), y.(measurement)
+
+	value, err := a.float.operator(operator, f.value, g.value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := measurement{value: value.(*big.Float)}
+
+	switch {
+	case f.exact && g.exact:
+		result.exact = true
+	case (operator == "+" || operator == "-") && f.exact:
+		result.place = g.place
+	case (operator == "+" || operator == "-") && g.exact:
+		result.place = f.place
+	case operator == "+" || operator == "-":
+		result.place = max(f.place, g.place)
+	case operator == "^" && !f.exact:
+		result.place = a.placeOf(result.value, a.figures(f))
+	default:
+		figures := 0
+		for _, m := range []measurement{f, g} {
+			if !m.exact && (figures == 0 || a.figures(m) < figures) {
+				figures = a.figures(m)
+			}
+		}
+		result.place = a.placeOf(result.value, figures)
+	}
+
+	return result, nil
+}
+
+func (a significantArithmetic) negate(x interface{}) (interface{}, error) {
+	f := x.(measurement)
+
+	return measurement{value: a.float.newFloat().Neg(f.value), place: f.place, exact: f.exact}, nil
+}
+
+// function keeps significant figures of argument, logarithms have as many decimal places as argument has
+// significant figures and exponential function as many significant figures as argument has decimal places
+func (a significantArithmetic) function(name string, x interface{}) (interface{}, error) {
+	f := x.(measurement)
+
+	value, err := a.float.function(name, f.value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := measurement{value: value.(*big.Float), exact: f.exact}
+	if f.exact {
+		return result, nil
+	}
+
+	switch name {
+	case "ln", "log":
+		result.place = -a.figures(f)
+	case "exp":
+		result.place = a.placeOf(result.value, max(-f.place, 1))
+	case "floor", "ceil":
+		result.place = max(f.place, 0)
+	default:
+		result.place = a.placeOf(result.value, a.figures(f))
+	}
+
+	return result, nil
+}
+
+func (a significantArithmetic) value(x interface{}) (calculator.Value, error) {
+	m := x.(measurement)
+	if m.exact {
+		return significantValue{measurement: m, digits: a.float.digits}, nil
+	}
+
+	return significantValue{measurement: m, rounded: a.round(m.value, m.place), figures: a.figures(m), digits: a.float.digits}, nil
+}
+
+func (v significantValue) Float64() float64 {
+	if v.exact {
+		f, _ := v.value.Float64()
+		return f
+	}
+
+	r := new(big.Rat).SetInt(v.rounded)
+	if v.place > 0 {
+		r.Mul(r, new(big.Rat).SetInt(pow10(v.place)))
+	} else {
+		r.Quo(r, new(big.Rat).SetInt(pow10(-v.place)))
+	}
+
+	f, _ := r.Float64()
+
+	return f
+}
+
+// String writes value rounded to its significant figures, values with insignificant digits before decimal point
+// are written in scientific notation, e.g. 1.2e+3
+func (v significantValue) String() string {
+	if v.exact {
+		return bigFloatValue{value: v.value, digits: v.digits}.String()
+	}
+
+	if v.place <= 0 {
+		return decimalValue{v.rounded, -v.place}.String()
+	}
+
+	if v.rounded.Sign() == 0 {
+		return "0"
+	}
+
+	sign := ""
+	if v.rounded.Sign() < 0 {
+		sign = "-"
+	}
+
+	digits := new(big.Int).Abs(v.rounded).String()
+	exponent := len(digits) - 1 + v.place
+	if len(digits) > 1 {
+		digits = digits[:1] + "." + digits[1:]
+	}
+
+	return fmt.Sprintf("%s%se%+d", sign, digits, exponent)
+}
+
+// Significance returns significant figures and decimal places of value, it is nil for exact values
+func (v significantValue) Significance() *calculator.Significance {
+	if v.exact {
+		return nil
+	}
+
+	return &calculator.Significance{Figures: v.figures, DecimalPlaces: -v.place}
+}
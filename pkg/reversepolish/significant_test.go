@@ -0,0 +1,79 @@
+package reversepolish
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestCalculateSignificantFigures(t *testing.T) {
+	tests := []struct {
+		name                 string
+		input                string
+		expected             string
+		expectedSignificance *calculator.Significance
+		expectedError        error
+	}{
+		{"Multiplication keeps fewest figures", "2.5 * 3.42", "8.5", &calculator.Significance{Figures: 2, DecimalPlaces: 1}, nil},
+		{"Addition keeps fewest decimal places", "12.30 + 1.2", "13.5", &calculator.Significance{Figures: 3, DecimalPlaces: 1}, nil},
+		{"Trailing zeros of integers are not significant", "1200 * 2.0", "2.4e+3", &calculator.Significance{Figures: 2, DecimalPlaces: -2}, nil},
+		{"Trailing decimal point", "1200. * 2.00", "2.40e+3", &calculator.Significance{Figures: 3, DecimalPlaces: -1}, nil},
+		{"Leading zeros are not significant", "0.0050 * 3.14159", "0.016", &calculator.Significance{Figures: 2, DecimalPlaces: 3}, nil},
+		{"Rounding carries to the next digit", "9.96 * 1.0", "10", &calculator.Significance{Figures: 2, DecimalPlaces: 0}, nil},
+		{"Subtraction loses figures", "100.0 - 99.95", "0.1", &calculator.Significance{Figures: 1, DecimalPlaces: 1}, nil},
+		{"Zero result", "1.2 - 1.2", "0.0", &calculator.Significance{Figures: 1, DecimalPlaces: 1}, nil},
+		{"Constants are exact", "pi * 2.000", "6.283", &calculator.Significance{Figures: 4, DecimalPlaces: 3}, nil},
+		{"Logarithm", "ln(2.50)", "0.916", &calculator.Significance{Figures: 3, DecimalPlaces: 3}, nil},
+		{"Exponential function", "exp(2.50)", "12", &calculator.Significance{Figures: 2, DecimalPlaces: 0}, nil},
+		{"Square root", "sqrt(16.0)", "4.00", &calculator.Significance{Figures: 3, DecimalPlaces: 2}, nil},
+		{"Power", "3.0 ^ 2", "9.0", &calculator.Significance{Figures: 2, DecimalPlaces: 1}, nil},
+		{"Intermediate results are not rounded", "(0.45 + 1.0) * 3.000", "4.4", &calculator.Significance{Figures: 2, DecimalPlaces: 1}, nil},
+		{"Zero with insignificant integer digits", "1200 - 1200", "0", &calculator.Significance{Figures: 1, DecimalPlaces: -2}, nil},
+		{"Division by zero", "1.0 / 0", "", nil, errors.NewCalculationError("division by zero")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), calculator.Mode{Name: calculator.SignificantFiguresMode})
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if value.String() != tt.expected {
+				t.Errorf("expected result to be %s, got %s", tt.expected, value.String())
+			}
+
+			if significance := value.(calculator.SignificantValue).Significance(); !cmp.Equal(tt.expectedSignificance, significance) {
+				t.Errorf("expected significance to be %v, got %v", tt.expectedSignificance, significance)
+			}
+		})
+	}
+}
+
+func TestLiteralPlace(t *testing.T) {
+	tests := map[string]int{"12.30": -2, "1200": 2, "1200.": 0, "0.0050": -4, "7": 0, "0": 0}
+
+	for literal, expected := range tests {
+		if place := literalPlace(literal); place != expected {
+			t.Errorf("expected place of %s to be %d, got %d", literal, expected, place)
+		}
+	}
+}
@@ -0,0 +1,585 @@
+package reversepolish
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/bigmath"
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// maxExpandedTerms limits number of terms created when multiplying sums, bigger products are kept unexpanded
+const maxExpandedTerms = 100
+
+// maxExpandedExponent is the highest integer power of a sum which is expanded
+const maxExpandedExponent = 8
+
+// maxFoldedExponent is the highest integer exponent applied to monomials, higher powers are kept unevaluated
+const maxFoldedExponent = 1024
+
+// factor is a base raised to rational exponent, bases are variables, constants and expressions which cannot be simplified further
+type factor struct {
+	base     *node
+	exponent *big.Rat
+}
+
+// term is a product of rational coefficient and factors sorted by their keys
+type term struct {
+	coefficient *big.Rat
+	factors     []factor
+}
+
+// polynomial is a sum of terms, terms with the same factors are combined
+type polynomial struct {
+	terms map[string]term
+}
+
+type simplifier struct {
+	lexers Lexers
+}
+
+// NewSimplifier returns simplifier of operations lexed by provided lexers
+func NewSimplifier(lexers Lexers) calculator.Simplifier {
+	return simplifier{lexers: lexers}
+}
+
+// Simplify lexes operation written in notation, parses and simplifies it
+func (s simplifier) Simplify(ctx context.Context, notation string, input string) (calculator.Simplification, error) {
+	lex, err := s.lexers.lexer(notation)
+	if err != nil {
+		return calculator.Simplification{}, err
+	}
+
+	operation, err := ParseItems(ctx, lex(input))
+	if err != nil {
+		return calculator.Simplification{}, err
+	}
+
+	simplified, err := Simplify(operation)
+	if err != nil {
+		return calculator.Simplification{}, err
+	}
+
+	tree, err := operationTree(simplified)
+	if err != nil {
+		return calculator.Simplification{}, err
+	}
+
+	return calculator.Simplification{Simplified: tree.format(), Tree: tree.text()}, nil
+}
+
+// Simplify folds constant sub-expressions, combines like terms and collects powers of the same bases,
+// simplified operation is equivalent to the original one wherever the original one is defined
+func Simplify(operation calculator.OperationInterface) (calculator.OperationInterface, error) {
+	tree, err := operationTree(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	simplified, err := tree.simplify()
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpnOperation{simplified.items()}, nil
+}
+
+func (n *node) simplify() (*node, error) {
+	p, err := n.polynomial()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.node(), nil
+}
+
+// polynomial converts node to canonical sum of products
+func (n *node) polynomial() (polynomial, error) {
+	switch {
+	case isNumber(n.item):
+		if r, ok := new(big.Rat).SetString(n.item.GetString()); ok {
+			return constantPolynomial(r), nil
+		}
+		return atomPolynomial(n), nil
+	case isVariable(n.item):
+		return atomPolynomial(n), nil
+	case isNegation(n.item):
+		p, err := n.children[0].polynomial()
+		if err != nil {
+			return polynomial{}, err
+		}
+		return p.negate(), nil
+	case isFunction(n.item):
+		return n.functionPolynomial()
+	case isMathOperator(n.item):
+		left, err := n.children[0].polynomial()
+		if err != nil {
+			return polynomial{}, err
+		}
+
+		right, err := n.children[1].polynomial()
+		if err != nil {
+			return polynomial{}, err
+		}
+
+		switch n.item.GetType() {
+		case lexer.Addition:
+			return left.add(right), nil
+		case lexer.Subtraction:
+			return left.add(right.negate()), nil
+		case lexer.Multiplication:
+			return left.multiply(right), nil
+		case lexer.Division:
+			return left.divide(right)
+		default:
+			return left.power(right)
+		}
+	default:
+		return polynomial{}, errors.NewCalculationError(fmt.Sprintf("invalid item in the RPN operation: %s", n.item.GetString()))
+	}
+}
+
+// functionPolynomial folds functions of constants if result is an integer, otherwise function of simplified argument is kept
+func (n *node) functionPolynomial() (polynomial, error) {
+	argument, err := n.children[0].polynomial()
+	if err != nil {
+		return polynomial{}, err
+	}
+
+	if n.item.GetString() == "ln" && argument.node().format() == "e" {
+		return constantPolynomial(big.NewRat(1, 1)), nil
+	}
+
+	if c, ok := argument.constant(); ok {
+		value, _ := c.Float64()
+		if r, ok := exactRat(functions[n.item.GetString()](value)); ok {
+			return constantPolynomial(r), nil
+		}
+	}
+
+	return atomPolynomial(functionNode(n.item.GetString(), argument.node())), nil
+}
+
+func constantPolynomial(r *big.Rat) polynomial {
+	p := polynomial{terms: map[string]term{}}
+	p.addTerm(term{coefficient: r, factors: []factor{}})
+
+	return p
+}
+
+func atomPolynomial(n *node) polynomial {
+	return factorPolynomial(factor{base: n, exponent: big.NewRat(1, 1)})
+}
+
+func factorPolynomial(f factor) polynomial {
+	p := polynomial{terms: map[string]term{}}
+	p.addTerm(term{coefficient: big.NewRat(1, 1), factors: []factor{f}})
+
+	return p
+}
+
+// addTerm adds term to the polynomial, terms with zero coefficients are removed
+func (p polynomial) addTerm(t term) {
+	key := t.key()
+
+	if existing, ok := p.terms[key]; ok {
+		t = term{coefficient: new(big.Rat).Add(existing.coefficient, t.coefficient), factors: t.factors}
+	}
+
+	if t.coefficient.Sign() == 0 {
+		delete(p.terms, key)
+		return
+	}
+
+	p.terms[key] = t
+}
+
+func (p polynomial) add(other polynomial) polynomial {
+	result := polynomial{terms: map[string]term{}}
+	for _, t := range p.terms {
+		result.addTerm(t)
+	}
+	for _, t := range other.terms {
+		result.addTerm(t)
+	}
+
+	return result
+}
+
+func (p polynomial) negate() polynomial {
+	result := polynomial{terms: map[string]term{}}
+	for _, t := range p.terms {
+		result.addTerm(term{coefficient: new(big.Rat).Neg(t.coefficient), factors: t.factors})
+	}
+
+	return result
+}
+
+func (p polynomial) multiply(other polynomial) polynomial {
+	if len(p.terms)*len(other.terms) > maxExpandedTerms {
+		return atomPolynomial(p.node()).multiply(atomPolynomial(other.node()))
+	}
+
+	result := polynomial{terms: map[string]term{}}
+	for _, t1 := range p.terms {
+		for _, t2 := range other.terms {
+			result.addTerm(t1.multiply(t2))
+		}
+	}
+
+	return result
+}
+
+// divide multiplies by inverse of monomials, divisions by sums are kept unexpanded
+func (p polynomial) divide(other polynomial) (polynomial, error) {
+	if len(other.terms) == 0 {
+		return polynomial{}, errors.NewCalculationError("division by zero")
+	}
+
+	if len(other.terms) > 1 {
+		if p.node().format() == other.node().format() {
+			return constantPolynomial(big.NewRat(1, 1)), nil
+		}
+
+		inverse := factorPolynomial(factor{base: other.node(), exponent: big.NewRat(-1, 1)})
+		if len(p.terms) > 1 {
+			return atomPolynomial(p.node()).multiply(inverse), nil
+		}
+
+		return p.multiply(inverse), nil
+	}
+
+	inverse, err := other.single().power(big.NewRat(-1, 1))
+	if err != nil {
+		return polynomial{}, err
+	}
+
+	return p.multiply(inverse), nil
+}
+
+// power expands integer powers of sums and collects exponents of monomials, other powers are kept as factors
+func (p polynomial) power(exponent polynomial) (polynomial, error) {
+	e, ok := exponent.constant()
+	if !ok {
+		return atomPolynomial(operatorNode(lexer.Exponent, p.node(), exponent.node())), nil
+	}
+
+	switch {
+	case e.Sign() == 0:
+		return constantPolynomial(big.NewRat(1, 1)), nil
+	case len(p.terms) == 0 && e.Sign() < 0:
+		return polynomial{}, errors.NewCalculationError("division by zero")
+	case len(p.terms) == 0:
+		return p, nil
+	case len(p.terms) == 1:
+		return p.single().power(e)
+	case e.IsInt() && e.Sign() > 0 && e.Num().Cmp(big.NewInt(maxExpandedExponent)) <= 0:
+		result := p
+		for k := int64(1); k < e.Num().Int64(); k++ {
+			result = result.multiply(p)
+		}
+		return result, nil
+	default:
+		return factorPolynomial(factor{base: p.node(), exponent: e}), nil
+	}
+}
+
+// constant returns value of the polynomial if it does not depend on any factor
+func (p polynomial) constant() (*big.Rat, bool) {
+	switch len(p.terms) {
+	case 0:
+		return new(big.Rat), true
+	case 1:
+		t := p.single()
+		return t.coefficient, len(t.factors) == 0
+	default:
+		return nil, false
+	}
+}
+
+// single returns the only term of polynomial
+func (p polynomial) single() term {
+	for _, t := range p.terms {
+		return t
+	}
+
+	return term{coefficient: new(big.Rat), factors: []factor{}}
+}
+
+// sortedTerms returns terms ordered by descending degree, terms of the same degree are ordered by their keys
+// and constant term is the last one
+func (p polynomial) sortedTerms() []term {
+	terms := []term{}
+	for _, t := range p.terms {
+		terms = append(terms, t)
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if c := terms[i].degree().Cmp(terms[j].degree()); c != 0 {
+			return c > 0
+		}
+		if len(terms[i].factors) == 0 || len(terms[j].factors) == 0 {
+			return len(terms[i].factors) > len(terms[j].factors)
+		}
+		return terms[i].key() < terms[j].key()
+	})
+
+	return terms
+}
+
+// node converts polynomial to operation tree, negative coefficients are written as subtractions
+func (p polynomial) node() *node {
+	terms := p.sortedTerms()
+	if len(terms) == 0 {
+		return numberNode(new(big.Rat))
+	}
+
+	result := terms[0].node(true)
+	for _, t := range terms[1:] {
+		operator := lexer.Addition
+		if t.coefficient.Sign() < 0 {
+			operator = lexer.Subtraction
+		}
+		result = operatorNode(operator, result, t.node(false))
+	}
+
+	return result
+}
+
+func (t term) multiply(other term) term {
+	result := term{coefficient: new(big.Rat).Mul(t.coefficient, other.coefficient), factors: []factor{}}
+
+	exponents := map[string]factor{}
+	for _, f := range append(append([]factor{}, t.factors...), other.factors...) {
+		key := f.base.format()
+		if existing, ok := exponents[key]; ok {
+			f = factor{base: f.base, exponent: new(big.Rat).Add(existing.exponent, f.exponent)}
+		}
+		exponents[key] = f
+	}
+
+	for _, f := range exponents {
+		if r, ok := f.base.rat(); ok && isFoldedExponent(f.exponent) && (r.Sign() != 0 || f.exponent.Sign() >= 0) {
+			result.coefficient.Mul(result.coefficient, ratPower(r, f.exponent.Num().Int64()))
+			continue
+		}
+		if f.exponent.Sign() != 0 {
+			result.factors = append(result.factors, f)
+		}
+	}
+	result.sortFactors()
+
+	return result
+}
+
+// power raises monomial to constant exponent, coefficients are folded only if result is exact
+func (t term) power(e *big.Rat) (polynomial, error) {
+	if t.coefficient.Sign() == 0 && e.Sign() < 0 {
+		return polynomial{}, errors.NewCalculationError("division by zero")
+	}
+
+	if !e.IsInt() {
+		if len(t.factors) == 0 {
+			return constantPower(t.coefficient, e), nil
+		}
+		return factorPolynomial(factor{base: t.node(true), exponent: e}), nil
+	}
+
+	if !isFoldedExponent(e) {
+		return factorPolynomial(factor{base: t.node(true), exponent: e}), nil
+	}
+
+	result := term{coefficient: ratPower(t.coefficient, e.Num().Int64()), factors: []factor{}}
+	for _, f := range t.factors {
+		result.factors = append(result.factors, factor{base: f.base, exponent: new(big.Rat).Mul(f.exponent, e)})
+	}
+
+	p := polynomial{terms: map[string]term{}}
+	p.addTerm(result)
+
+	return p, nil
+}
+
+// constantPower folds constant raised to fractional exponent if result is rational, i.e. 4 ^ (1 / 2)
+func constantPower(base *big.Rat, e *big.Rat) polynomial {
+	b, _ := base.Float64()
+	f, _ := e.Float64()
+	if r, ok := exactRat(math.Pow(b, f)); ok {
+		return constantPolynomial(r)
+	}
+
+	return factorPolynomial(factor{base: numberNode(base), exponent: e})
+}
+
+// isFoldedExponent returns true if exponent is an integer small enough to be applied to rational numbers
+func isFoldedExponent(e *big.Rat) bool {
+	return e.IsInt() && e.Num().IsInt64() && e.Num().Int64() <= maxFoldedExponent && e.Num().Int64() >= -maxFoldedExponent
+}
+
+func ratPower(r *big.Rat, n int64) *big.Rat {
+	if n < 0 {
+		r, n = new(big.Rat).Inv(r), -n
+	}
+
+	exponent := big.NewInt(n)
+	num := new(big.Int).Exp(r.Num(), exponent, nil)
+	denom := new(big.Int).Exp(r.Denom(), exponent, nil)
+
+	return new(big.Rat).SetFrac(num, denom)
+}
+
+// exactRat converts float to rational if it is an integer which can be represented exactly
+func exactRat(v float64) (*big.Rat, bool) {
+	if math.IsNaN(v) || math.IsInf(v, 0) || v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+		return nil, false
+	}
+
+	return new(big.Rat).SetFloat64(v), true
+}
+
+func (t term) sortFactors() {
+	sort.Slice(t.factors, func(i, j int) bool {
+		return t.factors[i].key() < t.factors[j].key()
+	})
+}
+
+// key identifies terms which can be combined, i.e. having the same factors
+func (t term) key() string {
+	keys := []string{}
+	for _, f := range t.factors {
+		keys = append(keys, f.node().format())
+	}
+
+	return strings.Join(keys, " * ")
+}
+
+func (t term) degree() *big.Rat {
+	degree := new(big.Rat)
+	for _, f := range t.factors {
+		degree.Add(degree, f.exponent)
+	}
+
+	return degree
+}
+
+// node converts term to operation tree, factors with negative exponents form a denominator,
+// the sign of coefficient is written only if signed is true
+func (t term) node(signed bool) *node {
+	coefficient := new(big.Rat).Abs(t.coefficient)
+	numerator, denominator := []*node{}, []*node{}
+
+	if !isTerminating(coefficient) {
+		denominator = append(denominator, numberNode(new(big.Rat).SetInt(coefficient.Denom())))
+		coefficient = new(big.Rat).SetInt(coefficient.Num())
+	}
+
+	if coefficient.Cmp(big.NewRat(1, 1)) != 0 || len(t.factors) == 0 {
+		numerator = append(numerator, numberNode(coefficient))
+	}
+
+	for _, f := range t.factors {
+		if f.exponent.Sign() < 0 {
+			denominator = append(denominator, factor{base: f.base, exponent: new(big.Rat).Neg(f.exponent)}.node())
+			continue
+		}
+		numerator = append(numerator, f.node())
+	}
+
+	if len(numerator) == 0 {
+		numerator = append(numerator, numberNode(big.NewRat(1, 1)))
+	}
+
+	// negation of the leftmost operand keeps the same tree when formatted and parsed again, i.e. -x ^ 2 * y
+	if signed && t.coefficient.Sign() < 0 {
+		numerator[0] = negationNode(numerator[0])
+	}
+
+	if len(denominator) == 0 {
+		return multiplyNodes(numerator)
+	}
+
+	return operatorNode(lexer.Division, multiplyNodes(numerator), multiplyNodes(denominator))
+}
+
+func (f factor) node() *node {
+	if f.exponent.Cmp(big.NewRat(1, 1)) == 0 {
+		return f.base
+	}
+
+	return operatorNode(lexer.Exponent, f.base, numberNode(f.exponent))
+}
+
+func (f factor) key() string {
+	return f.base.format()
+}
+
+// multiplyNodes returns product of nodes multiplied from left to right
+func multiplyNodes(nodes []*node) *node {
+	result := nodes[0]
+	for _, n := range nodes[1:] {
+		result = operatorNode(lexer.Multiplication, result, n)
+	}
+
+	return result
+}
+
+// numberNode returns node of rational number, numbers without finite decimal representation are written as divisions
+func numberNode(r *big.Rat) *node {
+	// negative numbers are negations, so they are parenthesized when formatted, i.e. (-8) ^ (1 / 3)
+	if r.Sign() < 0 {
+		return negationNode(numberNode(new(big.Rat).Neg(r)))
+	}
+
+	if !isTerminating(r) {
+		return operatorNode(lexer.Division, numberNode(new(big.Rat).SetInt(r.Num())), numberNode(new(big.Rat).SetInt(r.Denom())))
+	}
+
+	value, _ := r.Float64()
+	return &node{item: numericItem{decimalString(r), value}}
+}
+
+// rat returns value of node which is a number or a negated number
+func (n *node) rat() (*big.Rat, bool) {
+	if isNegation(n.item) {
+		r, ok := n.children[0].rat()
+		if !ok {
+			return nil, false
+		}
+		return r.Neg(r), true
+	}
+
+	if !isNumber(n.item) {
+		return nil, false
+	}
+
+	return new(big.Rat).SetString(n.item.GetString())
+}
+
+// isTerminating returns true if rational number has finite decimal representation
+func isTerminating(r *big.Rat) bool {
+	_, ok := bigmath.DecimalDigits(r.Denom())
+
+	return ok
+}
+
+func decimalString(r *big.Rat) string {
+	digits, _ := bigmath.DecimalDigits(r.Denom())
+
+	return r.FloatString(digits)
+}
+
+// items returns items of the tree in postfix order
+func (n *node) items() []lexer.Item {
+	items := []lexer.Item{}
+	for _, c := range n.children {
+		items = append(items, c.items()...)
+	}
+
+	return append(items, n.item)
+}
@@ -0,0 +1,128 @@
+package reversepolish
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      string
+		expectedError error
+	}{
+		{"Constant folding", "1 + 2 * 3 - 4 / 8", "6.5", nil},
+		{"Multiplication by one", "x * 1", "x", nil},
+		{"Addition of zero", "0 + x", "x", nil},
+		{"Subtraction of itself", "x - x", "0", nil},
+		{"Power of one", "x ^ 1", "x", nil},
+		{"Power of zero", "(x + y) ^ 0", "1", nil},
+		{"Combining like terms", "2*x + y + 3*x - y", "5 * x", nil},
+		{"Collecting powers", "x * x^2 * y / x", "x ^ 2 * y", nil},
+		{"Expanding products", "2*(x+1) - 2", "2 * x", nil},
+		{"Expanding powers", "(x + 1)^2", "x ^ 2 + 2 * x + 1", nil},
+		{"Negative terms", "-x + y - 2*z", "-x + y - 2 * z", nil},
+		{"Non terminating fractions", "2*x/3", "2 * x / 3", nil},
+		{"Negative exponents", "y * x^-2", "y / x ^ 2", nil},
+		{"Division by sum", "x / (x + 1) + (y + 1)/(y + 1)", "x / (x + 1) + 1", nil},
+		{"Folding exact functions", "sqrt(16) * x + sqrt(2)", "sqrt(2) + 4 * x", nil},
+		{"Function of simplified argument", "sin(x + x) - sin(2*x)", "0", nil},
+		{"Exact decimals", "0.1 + 0.2", "0.3", nil},
+		{"Constants", "pi * 2 + pi", "3 * pi", nil},
+		{"Large exponents are not folded", "2^100000000000*3", "3 * 2 ^ 100000000000", nil},
+		{"Powers of sums above int64 are not expanded", "(x+1)^18446744073709551616", "(x + 1) ^ 18446744073709551616", nil},
+		{"Odd powers of sums above int64 are not expanded", "(x+1)^18446744073709551619", "(x + 1) ^ 18446744073709551619", nil},
+		{"Negative bases", "(-8)^(1/3) * 2", "2 * (-8) ^ (1 / 3)", nil},
+		{"Division by zero", "x / (y - y)", "", errors.NewCalculationError("division by zero")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result string
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err == nil {
+				operation, err = Simplify(operation)
+			}
+			if err == nil {
+				result, err = Format(operation)
+			}
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil && !strings.Contains(err.Error(), tt.expectedError.Error()) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("expected result to be %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSimplifyKeepsValue(t *testing.T) {
+	tests := []string{
+		"(-8)^(1/3) * 2",
+		"(-1)^2000 * 2",
+		"3 * (-1/3)^3",
+		"(-2)^-2 + 1",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			simplified, err := Simplify(operation)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			formatted, err := Format(simplified)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			parsed, err := ParseInfix(context.Background(), formatted)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			expected, _ := operation.Calculate(context.Background())
+			result, _ := parsed.Calculate(context.Background())
+			if math.Abs(expected-result) > 1e-9 && !(math.IsNaN(expected) && math.IsNaN(result)) {
+				t.Errorf("expected value of %s to be %v, got %v", formatted, expected, result)
+			}
+		})
+	}
+}
+
+func TestSimplifier(t *testing.T) {
+	s := NewSimplifier(Lexers{calculator.InfixNotation: lexer.Lex})
+
+	simplification, err := s.Simplify(context.Background(), "", "x + 2*x")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	expected := calculator.Simplification{Simplified: "3 * x", Tree: "*\n├── 3\n└── x\n"}
+	if simplification != expected {
+		t.Errorf("expected simplification to be %v, got %v", expected, simplification)
+	}
+
+	_, err = s.Simplify(context.Background(), "latex", "x")
+	if err == nil || !strings.Contains(err.Error(), "Unknown notation: latex") {
+		t.Errorf("expected unknown notation error, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package reversepolish
+
+import (
+	"fmt"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+)
+
+// node of operation tree, operators and functions have their operands as children
+type node struct {
+	item     lexer.Item
+	children []*node
+}
+
+// buildTree builds operation tree from items in postfix order
+func buildTree(items []lexer.Item) (*node, error) {
+	stack := []*node{}
+
+	for _, i := range items {
+		arity := getArity(i)
+		if arity == 0 && !isNumber(i) && !isVariable(i) && !isTimeLiteral(i) {
+			return nil, errors.NewParsingError(fmt.Sprintf("invalid item in the RPN operation: %s", i.GetString()))
+		}
+
+		if len(stack) < arity {
+			return nil, errors.NewParsingError("not enough operands on stack")
+		}
+
+		n := &node{item: i, children: append([]*node{}, stack[len(stack)-arity:]...)}
+		stack = append(stack[:len(stack)-arity], n)
+	}
+
+	if len(stack) != 1 {
+		return nil, errors.NewParsingError("operation has to consist of exactly one expression")
+	}
+
+	return stack[0], nil
+}
+
+// operationTree builds tree of operation parsed by this package
+func operationTree(operation calculator.OperationInterface) (*node, error) {
+	o, ok := operation.(*rpnOperation)
+	if !ok {
+		return nil, errors.NewCalcError(fmt.Sprintf("unsupported operation type: %T", operation))
+	}
+
+	return buildTree(o.items)
+}
@@ -0,0 +1,280 @@
+package reversepolish
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+	"github.com/mateuszkrasucki/calculator/pkg/lexer"
+	"github.com/mateuszkrasucki/calculator/pkg/units"
+)
+
+// defaultUnitsDigits hides rounding errors of conversions, e.g. 0.1 km + 200 m is written as 0.3 km
+const defaultUnitsDigits = 15
+
+// unitsArithmetic calculates with float64 quantities, names of units are resolved by the mode, so 5 km is 5 * km,
+// sums are written in unit of the left operand and products in units of both operands, e.g. 686.7 m*kg/s^2,
+// products of units of dimensionless quantities are replaced by numbers, e.g. km/m is 1000, currency codes are units
+// if the mode has exchange rates
+type unitsArithmetic struct {
+	digits int
+	rates  *calculator.Rates
+}
+
+// quantity is a value with unit, rated quantities are calculated with exchange rates and mixed quantities
+// are sums of different currencies, e.g. USD and EUR, which have to be converted to a currency
+type quantity struct {
+	value float64
+	unit  units.Expression
+	rated bool
+	mixed string
+}
+
+type quantityValue struct {
+	quantity
+	digits    int
+	ratesDate string
+}
+
+func newUnitsArithmetic(mode calculator.Mode) (arithmetic, error) {
+	digits := defaultUnitsDigits
+	if mode.Digits > 0 {
+		digits = int(mode.Digits)
+	}
+
+	return unitsArithmetic{digits: digits, rates: mode.Rates}, nil
+}
+
+func (a unitsArithmetic) number(item lexer.Item) (interface{}, error) {
+	if value, ok := constants[item.GetString()]; ok {
+		return quantity{value: value}, nil
+	}
+
+	value, err := strconv.ParseFloat(item.GetString(), 64)
+	if err != nil {
+		return nil, errors.NewParsingErrorWrap(err, fmt.Sprintf("could not parse %s as a number", item.GetString()))
+	}
+
+	return quantity{value: value}, nil
+}
+
+func (a unitsArithmetic) name(name string) (interface{}, bool) {
+	if rate, ok := a.currency(name); ok {
+		return quantity{value: 1, unit: units.Expression{{Unit: units.Currency(name, rate), Power: 1}}, rated: true}, true
+	}
+
+	unit, ok := units.Lookup(name)
+	if !ok {
+		return nil, false
+	}
+
+	return quantity{value: 1, unit: units.Expression{{Unit: unit, Power: 1}}}, true
+}
+
+// currency returns exchange rate of currency
+func (a unitsArithmetic) currency(code string) (float64, bool) {
+	if a.rates == nil {
+		return 0, false
+	}
+
+	rate, ok := a.rates.Currencies[code]
+
+	return rate, ok
+}
+
+// with returns quantity with the same exchange rates and currencies
+func (q quantity) with(value float64, unit units.Expression) quantity {
+	return quantity{value: value, unit: unit, rated: q.rated, mixed: q.mixed}
+}
+
+// simplified replaces quantity of dimensionless product of units by a number
+func (q quantity) simplified() quantity {
+	if len(q.unit) == 0 || q.unit.Dimension() != (units.Dimension{}) {
+		return q
+	}
+
+	return q.with(q.value*q.unit.Factor(), nil)
+}
+
+// unconverted returns calculation error if quantity is a sum of different currencies
+func (q quantity) unconverted() error {
+	if q.mixed == "" {
+		return nil
+	}
+
+	return errors.NewCalculationError(fmt.Sprintf("cannot add amounts of %s without conversion, convert the result to a currency with in or to", q.mixed))
+}
+
+func (q quantity) String() string {
+	value := calculator.FormatSpecial(q.value)
+	if value == "" {
+		value = strconv.FormatFloat(q.value, 'g', -1, 64)
+	}
+	if len(q.unit) == 0 {
+		return value
+	}
+
+	return value + " " + q.unit.String()
+}
+
+// operator calculates sums of different currencies in currency of the left operand, but they have to be converted
+// before the end of calculation, so exchange rates are never used without being asked for
+func (a unitsArithmetic) operator(operator string, x, y interface{}) (interface{}, error) {
+	f, g := x.(quantity), y.(quantity)
+
+	result, err := a.calculate(operator, f, g)
+	if err != nil {
+		return nil, err
+	}
+
+	result.rated = f.rated || g.rated
+	result.mixed = f.mixed
+	if result.mixed == "" {
+		result.mixed = g.mixed
+	}
+
+	if (operator == "+" || operator == "-") && result.mixed == "" && f.unit.Dimension().IsMoney() && f.unit.String() != g.unit.String() {
+		result.mixed = fmt.Sprintf("%s and %s", f.unit, g.unit)
+	}
+
+	return result, nil
+}
+
+func (a unitsArithmetic) calculate(operator string, f, g quantity) (quantity, error) {
+	switch operator {
+	case "+", "-":
+		if f.unit.Dimension() != g.unit.Dimension() {
+			return quantity{}, errors.NewCalculationError(fmt.Sprintf("cannot calculate %s %s %s, %s and %s are different dimensions", f, operator, g, f.unit.Dimension(), g.unit.Dimension()))
+		}
+
+		value, err := units.Scale(g.value, g.unit, f.unit)
+		if err != nil {
+			return quantity{}, err
+		}
+
+		if operator == "-" {
+			value = -value
+		}
+
+		return quantity{value: f.value + value, unit: f.unit}, nil
+	case "*":
+		return quantity{value: f.value * g.value, unit: f.unit.Multiply(g.unit)}.simplified(), nil
+	case "/":
+		if g.value == 0 {
+			return quantity{}, errors.NewCalculationError("division by zero")
+		}
+		return quantity{value: f.value / g.value, unit: f.unit.Multiply(g.unit.Power(-1))}.simplified(), nil
+	case "^":
+		return a.power(f, g)
+	default:
+		return quantity{}, errors.NewCalculationError(fmt.Sprintf("unknown operator: %s", operator))
+	}
+}
+
+// power raises quantities with units only to integer powers, e.g. (3 m)^2 is 9 m^2
+func (a unitsArithmetic) power(f, g quantity) (quantity, error) {
+	if len(g.unit) > 0 {
+		return quantity{}, errors.NewCalculationError(fmt.Sprintf("exponent %s has to be a dimensionless number", g))
+	}
+
+	if f.value == 0 && g.value < 0 {
+		return quantity{}, errors.NewCalculationError("division by zero")
+	}
+
+	if len(f.unit) == 0 {
+		return quantity{value: math.Pow(f.value, g.value)}, nil
+	}
+
+	if g.value != math.Trunc(g.value) || math.Abs(g.value) > math.MaxInt32 {
+		return quantity{}, errors.NewCalculationError(fmt.Sprintf("%s can be raised only to integer powers", f.unit))
+	}
+
+	return quantity{value: math.Pow(f.value, g.value), unit: f.unit.Power(int(g.value))}.simplified(), nil
+}
+
+func (a unitsArithmetic) negate(x interface{}) (interface{}, error) {
+	f := x.(quantity)
+
+	return f.with(-f.value, f.unit), nil
+}
+
+// function keeps units of arguments of rounding functions and takes square roots of units, other functions
+// are defined only for dimensionless numbers
+func (a unitsArithmetic) function(name string, x interface{}) (interface{}, error) {
+	f := x.(quantity)
+
+	function, ok := functions[name]
+	if !ok {
+		return nil, errors.NewCalculationError(fmt.Sprintf("unknown function: %s", name))
+	}
+
+	switch {
+	case len(f.unit) == 0:
+		return f.with(function(f.value), nil), nil
+	case name == "sqrt":
+		unit, ok := f.unit.Root(2)
+		if !ok {
+			return nil, errors.NewCalculationError(fmt.Sprintf("cannot calculate square root of %s", f.unit))
+		}
+		return f.with(function(f.value), unit), nil
+	case name == "abs", name == "floor", name == "ceil", name == "re", name == "im", name == "conj":
+		return f.with(function(f.value), f.unit), nil
+	default:
+		return nil, errors.NewCalculationError(fmt.Sprintf("function %s requires a dimensionless argument, got %s", name, f.unit))
+	}
+}
+
+// convert writes quantity in units of target, target has to be a product of units, e.g. km/h,
+// sums of different currencies converted to a currency are no longer mixed
+func (a unitsArithmetic) convert(x, y interface{}) (interface{}, error) {
+	f, g := x.(quantity), y.(quantity)
+
+	if len(g.unit) == 0 || g.value != 1 {
+		return nil, errors.NewCalculationError(fmt.Sprintf("%s is not a unit, quantities can be converted only to units", g))
+	}
+
+	value, err := units.Convert(f.value, f.unit, g.unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return quantity{value: value, unit: g.unit, rated: f.rated || g.rated}, nil
+}
+
+func (a unitsArithmetic) value(x interface{}) (calculator.Value, error) {
+	q := x.(quantity)
+	if err := q.unconverted(); err != nil {
+		return nil, err
+	}
+
+	v := quantityValue{quantity: q, digits: a.digits}
+	if q.rated {
+		v.ratesDate = a.rates.Date
+	}
+
+	return v, nil
+}
+
+// Float64 returns value rounded to digits
+func (v quantityValue) Float64() float64 {
+	value, _ := strconv.ParseFloat(strconv.FormatFloat(v.value, 'g', v.digits, 64), 64)
+
+	return value
+}
+
+// String writes value rounded to digits followed by its unit, e.g. 96.56064 km/h
+func (v quantityValue) String() string {
+	return quantity{value: v.Float64() + 0, unit: v.unit}.String()
+}
+
+// Quantity returns value with its unit, dimension and date of exchange rates, it is nil for dimensionless numbers
+// calculated without exchange rates
+func (v quantityValue) Quantity() *calculator.Quantity {
+	if len(v.unit) == 0 && v.ratesDate == "" {
+		return nil
+	}
+
+	return &calculator.Quantity{Value: v.Float64(), Unit: v.unit.String(), Dimension: v.unit.Dimension().String(), Formatted: v.String(), RatesDate: v.ratesDate}
+}
@@ -0,0 +1,148 @@
+package reversepolish
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/mateuszkrasucki/calculator/pkg/calculator"
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestCalculateUnits(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		expected         string
+		expectedQuantity *calculator.Quantity
+		expectedError    error
+	}{
+		{"Sum in unit of left operand", "5 km + 300 m", "5.3 km", &calculator.Quantity{Value: 5.3, Unit: "km", Dimension: "length", Formatted: "5.3 km"}, nil},
+		{"Conversion", "60 mph to km/h", "96.56064 km/h", &calculator.Quantity{Value: 96.56064, Unit: "km/h", Dimension: "velocity", Formatted: "96.56064 km/h"}, nil},
+		{"Product of units", "9.81 m/s^2 * 70 kg", "686.7 m*kg/s^2", &calculator.Quantity{Value: 686.7, Unit: "m*kg/s^2", Dimension: "force", Formatted: "686.7 m*kg/s^2"}, nil},
+		{"Conversion of product to derived unit", "9.81 m/s^2 * 70 kg in N", "686.7 N", &calculator.Quantity{Value: 686.7, Unit: "N", Dimension: "force", Formatted: "686.7 N"}, nil},
+		{"Temperature", "100 degF in degC", "37.7777777777778 degC", &calculator.Quantity{Value: 37.7777777777778, Unit: "degC", Dimension: "temperature", Formatted: "37.7777777777778 degC"}, nil},
+		{"Absolute temperature", "-40 degC to K", "233.15 K", &calculator.Quantity{Value: 233.15, Unit: "K", Dimension: "temperature", Formatted: "233.15 K"}, nil},
+		{"Difference of temperatures", "20 degC + 9 degF", "25 degC", &calculator.Quantity{Value: 25, Unit: "degC", Dimension: "temperature", Formatted: "25 degC"}, nil},
+		{"Imperial units", "6 ft + 2 inch to cm", "187.96 cm", &calculator.Quantity{Value: 187.96, Unit: "cm", Dimension: "length", Formatted: "187.96 cm"}, nil},
+		{"Prefixed units", "1 kWh in MJ", "3.6 MJ", &calculator.Quantity{Value: 3.6, Unit: "MJ", Dimension: "energy", Formatted: "3.6 MJ"}, nil},
+		{"Power of unit", "(3 m) ^ 2 + 1 ha to m^2", "10009 m^2", &calculator.Quantity{Value: 10009, Unit: "m^2", Dimension: "area", Formatted: "10009 m^2"}, nil},
+		{"Square root", "sqrt(16 m^2)", "4 m", &calculator.Quantity{Value: 4, Unit: "m", Dimension: "length", Formatted: "4 m"}, nil},
+		{"Units of denominator", "2 J / (kg * K)", "2 J/(kg*K)", &calculator.Quantity{Value: 2, Unit: "J/(kg*K)", Dimension: "length^2/(time^2*temperature)", Formatted: "2 J/(kg*K)"}, nil},
+		{"Quotient of quantities", "10 km / 2 h", "5 km/h", &calculator.Quantity{Value: 5, Unit: "km/h", Dimension: "velocity", Formatted: "5 km/h"}, nil},
+		{"Dimensionless ratio", "1 km / (250 m)", "4", nil, nil},
+		{"Angle", "sin(30 deg)", "0.5", nil, nil},
+		{"Digits hide rounding errors", "0.1 km + 200 m", "0.3 km", &calculator.Quantity{Value: 0.3, Unit: "km", Dimension: "length", Formatted: "0.3 km"}, nil},
+		{"Number which is not finite", "exp(1000)", "Infinity", nil, nil},
+		{"Quantity which is not finite", "exp(1000) m", "Infinity m", &calculator.Quantity{Value: math.Inf(1), Unit: "m", Dimension: "length", Formatted: "Infinity m"}, nil},
+		{"Different dimensions", "3 m + 2 s", "", nil, errors.NewCalculationError("cannot calculate 3 m + 2 s, length and time are different dimensions")},
+		{"Conversion to different dimension", "5 km to h", "", nil, errors.NewCalculationError("cannot convert km to h, length and time are different dimensions")},
+		{"Conversion to number", "5 km to 1000", "", nil, errors.NewCalculationError("1000 is not a unit")},
+		{"Function of quantity", "ln(5 m)", "", nil, errors.NewCalculationError("function ln requires a dimensionless argument, got m")},
+		{"Fractional power", "(2 m) ^ 0.5", "", nil, errors.NewCalculationError("m can be raised only to integer powers")},
+		{"Unknown unit", "5 parsec", "", nil, errors.NewCalculationError("unbound variable: parsec")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), calculator.Mode{Name: calculator.UnitsMode})
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if value.String() != tt.expected {
+				t.Errorf("expected result to be %s, got %s", tt.expected, value.String())
+			}
+
+			if quantity := value.(calculator.QuantityValue).Quantity(); !cmp.Equal(tt.expectedQuantity, quantity) {
+				t.Errorf("expected quantity to be %v, got %v", tt.expectedQuantity, quantity)
+			}
+		})
+	}
+}
+
+func TestCalculateConversionOutsideUnitsMode(t *testing.T) {
+	operation, err := ParseInfix(context.Background(), "5 in 1")
+	if err != nil {
+		t.Fatalf("expected error to be nil, got %v", err)
+	}
+
+	expectedError := errors.NewCalculationError("units can be converted only in units mode")
+
+	if _, err := operation.Calculate(context.Background()); err == nil || !strings.Contains(err.Error(), expectedError.Error()) {
+		t.Errorf("expected error to be %v, got %v", expectedError, err)
+	}
+
+	if _, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), calculator.Mode{Name: calculator.ComplexMode}); err == nil || !strings.Contains(err.Error(), expectedError.Error()) {
+		t.Errorf("expected error to be %v, got %v", expectedError, err)
+	}
+}
+
+func TestCalculateCurrencies(t *testing.T) {
+	rates := &calculator.Rates{Date: "2026-10-16", Currencies: map[string]float64{"EUR": 1, "USD": 1.08, "PLN": 4.32}}
+
+	tests := []struct {
+		name             string
+		input            string
+		rates            *calculator.Rates
+		expected         string
+		expectedQuantity *calculator.Quantity
+		expectedError    error
+	}{
+		{"Converted sum of currencies", "100 USD + 50 EUR in PLN", rates, "616 PLN", &calculator.Quantity{Value: 616, Unit: "PLN", Dimension: "money", Formatted: "616 PLN", RatesDate: "2026-10-16"}, nil},
+		{"Sum of the same currency", "20 USD + 5 USD", rates, "25 USD", &calculator.Quantity{Value: 25, Unit: "USD", Dimension: "money", Formatted: "25 USD", RatesDate: "2026-10-16"}, nil},
+		{"Price per unit", "12 PLN/kg * 2.5 kg to EUR", rates, "6.94444444444444 EUR", &calculator.Quantity{Value: 6.94444444444444, Unit: "EUR", Dimension: "money", Formatted: "6.94444444444444 EUR", RatesDate: "2026-10-16"}, nil},
+		{"Ratio of currencies", "100 EUR / (108 USD)", rates, "1", &calculator.Quantity{Value: 1, Unit: "", Dimension: "dimensionless", Formatted: "1", RatesDate: "2026-10-16"}, nil},
+		{"Ratio of currencies without parentheses", "100 USD / 50 EUR", rates, "1.85185185185185", &calculator.Quantity{Value: 1.85185185185185, Unit: "", Dimension: "dimensionless", Formatted: "1.85185185185185", RatesDate: "2026-10-16"}, nil},
+		{"Unconverted sum of currencies", "100 USD + 50 EUR", rates, "", nil, errors.NewCalculationError("cannot add amounts of USD and EUR without conversion")},
+		{"Currency and length", "100 USD + 5 m", rates, "", nil, errors.NewCalculationError("cannot calculate 100 USD + 5 m, money and length are different dimensions")},
+		{"Unknown currency", "100 GBP", rates, "", nil, errors.NewCalculationError("unbound variable: GBP")},
+		{"Currencies without rates", "100 USD", nil, "", nil, errors.NewCalculationError("unbound variable: USD")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation, err := ParseInfix(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("expected error to be nil, got %v", err)
+			}
+
+			value, err := operation.(calculator.ModeOperation).CalculateMode(context.Background(), calculator.Mode{Name: calculator.UnitsMode, Rates: tt.rates})
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if value.String() != tt.expected {
+				t.Errorf("expected result to be %s, got %s", tt.expected, value.String())
+			}
+
+			if quantity := value.(calculator.QuantityValue).Quantity(); !cmp.Equal(tt.expectedQuantity, quantity) {
+				t.Errorf("expected quantity to be %v, got %v", tt.expectedQuantity, quantity)
+			}
+		})
+	}
+}
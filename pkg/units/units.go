@@ -0,0 +1,356 @@
+// Package units provides registry of units of measure, SI units with prefixes, derived units and imperial units,
+// and products of units with their dimensions, quantities are converted between units of the same dimension,
+// currencies are units of money with factors given by exchange rates
+package units
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+// Dimension is a vector of exponents of SI base quantities: length, mass, time, electric current,
+// temperature, amount of substance and luminous intensity, followed by exponent of money
+type Dimension [8]int
+
+// Unit is a unit of measure, factor is its value in SI base units and offset is the value of its zero
+// in SI base units, offset is not zero only for temperatures, e.g. degC
+type Unit struct {
+	Name      string
+	Factor    float64
+	Offset    float64
+	Dimension Dimension
+}
+
+// definition is a unit in registry, prefixed units are SI units which can be written with SI prefixes, e.g. km
+type definition struct {
+	Unit
+	prefixed bool
+}
+
+var (
+	dimensionless = Dimension{}
+	length        = Dimension{1, 0, 0, 0, 0, 0, 0, 0}
+	mass          = Dimension{0, 1, 0, 0, 0, 0, 0, 0}
+	duration      = Dimension{0, 0, 1, 0, 0, 0, 0, 0}
+	current       = Dimension{0, 0, 0, 1, 0, 0, 0, 0}
+	temperature   = Dimension{0, 0, 0, 0, 1, 0, 0, 0}
+	amount        = Dimension{0, 0, 0, 0, 0, 1, 0, 0}
+	luminosity    = Dimension{0, 0, 0, 0, 0, 0, 1, 0}
+	money         = Dimension{0, 0, 0, 0, 0, 0, 0, 1}
+	area          = Dimension{2, 0, 0, 0, 0, 0, 0, 0}
+	volume        = Dimension{3, 0, 0, 0, 0, 0, 0, 0}
+	frequency     = Dimension{0, 0, -1, 0, 0, 0, 0, 0}
+	velocity      = Dimension{1, 0, -1, 0, 0, 0, 0, 0}
+	acceleration  = Dimension{1, 0, -2, 0, 0, 0, 0, 0}
+	force         = Dimension{1, 1, -2, 0, 0, 0, 0, 0}
+	pressure      = Dimension{-1, 1, -2, 0, 0, 0, 0, 0}
+	energy        = Dimension{2, 1, -2, 0, 0, 0, 0, 0}
+	power         = Dimension{2, 1, -3, 0, 0, 0, 0, 0}
+	charge        = Dimension{0, 0, 1, 1, 0, 0, 0, 0}
+	voltage       = Dimension{2, 1, -3, -1, 0, 0, 0, 0}
+	resistance    = Dimension{2, 1, -3, -2, 0, 0, 0, 0}
+)
+
+// baseNames are names of SI base quantities in order of dimension exponents
+var baseNames = [8]string{"length", "mass", "time", "current", "temperature", "amount", "luminous intensity", "money"}
+
+// dimensionNames are names of derived quantities, other dimensions are written as products of base quantities
+var dimensionNames = map[Dimension]string{
+	dimensionless: "dimensionless",
+	area:          "area",
+	volume:        "volume",
+	frequency:     "frequency",
+	velocity:      "velocity",
+	acceleration:  "acceleration",
+	force:         "force",
+	pressure:      "pressure",
+	energy:        "energy",
+	power:         "power",
+	charge:        "charge",
+	voltage:       "voltage",
+	resistance:    "resistance",
+}
+
+// prefixes are SI prefixes, u is micro
+var prefixes = map[string]float64{
+	"Y": 1e24, "Z": 1e21, "E": 1e18, "P": 1e15, "T": 1e12, "G": 1e9, "M": 1e6, "k": 1e3, "h": 1e2, "da": 1e1,
+	"d": 1e-1, "c": 1e-2, "m": 1e-3, "u": 1e-6, "n": 1e-9, "p": 1e-12, "f": 1e-15, "a": 1e-18,
+}
+
+// registry of units by name, inch is not named in because in is the conversion operator
+var registry = map[string]definition{}
+
+func init() {
+	define := func(name string, factor float64, dimension Dimension, prefixed bool) {
+		registry[name] = definition{Unit{Name: name, Factor: factor, Dimension: dimension}, prefixed}
+	}
+
+	define("m", 1, length, true)
+	define("g", 1e-3, mass, true)
+	define("s", 1, duration, true)
+	define("A", 1, current, true)
+	define("K", 1, temperature, true)
+	define("mol", 1, amount, true)
+	define("cd", 1, luminosity, true)
+
+	define("Hz", 1, frequency, true)
+	define("N", 1, force, true)
+	define("Pa", 1, pressure, true)
+	define("J", 1, energy, true)
+	define("W", 1, power, true)
+	define("C", 1, charge, true)
+	define("V", 1, voltage, true)
+	define("Ohm", 1, resistance, true)
+	define("L", 1e-3, volume, true)
+	define("l", 1e-3, volume, true)
+	define("Wh", 3600, energy, true)
+	define("eV", 1.602176634e-19, energy, true)
+	define("cal", 4.184, energy, true)
+	define("bar", 1e5, pressure, true)
+	define("t", 1e3, mass, false)
+
+	define("min", 60, duration, false)
+	define("h", 3600, duration, false)
+	define("day", 86400, duration, false)
+	define("week", 7*86400, duration, false)
+	define("year", 365.25*86400, duration, false)
+	define("ha", 1e4, area, false)
+	define("rad", 1, dimensionless, false)
+	define("deg", math.Pi/180, dimensionless, false)
+	define("atm", 101325, pressure, false)
+
+	define("inch", 0.0254, length, false)
+	define("ft", 0.3048, length, false)
+	define("yd", 0.9144, length, false)
+	define("mi", 1609.344, length, false)
+	define("nmi", 1852, length, false)
+	define("acre", 4046.8564224, area, false)
+	define("gal", 3.785411784e-3, volume, false)
+	define("lb", 0.45359237, mass, false)
+	define("oz", 0.028349523125, mass, false)
+	define("mph", 0.44704, velocity, false)
+	define("kn", 1852.0/3600, velocity, false)
+	define("psi", 6894.757293168361, pressure, false)
+	define("hp", 745.6998715822702, power, false)
+
+	registry["degC"] = definition{Unit{Name: "degC", Factor: 1, Offset: 273.15, Dimension: temperature}, false}
+	registry["degF"] = definition{Unit{Name: "degF", Factor: 5.0 / 9, Offset: 273.15 - 32*5.0/9, Dimension: temperature}, false}
+}
+
+// Lookup returns unit with the given name, names of units without definition are resolved as prefixed SI units
+func Lookup(name string) (Unit, bool) {
+	if d, ok := registry[name]; ok {
+		return d.Unit, true
+	}
+
+	for prefix, factor := range prefixes {
+		d, ok := registry[strings.TrimPrefix(name, prefix)]
+		if !ok || !d.prefixed || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		unit := d.Unit
+		unit.Name = name
+		unit.Factor *= factor
+
+		return unit, true
+	}
+
+	return Unit{}, false
+}
+
+// Currency returns unit of currency with exchange rate, rate is the amount of currency worth one unit
+// of base currency of rates
+func Currency(code string, rate float64) Unit {
+	return Unit{Name: code, Factor: 1 / rate, Dimension: money}
+}
+
+// IsMoney returns true if dimension includes money, e.g. price of a kilogram
+func (d Dimension) IsMoney() bool {
+	return d[7] != 0
+}
+
+// String returns name of dimension or product of base quantities, e.g. length/time^3
+func (d Dimension) String() string {
+	if name, ok := dimensionNames[d]; ok {
+		return name
+	}
+
+	names := make([]string, len(d))
+	for i := range d {
+		names[i] = baseNames[i]
+	}
+
+	return product(names, d[:])
+}
+
+// Term is a unit raised to a power, e.g. s^-2
+type Term struct {
+	Unit  Unit
+	Power int
+}
+
+// Expression is a product of units, units are kept as they are written in operations, e.g. km/h,
+// so results are written in units of operands
+type Expression []Term
+
+// Multiply returns product of expressions, powers of the same units are summed
+func (e Expression) Multiply(f Expression) Expression {
+	result := append(Expression{}, e...)
+
+	for _, term := range f {
+		found := false
+		for i := range result {
+			if result[i].Unit.Name == term.Unit.Name {
+				result[i].Power += term.Power
+				found = true
+			}
+		}
+
+		if !found {
+			result = append(result, term)
+		}
+	}
+
+	terms := Expression{}
+	for _, term := range result {
+		if term.Power != 0 {
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// Power returns expression raised to integer power
+func (e Expression) Power(n int) Expression {
+	result := Expression{}
+	if n == 0 {
+		return result
+	}
+
+	for _, term := range e {
+		result = append(result, Term{term.Unit, term.Power * n})
+	}
+
+	return result
+}
+
+// Root returns n-th root of expression, it is false if powers of units are not multiples of n
+func (e Expression) Root(n int) (Expression, bool) {
+	result := Expression{}
+	for _, term := range e {
+		if term.Power%n != 0 {
+			return nil, false
+		}
+		result = append(result, Term{term.Unit, term.Power / n})
+	}
+
+	return result, true
+}
+
+// Dimension returns dimension of product of units
+func (e Expression) Dimension() Dimension {
+	dimension := Dimension{}
+	for _, term := range e {
+		for i, exponent := range term.Unit.Dimension {
+			dimension[i] += exponent * term.Power
+		}
+	}
+
+	return dimension
+}
+
+// Factor returns value of product of units in SI base units
+func (e Expression) Factor() float64 {
+	factor := 1.0
+	for _, term := range e {
+		factor *= math.Pow(term.Unit.Factor, float64(term.Power))
+	}
+
+	return factor
+}
+
+// offset returns offset of expression consisting of a single unit, offsets of products of units are ignored,
+// e.g. degC/s is a rate of change of temperature
+func (e Expression) offset() float64 {
+	if len(e) != 1 || e[0].Power != 1 {
+		return 0
+	}
+
+	return e[0].Unit.Offset
+}
+
+// String writes units of numerator and denominator separated with /, e.g. kg*m/s^2
+func (e Expression) String() string {
+	names := make([]string, len(e))
+	powers := make([]int, len(e))
+	for i, term := range e {
+		names[i] = term.Unit.Name
+		powers[i] = term.Power
+	}
+
+	return product(names, powers)
+}
+
+// product writes names raised to powers with positive powers in numerator and negative in denominator
+func product(names []string, powers []int) string {
+	numerator, denominator := []string{}, []string{}
+	for i, name := range names {
+		switch {
+		case powers[i] == 1:
+			numerator = append(numerator, name)
+		case powers[i] > 1:
+			numerator = append(numerator, fmt.Sprintf("%s^%d", name, powers[i]))
+		case powers[i] == -1:
+			denominator = append(denominator, name)
+		case powers[i] < -1:
+			denominator = append(denominator, fmt.Sprintf("%s^%d", name, -powers[i]))
+		}
+	}
+
+	written := strings.Join(numerator, "*")
+	switch {
+	case len(denominator) == 0:
+		return written
+	case len(numerator) == 0:
+		written = "1"
+	}
+
+	if len(denominator) > 1 {
+		return fmt.Sprintf("%s/(%s)", written, strings.Join(denominator, "*"))
+	}
+
+	return fmt.Sprintf("%s/%s", written, denominator[0])
+}
+
+// Scale returns value of quantity in units from written in units to, offsets are not applied,
+// so differences of temperatures are scaled, e.g. 9 degF is 5 degC
+func Scale(value float64, from, to Expression) (float64, error) {
+	if err := compatible(from, to); err != nil {
+		return 0, err
+	}
+
+	return value * from.Factor() / to.Factor(), nil
+}
+
+// Convert returns value of quantity in units from written in units to, offsets of temperatures are applied,
+// e.g. 212 degF is 100 degC
+func Convert(value float64, from, to Expression) (float64, error) {
+	if err := compatible(from, to); err != nil {
+		return 0, err
+	}
+
+	return (value*from.Factor() + from.offset() - to.offset()) / to.Factor(), nil
+}
+
+func compatible(from, to Expression) error {
+	if from.Dimension() != to.Dimension() {
+		return errors.NewCalculationError(fmt.Sprintf("cannot convert %s to %s, %s and %s are different dimensions", from, to, from.Dimension(), to.Dimension()))
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package units
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/mateuszkrasucki/calculator/pkg/errors"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name           string
+		expectedFactor float64
+		expectedOk     bool
+	}{
+		{"m", 1, true},
+		{"km", 1e3, true},
+		{"kg", 1, true},
+		{"dam", 10, true},
+		{"min", 60, true},
+		{"mi", 1609.344, true},
+		{"us", 1e-6, true},
+		{"kmph", 0, false},
+		{"kft", 0, false},
+		{"x", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit, ok := Lookup(tt.name)
+			if ok != tt.expectedOk {
+				t.Fatalf("expected %s to be found: %t, got %t", tt.name, tt.expectedOk, ok)
+			}
+
+			if ok && (unit.Name != tt.name || math.Abs(unit.Factor-tt.expectedFactor) > 1e-12*tt.expectedFactor) {
+				t.Errorf("expected %s to have factor %g, got %s with factor %g", tt.name, tt.expectedFactor, unit.Name, unit.Factor)
+			}
+		})
+	}
+}
+
+func expression(names ...string) Expression {
+	e := Expression{}
+	for _, name := range names {
+		power := 1
+		if strings.HasPrefix(name, "/") {
+			name, power = name[1:], -1
+		}
+
+		unit, _ := Lookup(name)
+		e = e.Multiply(Expression{{unit, power}})
+	}
+
+	return e
+}
+
+func TestExpression(t *testing.T) {
+	tests := []struct {
+		name              string
+		expression        Expression
+		expectedString    string
+		expectedDimension string
+	}{
+		{"Single unit", expression("km"), "km", "length"},
+		{"Powers of the same unit", expression("m", "m", "/s", "/s"), "m^2/s^2", "length^2/time^2"},
+		{"Cancelled units", expression("m", "/m"), "", "dimensionless"},
+		{"Derived dimension", expression("kg", "m", "/s", "/s"), "kg*m/s^2", "force"},
+		{"Denominator only", expression("/s"), "1/s", "frequency"},
+		{"Product in denominator", expression("J", "/kg", "/K"), "J/(kg*K)", "length^2/(time^2*temperature)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s := tt.expression.String(); s != tt.expectedString {
+				t.Errorf("expected expression to be %s, got %s", tt.expectedString, s)
+			}
+
+			if d := tt.expression.Dimension().String(); d != tt.expectedDimension {
+				t.Errorf("expected dimension to be %s, got %s", tt.expectedDimension, d)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         float64
+		from          Expression
+		to            Expression
+		expected      float64
+		expectedError error
+	}{
+		{"Prefixed units", 5.3, expression("km"), expression("m"), 5300, nil},
+		{"Velocity", 60, expression("mph"), expression("km", "/h"), 96.56064, nil},
+		{"Temperature", 212, expression("degF"), expression("degC"), 100, nil},
+		{"Rate of temperature change", 9, expression("degF", "/s"), expression("degC", "/s"), 5, nil},
+		{"Different dimensions", 3, expression("m"), expression("s"), 0, errors.NewCalculationError("cannot convert m to s, length and time are different dimensions")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Convert(tt.value, tt.from, tt.to)
+
+			if (tt.expectedError != nil && err == nil) || (tt.expectedError == nil && err != nil) {
+				t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedError != nil {
+				if !strings.Contains(err.Error(), tt.expectedError.Error()) {
+					t.Fatalf("expected error to be %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("expected result to be %g, got %g", tt.expected, result)
+			}
+		})
+	}
+}